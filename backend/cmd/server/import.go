@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/vehiculos/backend/internal/config"
+	"github.com/vehiculos/backend/internal/importer"
+)
+
+// runImport ejecuta el subcomando import y devuelve el código de salida:
+// 0 si todas las filas se importaron, 1 si hubo filas con error o una falla general
+func runImport(cfg config.Config, args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "valida el archivo sin escribir en la base de datos")
+	format := fs.String("format", "", "csv o json (por defecto se deduce de la extensión)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Uso: server import [--dry-run] [--format csv|json] <archivo>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	path := fs.Arg(0)
+	if *format == "" {
+		*format = importer.FormatFromFilename(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Printf("Error al abrir archivo: %v", err)
+		return 1
+	}
+	defer file.Close()
+
+	st, err := openStores(cfg)
+	if err != nil {
+		log.Printf("Error al inicializar el almacenamiento: %v", err)
+		return 1
+	}
+	defer st.close()
+
+	result, err := importer.New(st.catalog, st.imports).Import(context.Background(), *format, file, *dryRun)
+	if err != nil {
+		log.Printf("Error al importar: %v", err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(result); err != nil {
+		log.Printf("Error al escribir resultado: %v", err)
+		return 1
+	}
+
+	if result.Failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/vehiculos/backend/internal/config"
+	"github.com/vehiculos/backend/internal/database"
+	"github.com/vehiculos/backend/internal/handlers"
+	"github.com/vehiculos/backend/internal/importer"
+	"github.com/vehiculos/backend/internal/suggest"
+	"github.com/vehiculos/backend/internal/synonyms"
+)
+
+func main() {
+	// El archivo .env es opcional; en producción las variables vienen del entorno
+	if err := godotenv.Load(); err != nil {
+		log.Println("No se encontró archivo .env, usando variables de entorno")
+	}
+
+	cfg := config.Load()
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			os.Exit(runMigrate(os.Args[2:]))
+		case "import":
+			os.Exit(runImport(cfg, os.Args[2:]))
+		}
+	}
+
+	st, err := openStores(cfg)
+	if err != nil {
+		log.Fatalf("Error al inicializar el almacenamiento: %v", err)
+	}
+	defer st.close()
+
+	searchLogger := database.NewSearchLogger(st.searches, cfg.SearchLogBuffer)
+
+	// Sin diccionario válido la búsqueda sigue funcionando, sólo que sin sinónimos
+	rewriter := synonyms.NewRewriter(cfg.SynonymsFile, st.catalog)
+	if rules, err := rewriter.Reload(context.Background()); err != nil {
+		log.Printf("Advertencia: diccionario de sinónimos no cargado: %v", err)
+	} else {
+		log.Printf("✓ Diccionario de sinónimos cargado (%d reglas)", rules)
+	}
+
+	// El índice de autocompletado se construye al iniciar; si falla, con la primera consulta
+	suggester := suggest.NewSuggester(st.suggestions, cfg.SuggestRefresh)
+	if idx, err := suggester.Refresh(context.Background()); err != nil {
+		log.Printf("Advertencia: índice de autocompletado no construido: %v", err)
+	} else {
+		log.Printf("✓ Índice de autocompletado construido (%d sugerencias)", idx.Len())
+	}
+
+	vehicleHandler := handlers.NewVehicleHandler(st.vehicles, st.catalog, searchLogger, rewriter)
+	userSearchHandler := handlers.NewUserSearchHandler(st.searches)
+	userPreferenceHandler := handlers.NewUserPreferenceHandler(st.preferences, st.catalog)
+	adminVehicleHandler := handlers.NewAdminVehicleHandler(st.vehicles)
+	adminImportHandler := handlers.NewAdminImportHandler(importer.New(st.catalog, st.imports))
+	adminSynonymHandler := handlers.NewAdminSynonymHandler(rewriter)
+	suggestHandler := handlers.NewSuggestHandler(suggester)
+	recommendationHandler := handlers.NewRecommendationHandler(st.vehicles)
+
+	if cfg.AdminAPIKey == "" {
+		log.Println("Advertencia: ADMIN_API_KEY no configurada, los endpoints /api/admin están deshabilitados")
+	}
+
+	srv := &http.Server{
+		Addr:              ":" + cfg.ServerPort,
+		Handler:           setupRouter(cfg, vehicleHandler, userSearchHandler, userPreferenceHandler, adminVehicleHandler, adminImportHandler, adminSynonymHandler, suggestHandler, recommendationHandler),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// SIGHUP recarga el diccionario de sinónimos sin reiniciar
+	go reloadOnHangup(ctx, rewriter)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("✓ Servidor escuchando en el puerto %s", cfg.ServerPort)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	// Si el servidor falla también se sigue al apagado, para vaciar el registro de búsquedas
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			log.Printf("Error en el servidor HTTP: %v", err)
+		}
+	case <-ctx.Done():
+	}
+
+	// Dejar de aceptar conexiones y esperar a que terminen las peticiones en curso
+	log.Println("Apagando servidor...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error al apagar el servidor: %v", err)
+	}
+	searchLogger.Close()
+	log.Println("✓ Servidor detenido")
+}
+
+// reloadOnHangup recarga el diccionario de sinónimos con cada SIGHUP hasta que ctx termine
+func reloadOnHangup(ctx context.Context, rewriter *synonyms.Rewriter) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			if rules, err := rewriter.Reload(ctx); err != nil {
+				log.Printf("Error al recargar el diccionario de sinónimos: %v", err)
+			} else {
+				log.Printf("✓ Diccionario de sinónimos recargado (%d reglas)", rules)
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vehiculos/backend/internal/database"
+	"github.com/vehiculos/backend/internal/migrate"
+	"github.com/vehiculos/backend/migrations"
+)
+
+const migrateUsage = `Uso: server migrate <comando>
+
+Comandos:
+  up          aplica todas las migraciones pendientes
+  down [n]    revierte las últimas n migraciones (por defecto 1)
+  status      muestra las migraciones aplicadas y pendientes`
+
+// runMigrate ejecuta el subcomando migrate y devuelve el código de salida
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	db, err := database.OpenPostgres()
+	if err != nil {
+		log.Printf("Error al inicializar la base de datos: %v", err)
+		return 1
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		log.Printf("Error al cargar migraciones: %v", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			log.Printf("Error al migrar: %v", err)
+			return 1
+		}
+		log.Printf("✓ %d migraciones aplicadas", applied)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				fmt.Fprintln(os.Stderr, "n debe ser un entero positivo")
+				return 2
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			log.Printf("Error al revertir: %v", err)
+			return 1
+		}
+		log.Printf("✓ %d migraciones revertidas", reverted)
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Printf("Error al consultar migraciones: %v", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSIÓN\tNOMBRE\tESTADO")
+		for _, s := range statuses {
+			state := "pendiente"
+			if s.Applied {
+				state = "aplicada " + s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, state)
+		}
+		w.Flush()
+
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	return 0
+}
+
+// migrateUp aplica las migraciones pendientes al arrancar el servidor (MIGRATE_ON_START)
+func migrateUp(db *sql.DB) error {
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		return fmt.Errorf("error al cargar migraciones: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		return fmt.Errorf("error al migrar: %w", err)
+	}
+	log.Printf("✓ Esquema al día (%d migraciones aplicadas)", applied)
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vehiculos/backend/internal/config"
+	"github.com/vehiculos/backend/internal/handlers"
+	"github.com/vehiculos/backend/internal/middleware"
+)
+
+// setupRouter registra todas las rutas de la API bajo /api
+func setupRouter(
+	cfg config.Config,
+	vehicleHandler *handlers.VehicleHandler,
+	userSearchHandler *handlers.UserSearchHandler,
+	userPreferenceHandler *handlers.UserPreferenceHandler,
+	adminVehicleHandler *handlers.AdminVehicleHandler,
+	adminImportHandler *handlers.AdminImportHandler,
+	adminSynonymHandler *handlers.AdminSynonymHandler,
+	suggestHandler *handlers.SuggestHandler,
+	recommendationHandler *handlers.RecommendationHandler,
+) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Logger(), gin.Recovery())
+	router.Use(middleware.CORS(cfg.AllowedOrigins))
+
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	api := router.Group("/api")
+	{
+		api.GET("/vehicles", vehicleHandler.GetVehicles)
+		api.GET("/vehicles/search", vehicleHandler.SearchVehicles)
+		api.POST("/vehicles/search", vehicleHandler.SearchVehicles)
+		api.GET("/vehicles/export", vehicleHandler.ExportVehicles)
+		api.GET("/vehicles/:id", vehicleHandler.GetVehicleByID)
+		api.GET("/vehicles/:id/images", vehicleHandler.GetVehicleImages)
+		api.GET("/vehicles/:id/similar", vehicleHandler.GetSimilarVehicles)
+		api.POST("/vehicles/compare", vehicleHandler.CompareVehicles)
+
+		api.GET("/brands", vehicleHandler.GetBrands)
+		api.GET("/vehicle-types", vehicleHandler.GetVehicleTypes)
+		api.GET("/fuel-types", vehicleHandler.GetFuelTypes)
+		api.GET("/transmissions", vehicleHandler.GetTransmissions)
+		api.GET("/features", vehicleHandler.GetFeatures)
+		api.GET("/filters", vehicleHandler.GetFilters)
+
+		api.GET("/search/suggest", suggestHandler.Suggest)
+		api.POST("/recommendations", recommendationHandler.GetRecommendations)
+
+		api.POST("/user-searches", userSearchHandler.CreateUserSearch)
+		api.PATCH("/user-searches/:id/selection", userSearchHandler.SetSelectedVehicle)
+
+		api.POST("/user-preferences", userPreferenceHandler.CreatePreference)
+		api.GET("/user-preferences/:session_id", userPreferenceHandler.GetPreferences)
+		api.GET("/user-preferences/:session_id/filter", userPreferenceHandler.GetPreferenceFilter)
+		api.DELETE("/user-preferences/:session_id", userPreferenceHandler.DeleteSessionPreferences)
+		api.DELETE("/user-preferences/:session_id/:id", userPreferenceHandler.DeletePreference)
+	}
+
+	admin := router.Group("/api/admin", middleware.AdminAuth(cfg.AdminAPIKey))
+	{
+		admin.POST("/vehicles", adminVehicleHandler.CreateVehicle)
+		admin.POST("/vehicles/import", adminImportHandler.ImportVehicles)
+		admin.PUT("/vehicles/:id", adminVehicleHandler.UpdateVehicle)
+		admin.PATCH("/vehicles/:id", adminVehicleHandler.PatchVehicle)
+		admin.DELETE("/vehicles/:id", adminVehicleHandler.DeleteVehicle)
+
+		admin.POST("/vehicles/:id/images", adminVehicleHandler.AddVehicleImage)
+		admin.PUT("/vehicles/:id/images/order", adminVehicleHandler.ReorderVehicleImages)
+		admin.DELETE("/vehicles/:id/images/:image_id", adminVehicleHandler.DeleteVehicleImage)
+
+		admin.PUT("/vehicles/:id/specs", adminVehicleHandler.UpsertVehicleSpecs)
+		admin.DELETE("/vehicles/:id/specs/:name", adminVehicleHandler.DeleteVehicleSpec)
+
+		admin.POST("/synonyms/reload", adminSynonymHandler.ReloadSynonyms)
+	}
+
+	return router
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/vehiculos/backend/internal/config"
+	"github.com/vehiculos/backend/internal/database"
+	"github.com/vehiculos/backend/internal/database/memory"
+)
+
+// stores agrupa los backends de datos que usan los handlers
+type stores struct {
+	vehicles    database.VehicleStore
+	catalog     database.CatalogStore
+	imports     database.ImportStore
+	suggestions database.SuggestionStore
+	searches    database.UserSearchStore
+	preferences database.UserPreferenceStore
+	close       func()
+}
+
+// openStores selecciona el backend según STORE_DRIVER: postgres (por defecto) o memory,
+// que carga el catálogo desde SEED_FILE y no requiere PostgreSQL ni Redis
+func openStores(cfg config.Config) (*stores, error) {
+	switch cfg.StoreDriver {
+	case "memory":
+		store, err := memory.LoadFile(cfg.SeedFile)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("✓ Catálogo en memoria cargado desde %s", cfg.SeedFile)
+		return &stores{
+			vehicles:    store,
+			catalog:     store,
+			imports:     store,
+			suggestions: store,
+			searches:    store,
+			preferences: store,
+			close:       func() {},
+		}, nil
+
+	case "postgres":
+		db, err := database.NewConnection()
+		if err != nil {
+			return nil, err
+		}
+		if cfg.MigrateOnStart {
+			if err := migrateUp(db.SQL); err != nil {
+				db.Close()
+				return nil, err
+			}
+		}
+		vehicleRepo := database.NewVehicleRepository(db)
+		return &stores{
+			vehicles:    vehicleRepo,
+			catalog:     vehicleRepo,
+			imports:     vehicleRepo,
+			suggestions: vehicleRepo,
+			searches:    database.NewUserSearchRepository(db),
+			preferences: database.NewUserPreferenceRepository(db),
+			close:       db.Close,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("STORE_DRIVER desconocido: %q (use postgres o memory)", cfg.StoreDriver)
+}
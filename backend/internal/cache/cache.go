@@ -0,0 +1,23 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMiss indica que la llave no existe o ya expiró
+var ErrMiss = errors.New("cache: llave no encontrada")
+
+// Cache es el almacenamiento de caché que usan los repositorios. Las etiquetas permiten
+// invalidar de una vez todas las llaves que dependen de una entidad.
+type Cache interface {
+	// Get devuelve el valor de key o ErrMiss
+	Get(ctx context.Context, key string) (string, error)
+	// Set guarda key durante ttl y la registra bajo cada etiqueta
+	Set(ctx context.Context, key, value string, ttl time.Duration, tags ...string) error
+	// InvalidateTags elimina todas las llaves registradas bajo las etiquetas
+	InvalidateTags(ctx context.Context, tags ...string) error
+	// Close libera las conexiones del backend
+	Close() error
+}
@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// Fallback usa primary (Redis) mientras responda y cambia a secondary (memoria) cuando
+// falla: tras threshold errores seguidos el circuito se abre durante cooldown y todas las
+// operaciones van a secondary. Pasado el cooldown se vuelve a probar primary. Los errores de
+// contexto (petición cancelada o vencida) no cuentan como fallos de primary.
+//
+// Las invalidaciones que no pudieron aplicarse en primary se guardan y se reenvían antes de
+// volver a usarlo; mientras el reenvío no se complete las operaciones siguen en secondary,
+// para no servir datos obsoletos escritos antes de la caída.
+type Fallback struct {
+	primary   Cache
+	secondary Cache
+	threshold int
+	cooldown  time.Duration
+
+	mu          sync.Mutex
+	failures    int
+	open        bool
+	openUntil   time.Time
+	replaying   bool
+	pendingTags map[string]struct{}
+}
+
+func NewFallback(primary, secondary Cache, threshold int, cooldown time.Duration) *Fallback {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &Fallback{
+		primary:     primary,
+		secondary:   secondary,
+		threshold:   threshold,
+		cooldown:    cooldown,
+		pendingTags: make(map[string]struct{}),
+	}
+}
+
+func (c *Fallback) Get(ctx context.Context, key string) (string, error) {
+	if c.usePrimary(ctx) {
+		value, err := c.primary.Get(ctx, key)
+		c.record(err)
+		if err == nil || errors.Is(err, ErrMiss) {
+			return value, err
+		}
+	}
+	return c.secondary.Get(ctx, key)
+}
+
+func (c *Fallback) Set(ctx context.Context, key, value string, ttl time.Duration, tags ...string) error {
+	if c.usePrimary(ctx) {
+		err := c.primary.Set(ctx, key, value, ttl, tags...)
+		c.record(err)
+		if err == nil {
+			return nil
+		}
+	}
+	return c.secondary.Set(ctx, key, value, ttl, tags...)
+}
+
+func (c *Fallback) InvalidateTags(ctx context.Context, tags ...string) error {
+	// La memoria se invalida siempre para no servir datos viejos en la próxima caída
+	if err := c.secondary.InvalidateTags(ctx, tags...); err != nil {
+		return err
+	}
+
+	if c.usePrimary(ctx) {
+		err := c.primary.InvalidateTags(ctx, tags...)
+		c.record(err)
+		if err == nil {
+			return nil
+		}
+	}
+
+	c.mu.Lock()
+	for _, tag := range tags {
+		c.pendingTags[tag] = struct{}{}
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Fallback) Close() error {
+	errPrimary := c.primary.Close()
+	errSecondary := c.secondary.Close()
+	if errPrimary != nil {
+		return errPrimary
+	}
+	return errSecondary
+}
+
+// usePrimary indica si la operación puede ir a primary: el circuito debe permitirlo y las
+// invalidaciones pendientes deben haberse reenviado. Sólo una operación a la vez hace el
+// reenvío; las demás usan secondary mientras tanto.
+func (c *Fallback) usePrimary(ctx context.Context) bool {
+	c.mu.Lock()
+	if (c.open && time.Now().Before(c.openUntil)) || c.replaying {
+		c.mu.Unlock()
+		return false
+	}
+	if len(c.pendingTags) == 0 {
+		c.mu.Unlock()
+		return true
+	}
+	c.replaying = true
+	tags := keysOf(c.pendingTags)
+	c.pendingTags = make(map[string]struct{})
+	c.mu.Unlock()
+
+	err := c.replay(ctx, tags)
+
+	c.mu.Lock()
+	c.replaying = false
+	c.mu.Unlock()
+	c.record(err)
+	return err == nil
+}
+
+// record actualiza el estado del circuito con el resultado de una operación en primary
+func (c *Fallback) record(err error) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil && !errors.Is(err, ErrMiss) {
+		c.failures++
+		if c.open || c.failures >= c.threshold {
+			if !c.open {
+				log.Printf("Advertencia: caché principal no disponible, usando caché en memoria: %v", err)
+			}
+			c.open = true
+			c.openUntil = time.Now().Add(c.cooldown)
+		}
+		return
+	}
+
+	if c.open {
+		log.Println("✓ Caché principal recuperada")
+	}
+	c.failures = 0
+	c.open = false
+}
+
+// replay reenvía a primary las etiquetas pendientes; si vuelve a fallar se conservan y se
+// devuelve el error. No depende de la cancelación de la petición que lo dispara porque las
+// invalidaciones no son suyas.
+func (c *Fallback) replay(ctx context.Context, tags []string) error {
+	err := c.primary.InvalidateTags(context.WithoutCancel(ctx), tags...)
+	if err == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	for _, tag := range tags {
+		c.pendingTags[tag] = struct{}{}
+	}
+	c.mu.Unlock()
+	return err
+}
+
+func keysOf(set map[string]struct{}) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,254 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+var errDown = errors.New("primary caído")
+
+// flakyCache es una Cache en memoria que falla a voluntad y cuenta las llamadas que recibe
+type flakyCache struct {
+	*Memory
+
+	mu          sync.Mutex
+	down        bool
+	calls       int
+	invalidated [][]string
+}
+
+func newFlakyCache() *flakyCache {
+	return &flakyCache{Memory: NewMemory(100)}
+}
+
+func (c *flakyCache) setDown(down bool) {
+	c.mu.Lock()
+	c.down = down
+	c.mu.Unlock()
+}
+
+func (c *flakyCache) callCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+// fail registra la llamada e indica si debe fallar
+func (c *flakyCache) fail() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	return c.down
+}
+
+func (c *flakyCache) Get(ctx context.Context, key string) (string, error) {
+	if c.fail() {
+		return "", errDown
+	}
+	return c.Memory.Get(ctx, key)
+}
+
+func (c *flakyCache) Set(ctx context.Context, key, value string, ttl time.Duration, tags ...string) error {
+	if c.fail() {
+		return errDown
+	}
+	return c.Memory.Set(ctx, key, value, ttl, tags...)
+}
+
+func (c *flakyCache) InvalidateTags(ctx context.Context, tags ...string) error {
+	if c.fail() {
+		return errDown
+	}
+	c.mu.Lock()
+	c.invalidated = append(c.invalidated, tags)
+	c.mu.Unlock()
+	return c.Memory.InvalidateTags(ctx, tags...)
+}
+
+func TestFallbackOpensCircuitAfterThreshold(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := newFlakyCache(), NewMemory(100)
+	c := NewFallback(primary, secondary, 2, time.Hour)
+
+	primary.setDown(true)
+	if err := c.Set(ctx, "k", "memoria", time.Minute); err != nil {
+		t.Fatalf("Set con primary caído: %v", err)
+	}
+	if _, err := c.Get(ctx, "k"); err != nil {
+		t.Fatalf("Get con primary caído: %v", err)
+	}
+	if got := primary.callCount(); got != 2 {
+		t.Fatalf("primary recibió %d llamadas antes de abrir el circuito, want 2", got)
+	}
+
+	// Con el circuito abierto todo va a secondary sin tocar primary
+	for i := 0; i < 5; i++ {
+		value, err := c.Get(ctx, "k")
+		if err != nil || value != "memoria" {
+			t.Fatalf("Get = %q, %v; want valor de secondary", value, err)
+		}
+	}
+	if got := primary.callCount(); got != 2 {
+		t.Errorf("primary recibió %d llamadas con el circuito abierto, want 2", got)
+	}
+}
+
+func TestFallbackBelowThresholdKeepsPrimary(t *testing.T) {
+	ctx := context.Background()
+	primary := newFlakyCache()
+	c := NewFallback(primary, NewMemory(100), 3, time.Hour)
+
+	primary.setDown(true)
+	c.Get(ctx, "k")
+	c.Get(ctx, "k")
+	primary.setDown(false)
+	c.Get(ctx, "k") // un éxito reinicia la cuenta de fallos
+	primary.setDown(true)
+	c.Get(ctx, "k")
+	c.Get(ctx, "k")
+
+	if got := primary.callCount(); got != 5 {
+		t.Fatalf("primary recibió %d llamadas, want 5", got)
+	}
+	c.Get(ctx, "k")
+	if got := primary.callCount(); got != 6 {
+		t.Errorf("el circuito se abrió antes de %d fallos seguidos", 3)
+	}
+}
+
+func TestFallbackRetriesPrimaryAfterCooldown(t *testing.T) {
+	ctx := context.Background()
+	primary := newFlakyCache()
+	c := NewFallback(primary, NewMemory(100), 1, 20*time.Millisecond)
+
+	primary.setDown(true)
+	c.Get(ctx, "k")
+	c.Get(ctx, "k")
+	if got := primary.callCount(); got != 1 {
+		t.Fatalf("primary recibió %d llamadas, want 1", got)
+	}
+
+	primary.setDown(false)
+	time.Sleep(30 * time.Millisecond)
+	if err := c.Set(ctx, "k", "redis", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := primary.Memory.Get(ctx, "k"); err != nil || value != "redis" {
+		t.Errorf("tras el cooldown Set no llegó a primary: %q, %v", value, err)
+	}
+}
+
+func TestFallbackReplaysPendingInvalidations(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := newFlakyCache(), NewMemory(100)
+	c := NewFallback(primary, secondary, 1, 10*time.Millisecond)
+
+	// Datos cacheados en ambos niveles antes de la caída
+	primary.Memory.Set(ctx, "vehicle:1", "v1", time.Minute, "vehicle:1")
+	primary.Memory.Set(ctx, "search:abc", "s", time.Minute, "search")
+	secondary.Set(ctx, "vehicle:1", "v1", time.Minute, "vehicle:1")
+
+	primary.setDown(true)
+	if err := c.InvalidateTags(ctx, "vehicle:1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.InvalidateTags(ctx, "search"); err != nil {
+		t.Fatal(err)
+	}
+
+	// secondary se invalida de inmediato; primary conserva los datos obsoletos
+	if _, err := secondary.Get(ctx, "vehicle:1"); !errors.Is(err, ErrMiss) {
+		t.Errorf("secondary no se invalidó: %v", err)
+	}
+	if _, err := primary.Memory.Get(ctx, "vehicle:1"); err != nil {
+		t.Fatalf("primary perdió datos durante la caída: %v", err)
+	}
+
+	// La primera lectura tras la recuperación reenvía las invalidaciones antes de leer, así
+	// que no ve el dato invalidado durante la caída
+	primary.setDown(false)
+	time.Sleep(20 * time.Millisecond)
+	if value, err := c.Get(ctx, "vehicle:1"); !errors.Is(err, ErrMiss) {
+		t.Fatalf("Get = %q, %v; want ErrMiss", value, err)
+	}
+	if _, err := primary.Memory.Get(ctx, "vehicle:1"); !errors.Is(err, ErrMiss) {
+		t.Errorf("la etiqueta pendiente no se reenvió a primary: %v", err)
+	}
+	if _, err := primary.Memory.Get(ctx, "search:abc"); !errors.Is(err, ErrMiss) {
+		t.Errorf("la segunda etiqueta pendiente no se reenvió a primary: %v", err)
+	}
+
+	// Las invalidaciones pendientes se reenvían una sola vez
+	c.Get(ctx, "otra")
+	if len(primary.invalidated) != 1 || len(primary.invalidated[0]) != 2 {
+		t.Errorf("reenvíos = %v, want uno con las dos etiquetas", primary.invalidated)
+	}
+}
+
+func TestFallbackKeepsInvalidationsWhenReplayFails(t *testing.T) {
+	ctx := context.Background()
+	primary, secondary := newFlakyCache(), NewMemory(100)
+	c := NewFallback(primary, secondary, 1, 10*time.Millisecond)
+
+	primary.Memory.Set(ctx, "catalog:v", "viejo", time.Minute, "catalog")
+	secondary.Set(ctx, "otra", "memoria", time.Minute)
+
+	primary.setDown(true)
+	c.InvalidateTags(ctx, "catalog")
+
+	// Pasado el cooldown el reenvío vuelve a fallar: la lectura sigue en secondary y la
+	// etiqueta se conserva
+	time.Sleep(20 * time.Millisecond)
+	if value, err := c.Get(ctx, "otra"); err != nil || value != "memoria" {
+		t.Fatalf("Get = %q, %v; want valor de secondary", value, err)
+	}
+	if len(primary.invalidated) != 0 {
+		t.Fatalf("el reenvío no debió aplicarse con primary caído")
+	}
+	c.mu.Lock()
+	_, pending := c.pendingTags["catalog"]
+	open := c.open
+	c.mu.Unlock()
+	if !pending || !open {
+		t.Fatalf("tras un reenvío fallido: pendiente = %v, circuito abierto = %v; want ambos", pending, open)
+	}
+
+	primary.setDown(false)
+	time.Sleep(20 * time.Millisecond)
+	if _, err := c.Get(ctx, "catalog:v"); !errors.Is(err, ErrMiss) {
+		t.Errorf("Get = %v, want ErrMiss tras reenviar la etiqueta", err)
+	}
+	if len(primary.invalidated) != 1 || primary.invalidated[0][0] != "catalog" {
+		t.Errorf("invalidaciones en primary = %v, want [[catalog]]", primary.invalidated)
+	}
+}
+
+// canceledCache responde siempre con el error de contexto de la petición
+type canceledCache struct {
+	*Memory
+}
+
+func (c canceledCache) Get(ctx context.Context, key string) (string, error) {
+	return "", ctx.Err()
+}
+
+func TestFallbackIgnoresContextErrors(t *testing.T) {
+	c := NewFallback(canceledCache{NewMemory(10)}, NewMemory(10), 1, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.Get(ctx, "k")
+
+	deadline, cancelDeadline := context.WithTimeout(context.Background(), -time.Second)
+	defer cancelDeadline()
+	c.Get(deadline, "k")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.open || c.failures != 0 {
+		t.Errorf("las peticiones canceladas abrieron el circuito: fallos = %d, abierto = %v", c.failures, c.open)
+	}
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemory(2)
+	c.Set(ctx, "a", "1", 0)
+	c.Set(ctx, "b", "2", 0)
+	c.Get(ctx, "a") // b pasa a ser la menos usada
+	c.Set(ctx, "c", "3", 0)
+
+	if _, err := c.Get(ctx, "b"); !errors.Is(err, ErrMiss) {
+		t.Errorf("b debió desalojarse: %v", err)
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, err := c.Get(ctx, key); err != nil {
+			t.Errorf("%s: %v", key, err)
+		}
+	}
+}
+
+func TestMemoryExpires(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemory(10)
+	c.Set(ctx, "corta", "1", 10*time.Millisecond)
+	c.Set(ctx, "sin ttl", "2", 0)
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := c.Get(ctx, "corta"); !errors.Is(err, ErrMiss) {
+		t.Errorf("la llave debió expirar: %v", err)
+	}
+	if _, err := c.Get(ctx, "sin ttl"); err != nil {
+		t.Errorf("una llave sin ttl no expira: %v", err)
+	}
+}
+
+func TestMemoryInvalidation(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemory(10)
+	c.Set(ctx, "vehicle:1", "v1", 0, "vehicle:1")
+	c.Set(ctx, "search:a", "s", 0, "search", "vehicle:1")
+	c.Set(ctx, "catalog:brands", "b", 0, "catalog")
+	// Reescribir una llave con otras etiquetas la saca de las anteriores
+	c.Set(ctx, "vehicle:1", "v1", 0, "otra")
+
+	if err := c.InvalidateTags(ctx, "vehicle:1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(ctx, "search:a"); !errors.Is(err, ErrMiss) {
+		t.Errorf("search:a debió invalidarse: %v", err)
+	}
+	if _, err := c.Get(ctx, "vehicle:1"); err != nil {
+		t.Errorf("vehicle:1 ya no tenía la etiqueta: %v", err)
+	}
+	if _, err := c.Get(ctx, "catalog:brands"); err != nil {
+		t.Errorf("catalog:brands no dependía de vehicle:1: %v", err)
+	}
+}
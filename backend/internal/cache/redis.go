@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cada etiqueta se guarda en la llave "tags:<nombre>"
+const (
+	tagKeyPrefix  = "tags:"
+	tagTTL        = 24 * time.Hour
+	scanBatchSize = 500
+)
+
+// Redis implementa Cache sobre Redis. Cada etiqueta es un sorted set con las llaves que
+// dependen de ella, puntuadas por su vencimiento; cada Set recorta las que ya vencieron para
+// que la etiqueta no crezca sin límite aunque nunca se invalide.
+type Redis struct {
+	client *redis.Client
+}
+
+func NewRedis(client *redis.Client) *Redis {
+	return &Redis{client: client}
+}
+
+func (c *Redis) Get(ctx context.Context, key string) (string, error) {
+	value, err := c.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrMiss
+	}
+	return value, err
+}
+
+func (c *Redis) Set(ctx context.Context, key, value string, ttl time.Duration, tags ...string) error {
+	if len(tags) == 0 {
+		return c.client.Set(ctx, key, value, ttl).Err()
+	}
+
+	now := time.Now()
+	expired := "(" + strconv.FormatInt(now.UnixMilli(), 10)
+	member := redis.Z{Score: tagScore(now, ttl), Member: key}
+
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, value, ttl)
+		for _, tag := range tags {
+			tagKey := tagKeyPrefix + tag
+			pipe.ZAdd(ctx, tagKey, member)
+			pipe.ZRemRangeByScore(ctx, tagKey, "-inf", expired)
+			pipe.Expire(ctx, tagKey, tagTTL)
+		}
+		return nil
+	})
+	return err
+}
+
+// tagScore es el vencimiento de la llave en milisegundos; sin ttl la llave no vence y nunca
+// se recorta
+func tagScore(now time.Time, ttl time.Duration) float64 {
+	if ttl <= 0 {
+		return math.Inf(1)
+	}
+	return float64(now.Add(ttl).UnixMilli())
+}
+
+func (c *Redis) InvalidateTags(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		tagKey := tagKeyPrefix + tag
+		iter := c.client.ZScan(ctx, tagKey, 0, "", scanBatchSize).Iterator()
+		if err := c.unlinkMembers(ctx, iter, tagKey); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Redis) Close() error {
+	return c.client.Close()
+}
+
+// Ping comprueba que Redis responda
+func (c *Redis) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// unlinkMembers elimina en lotes las llaves de la etiqueta y al final la etiqueta misma. El
+// iterador de ZSCAN alterna miembro y puntaje; los puntajes se descartan.
+func (c *Redis) unlinkMembers(ctx context.Context, iter *redis.ScanIterator, tagKey string) error {
+	batch := make([]string, 0, scanBatchSize)
+	for i := 0; iter.Next(ctx); i++ {
+		if i%2 == 1 {
+			continue
+		}
+		batch = append(batch, iter.Val())
+		if len(batch) == scanBatchSize {
+			if err := c.client.Unlink(ctx, batch...).Err(); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	return c.client.Unlink(ctx, append(batch, tagKey)...).Err()
+}
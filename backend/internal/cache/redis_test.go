@@ -0,0 +1,18 @@
+package cache
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTagScore(t *testing.T) {
+	now := time.UnixMilli(1_700_000_000_000)
+	if got, want := tagScore(now, time.Minute), float64(1_700_000_060_000); got != want {
+		t.Errorf("tagScore con ttl = %v, want %v", got, want)
+	}
+	// Una llave sin vencimiento nunca queda por debajo del recorte
+	if got := tagScore(now, 0); !math.IsInf(got, 1) {
+		t.Errorf("tagScore sin ttl = %v, want +Inf", got)
+	}
+}
@@ -0,0 +1,165 @@
+package compare
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// MaxVehicles es el número máximo de vehículos que se pueden comparar a la vez
+const MaxVehicles = 4
+
+// attribute describe un atributo numérico comparable y si un valor mayor es mejor
+type attribute struct {
+	name           string
+	higherIsBetter bool
+	value          func(v models.Vehicle) float64
+}
+
+var attributes = []attribute{
+	{"price", false, func(v models.Vehicle) float64 { return v.Price }},
+	{"horsepower", true, func(v models.Vehicle) float64 { return float64(v.Horsepower) }},
+	{"fuel_economy", true, func(v models.Vehicle) float64 { return v.FuelEconomy }},
+	{"cargo_space", true, func(v models.Vehicle) float64 { return v.CargoSpace }},
+	{"safety_rating", true, func(v models.Vehicle) float64 { return v.SafetyRating }},
+}
+
+// AttributeValue es el valor de un atributo para un vehículo concreto
+type AttributeValue struct {
+	VehicleID int     `json:"vehicle_id"`
+	Value     float64 `json:"value"`
+	Best      bool    `json:"best"`
+	Worst     bool    `json:"worst"`
+}
+
+// AttributeComparison agrupa los valores de un atributo para todos los vehículos
+type AttributeComparison struct {
+	Attribute      string           `json:"attribute"`
+	HigherIsBetter bool             `json:"higher_is_better"`
+	Values         []AttributeValue `json:"values"`
+}
+
+// FeatureComparison indica qué características comparten todos y cuáles son exclusivas
+type FeatureComparison struct {
+	Shared []string         `json:"shared"`
+	Unique map[int][]string `json:"unique"`
+}
+
+// Matrix es la matriz de comparación normalizada que consume ComparePage
+type Matrix struct {
+	Attributes []AttributeComparison `json:"attributes"`
+	Features   FeatureComparison     `json:"features"`
+}
+
+// Build construye la matriz de comparación para los vehículos dados
+func Build(vehicles []models.Vehicle) Matrix {
+	matrix := Matrix{
+		Attributes: make([]AttributeComparison, 0, len(attributes)),
+		Features:   compareFeatures(vehicles),
+	}
+
+	for _, attr := range attributes {
+		matrix.Attributes = append(matrix.Attributes, compareAttribute(attr, vehicles))
+	}
+
+	return matrix
+}
+
+// compareAttribute marca el mejor y el peor valor. Un 0 es un dato desconocido (p. ej. el
+// rendimiento de un eléctrico o una calificación sin registrar) y no participa.
+func compareAttribute(attr attribute, vehicles []models.Vehicle) AttributeComparison {
+	comparison := AttributeComparison{
+		Attribute:      attr.name,
+		HigherIsBetter: attr.higherIsBetter,
+		Values:         make([]AttributeValue, len(vehicles)),
+	}
+
+	var lowest, highest float64
+	known := false
+	for i, v := range vehicles {
+		value := attr.value(v)
+		comparison.Values[i] = AttributeValue{VehicleID: v.ID, Value: value}
+		if value <= 0 {
+			continue
+		}
+		if !known || value < lowest {
+			lowest = value
+		}
+		if !known || value > highest {
+			highest = value
+		}
+		known = true
+	}
+
+	// Si todos los valores conocidos son iguales no hay mejor ni peor
+	if !known || lowest == highest {
+		return comparison
+	}
+
+	best, worst := highest, lowest
+	if !attr.higherIsBetter {
+		best, worst = lowest, highest
+	}
+	for i := range comparison.Values {
+		comparison.Values[i].Best = comparison.Values[i].Value == best
+		comparison.Values[i].Worst = comparison.Values[i].Value == worst
+	}
+
+	return comparison
+}
+
+// compareFeatures agrupa las características sin distinguir mayúsculas ni espacios sobrantes.
+// Las compartidas se nombran como aparecen en el primer vehículo; las exclusivas, como las
+// escribe su vehículo.
+func compareFeatures(vehicles []models.Vehicle) FeatureComparison {
+	result := FeatureComparison{
+		Shared: []string{},
+		Unique: make(map[int][]string, len(vehicles)),
+	}
+
+	// Contar en cuántos vehículos aparece cada característica
+	counts := make(map[string]int)
+	names := make(map[string]string)
+	for _, v := range vehicles {
+		seen := make(map[string]bool, len(v.Features))
+		for _, feature := range v.Features {
+			key := featureKey(feature)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			counts[key]++
+			if _, ok := names[key]; !ok {
+				names[key] = strings.TrimSpace(feature)
+			}
+		}
+	}
+
+	for key, count := range counts {
+		if count == len(vehicles) {
+			result.Shared = append(result.Shared, names[key])
+		}
+	}
+	sort.Strings(result.Shared)
+
+	for _, v := range vehicles {
+		unique := []string{}
+		seen := make(map[string]bool, len(v.Features))
+		for _, feature := range v.Features {
+			key := featureKey(feature)
+			if counts[key] == 1 && len(vehicles) > 1 && !seen[key] {
+				seen[key] = true
+				unique = append(unique, strings.TrimSpace(feature))
+			}
+		}
+		sort.Strings(unique)
+		result.Unique[v.ID] = unique
+	}
+
+	return result
+}
+
+func featureKey(feature string) string {
+	return strings.ToLower(strings.TrimSpace(feature))
+}
@@ -0,0 +1,88 @@
+package compare
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+func flags(c AttributeComparison) (best, worst []int) {
+	for _, v := range c.Values {
+		if v.Best {
+			best = append(best, v.VehicleID)
+		}
+		if v.Worst {
+			worst = append(worst, v.VehicleID)
+		}
+	}
+	return best, worst
+}
+
+func TestCompareAttribute(t *testing.T) {
+	economy := attributes[2]
+	price := attributes[0]
+	tests := []struct {
+		name     string
+		attr     attribute
+		vehicles []models.Vehicle
+		best     []int
+		worst    []int
+	}{
+		{
+			"mayor es mejor",
+			economy,
+			[]models.Vehicle{{ID: 1, FuelEconomy: 15}, {ID: 2, FuelEconomy: 20}, {ID: 3, FuelEconomy: 10}},
+			[]int{2}, []int{3},
+		},
+		{
+			"menor precio es mejor",
+			price,
+			[]models.Vehicle{{ID: 1, Price: 500000}, {ID: 2, Price: 300000}},
+			[]int{2}, []int{1},
+		},
+		{
+			// El eléctrico no registra rendimiento: no es el peor
+			"sin dato no participa",
+			economy,
+			[]models.Vehicle{{ID: 1, FuelEconomy: 15}, {ID: 2, FuelEconomy: 0}, {ID: 3, FuelEconomy: 12}},
+			[]int{1}, []int{3},
+		},
+		{
+			"un solo dato conocido",
+			economy,
+			[]models.Vehicle{{ID: 1, FuelEconomy: 15}, {ID: 2}},
+			nil, nil,
+		},
+		{
+			"todos iguales",
+			economy,
+			[]models.Vehicle{{ID: 1, FuelEconomy: 15}, {ID: 2, FuelEconomy: 15}},
+			nil, nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			best, worst := flags(compareAttribute(tt.attr, tt.vehicles))
+			if !reflect.DeepEqual(best, tt.best) || !reflect.DeepEqual(worst, tt.worst) {
+				t.Errorf("mejor = %v, peor = %v; want %v, %v", best, worst, tt.best, tt.worst)
+			}
+		})
+	}
+}
+
+func TestCompareFeaturesFoldsCase(t *testing.T) {
+	got := compareFeatures([]models.Vehicle{
+		{ID: 1, Features: []string{"Apple CarPlay", "Quemacocos", "GPS"}},
+		{ID: 2, Features: []string{"apple carplay ", "Cámara", "gps", "GPS"}},
+	})
+
+	want := FeatureComparison{
+		Shared: []string{"Apple CarPlay", "GPS"},
+		Unique: map[int][]string{1: {"Quemacocos"}, 2: {"Cámara"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("compareFeatures = %+v, want %+v", got, want)
+	}
+}
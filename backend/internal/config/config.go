@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config agrupa la configuración del servidor HTTP leída del entorno
+type Config struct {
+	ServerPort      string
+	ShutdownTimeout time.Duration
+	AllowedOrigins  []string
+	SearchLogBuffer int
+	AdminAPIKey     string
+	StoreDriver     string
+	SeedFile        string
+	MigrateOnStart  bool
+	SynonymsFile    string
+	SuggestRefresh  time.Duration
+}
+
+// Load construye la configuración a partir de las variables de entorno
+func Load() Config {
+	return Config{
+		ServerPort:      getEnv("SERVER_PORT", "8080"),
+		ShutdownTimeout: getDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
+		AllowedOrigins:  getList("CORS_ALLOWED_ORIGINS", []string{"*"}),
+		SearchLogBuffer: getInt("SEARCH_LOG_BUFFER", 1000),
+		AdminAPIKey:     os.Getenv("ADMIN_API_KEY"),
+		StoreDriver:     getEnv("STORE_DRIVER", "postgres"),
+		SeedFile:        getEnv("SEED_FILE", "seeds/catalog.json"),
+		MigrateOnStart:  getBool("MIGRATE_ON_START", false),
+		SynonymsFile:    getEnv("SYNONYMS_FILE", "config/synonyms.json"),
+		SuggestRefresh:  getDuration("SUGGEST_REFRESH_INTERVAL", 5*time.Second),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
+func getDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+func getList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
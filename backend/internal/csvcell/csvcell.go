@@ -0,0 +1,29 @@
+// Package csvcell define el formato de las celdas del CSV de catálogo. La exportación las
+// escribe y el importador las lee con las mismas reglas, de modo que un CSV exportado se
+// puede volver a importar sin cambios.
+package csvcell
+
+import "strings"
+
+// FeatureSeparator separa las características dentro de la columna features
+const FeatureSeparator = "|"
+
+// formulaEscape antecede a las celdas de texto que una hoja de cálculo interpretaría como
+// fórmula, para que al abrir un CSV exportado no se ejecute el contenido de la celda
+const formulaEscape = "'"
+
+// Escape neutraliza el texto que empieza con =, +, -, @, tabulador o retorno de carro
+func Escape(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return formulaEscape + value
+	}
+	return value
+}
+
+// Unescape revierte Escape; el texto que no se neutralizó queda igual
+func Unescape(value string) string {
+	if rest := strings.TrimPrefix(value, formulaEscape); rest != value && Escape(rest) == value {
+		return rest
+	}
+	return value
+}
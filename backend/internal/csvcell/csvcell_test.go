@@ -0,0 +1,26 @@
+package csvcell
+
+import "testing"
+
+func TestEscape(t *testing.T) {
+	tests := map[string]string{
+		"=1+1":         "'=1+1",
+		"+52 55":       "'+52 55",
+		"-Faros":       "'-Faros",
+		"@SUM(A1)":     "'@SUM(A1)",
+		"\tx":          "'\tx",
+		"Toyota":       "Toyota",
+		"'apostrofo":   "'apostrofo",
+		"":             "",
+		"precio = 100": "precio = 100",
+	}
+	for in, want := range tests {
+		got := Escape(in)
+		if got != want {
+			t.Errorf("Escape(%q) = %q, want %q", in, got, want)
+		}
+		if back := Unescape(got); back != in {
+			t.Errorf("Unescape(%q) = %q, want %q", got, back, in)
+		}
+	}
+}
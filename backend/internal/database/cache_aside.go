@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// cachePolicy define cuánto tiempo se sirve un valor como fresco y cuánto más como
+// obsoleto mientras se recalcula en segundo plano (stale-while-revalidate)
+type cachePolicy struct {
+	fresh time.Duration
+	stale time.Duration
+}
+
+var (
+	searchCachePolicy  = cachePolicy{fresh: time.Minute, stale: 5 * time.Minute}
+	catalogCachePolicy = cachePolicy{fresh: 10 * time.Minute, stale: time.Hour}
+)
+
+// loadTimeout limita las consultas que se ejecutan desacopladas de la petición original
+const loadTimeout = 10 * time.Second
+
+type cacheEnvelope[T any] struct {
+	Data       T         `json:"data"`
+	FreshUntil time.Time `json:"fresh_until"`
+}
+
+// cacheAside devuelve el valor cacheado en key o lo carga con load. Las cargas concurrentes
+// de la misma llave se agrupan en una sola consulta, y un valor obsoleto se sirve de
+// inmediato mientras una única goroutine lo refresca.
+func cacheAside[T any](ctx context.Context, r *VehicleRepository, key string, policy cachePolicy, tags []string, load func(context.Context) (T, error)) (T, error) {
+	if cached, err := r.cache.Get(ctx, key); err == nil {
+		var env cacheEnvelope[T]
+		if err := json.Unmarshal([]byte(cached), &env); err == nil {
+			if time.Now().After(env.FreshUntil) {
+				go func() {
+					if _, err := loadShared(context.Background(), r, key, policy, tags, load); err != nil {
+						log.Printf("Error al refrescar caché %s: %v", key, err)
+					}
+				}()
+			}
+			return env.Data, nil
+		}
+	}
+
+	return loadShared(ctx, r, key, policy, tags, load)
+}
+
+// loadShared ejecuta load una sola vez por llave aunque lleguen varias peticiones a la vez.
+// Si alguna etiqueta se invalida mientras load corre, el resultado se devuelve pero no se
+// guarda: podría reflejar el estado anterior a la escritura.
+func loadShared[T any](ctx context.Context, r *VehicleRepository, key string, policy cachePolicy, tags []string, load func(context.Context) (T, error)) (T, error) {
+	result, err, _ := r.group.Do(key, func() (interface{}, error) {
+		// La carga es compartida: no debe cancelarse porque la petición que la inició termine
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+
+		gens := r.gens.snapshot(tags)
+		value, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+		if r.gens.changed(tags, gens) {
+			return value, nil
+		}
+
+		env := cacheEnvelope[T]{Data: value, FreshUntil: time.Now().Add(policy.fresh)}
+		if data, err := json.Marshal(env); err == nil {
+			if err := r.cache.Set(loadCtx, key, string(data), policy.fresh+policy.stale, tags...); err != nil {
+				log.Printf("Error al guardar caché %s: %v", key, err)
+			}
+		}
+		return value, nil
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return result.(T), nil
+}
+
+// filterCacheKey genera una llave estable para el filtro: dos filtros equivalentes
+// (mismos IDs en distinto orden, misma búsqueda con otras mayúsculas) comparten llave
+func filterCacheKey(prefix string, filter models.SearchFilter) string {
+	return prefix + canonicalFilterHash(filter)
+}
+
+// canonicalFilterHash resume el filtro en forma canónica; lo comparten las llaves de caché
+// y los cursores
+func canonicalFilterHash(filter models.SearchFilter) string {
+	canonical := filter
+	canonical.BrandID = sortedInts(filter.BrandID)
+	canonical.TypeID = sortedInts(filter.TypeID)
+	canonical.FuelTypeID = sortedInts(filter.FuelTypeID)
+	canonical.TransmissionID = sortedInts(filter.TransmissionID)
+	canonical.Query = strings.ToLower(strings.Join(strings.Fields(filter.Query), " "))
+
+	canonical.Specs = append([]models.SpecFilter(nil), filter.Specs...)
+	sort.Slice(canonical.Specs, func(i, j int) bool {
+		a, b := canonical.Specs[i], canonical.Specs[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Operator != b.Operator {
+			return a.Operator < b.Operator
+		}
+		return a.Value < b.Value
+	})
+
+	data, _ := json.Marshal(canonical)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+func sortedInts(values []int) []int {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	return sorted
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vehiculos/backend/internal/cache"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+func TestCacheAsideCachesAndCoalesces(t *testing.T) {
+	ctx := context.Background()
+	r := &VehicleRepository{cache: cache.NewMemory(10)}
+
+	var loads int32
+	load := func(context.Context) (int, error) {
+		atomic.AddInt32(&loads, 1)
+		return 42, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		got, err := cacheAside(ctx, r, "k", searchCachePolicy, []string{TagSearch}, load)
+		if err != nil || got != 42 {
+			t.Fatalf("cacheAside = %v, %v; want 42", got, err)
+		}
+	}
+	if n := atomic.LoadInt32(&loads); n != 1 {
+		t.Errorf("load se ejecutó %d veces, want 1", n)
+	}
+
+	if err := r.invalidateTags(ctx, TagSearch); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.cache.Get(ctx, "k"); !errors.Is(err, cache.ErrMiss) {
+		t.Errorf("la etiqueta no invalidó la llave: %v", err)
+	}
+}
+
+func TestCacheAsideSkipsSetWhenInvalidatedDuringLoad(t *testing.T) {
+	ctx := context.Background()
+	r := &VehicleRepository{cache: cache.NewMemory(10)}
+
+	// Una escritura confirma e invalida mientras la carga aún lee el estado anterior
+	load := func(context.Context) (string, error) {
+		if err := r.invalidateTags(ctx, VehicleTag(1), TagSearch); err != nil {
+			t.Fatal(err)
+		}
+		return "viejo", nil
+	}
+
+	got, err := cacheAside(ctx, r, "search:k", searchCachePolicy, []string{TagSearch}, load)
+	if err != nil || got != "viejo" {
+		t.Fatalf("cacheAside = %q, %v", got, err)
+	}
+	if cached, err := r.cache.Get(ctx, "search:k"); !errors.Is(err, cache.ErrMiss) {
+		t.Errorf("se guardó un resultado invalidado durante la carga: %q, %v", cached, err)
+	}
+
+	// Invalidar otra etiqueta no impide guardar
+	other := func(context.Context) (string, error) {
+		r.invalidateTags(ctx, TagCatalog)
+		return "nuevo", nil
+	}
+	if _, err := cacheAside(ctx, r, "search:k", searchCachePolicy, []string{TagSearch}, other); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.cache.Get(ctx, "search:k"); err != nil {
+		t.Errorf("el resultado debió guardarse: %v", err)
+	}
+}
+
+func TestCacheAsideServesStaleWhileRefreshing(t *testing.T) {
+	ctx := context.Background()
+	r := &VehicleRepository{cache: cache.NewMemory(10)}
+
+	// Un valor vencido como fresco pero aún dentro del TTL
+	stale, _ := json.Marshal(cacheEnvelope[string]{Data: "viejo", FreshUntil: time.Now().Add(-time.Second)})
+	if err := r.cache.Set(ctx, "k", string(stale), time.Minute, TagSearch); err != nil {
+		t.Fatal(err)
+	}
+
+	refreshed := make(chan struct{})
+	load := func(context.Context) (string, error) {
+		defer close(refreshed)
+		return "nuevo", nil
+	}
+	got, err := cacheAside(ctx, r, "k", searchCachePolicy, []string{TagSearch}, load)
+	if err != nil || got != "viejo" {
+		t.Fatalf("cacheAside = %q, %v; want el valor obsoleto sin esperar la carga", got, err)
+	}
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("el valor obsoleto no se refrescó en segundo plano")
+	}
+	// El Set ocurre después de load; se espera a que el grupo termine
+	r.group.Do("k", func() (interface{}, error) { return nil, nil })
+
+	cached, err := r.cache.Get(ctx, "k")
+	var env cacheEnvelope[string]
+	if err != nil || json.Unmarshal([]byte(cached), &env) != nil || env.Data != "nuevo" || !env.FreshUntil.After(time.Now()) {
+		t.Errorf("caché tras refrescar = %q, %v", cached, err)
+	}
+}
+
+func TestFilterCacheKeyIsCanonical(t *testing.T) {
+	a := models.SearchFilter{
+		BrandID: []int{3, 1},
+		Query:   "  Camioneta   Familiar ",
+		Specs:   []models.SpecFilter{{Name: "b", Operator: ">=", Value: 1}, {Name: "a", Operator: "<", Value: 2}},
+	}
+	b := models.SearchFilter{
+		BrandID: []int{1, 3},
+		Query:   "camioneta familiar",
+		Specs:   []models.SpecFilter{{Name: "a", Operator: "<", Value: 2}, {Name: "b", Operator: ">=", Value: 1}},
+	}
+	if filterCacheKey("search:", a) != filterCacheKey("search:", b) {
+		t.Error("filtros equivalentes deben compartir llave")
+	}
+
+	b.BrandID = []int{1}
+	if filterCacheKey("search:", a) == filterCacheKey("search:", b) {
+		t.Error("filtros distintos no deben compartir llave")
+	}
+	if a.BrandID[0] != 3 {
+		t.Error("filterCacheKey no debe modificar el filtro")
+	}
+}
@@ -0,0 +1,78 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Etiquetas de caché compartidas por los repositorios
+const (
+	TagSearch  = "search"
+	TagCatalog = "catalog"
+)
+
+// VehicleTag es la etiqueta de todo lo cacheado que depende del vehículo id
+func VehicleTag(id int) string {
+	return fmt.Sprintf("vehicle:%d", id)
+}
+
+// tagGenerations cuenta las invalidaciones de cada etiqueta. Una carga toma las generaciones
+// de sus etiquetas antes de consultar y sólo guarda el resultado si ninguna cambió, para no
+// volver a cachear datos que una escritura invalidó mientras la consulta corría.
+type tagGenerations struct {
+	mu   sync.Mutex
+	gens map[string]uint64
+}
+
+// snapshot devuelve la generación actual de cada etiqueta
+func (g *tagGenerations) snapshot(tags []string) []uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	gens := make([]uint64, len(tags))
+	for i, tag := range tags {
+		gens[i] = g.gens[tag]
+	}
+	return gens
+}
+
+// changed indica si alguna etiqueta se invalidó desde snapshot
+func (g *tagGenerations) changed(tags []string, snapshot []uint64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for i, tag := range tags {
+		if g.gens[tag] != snapshot[i] {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *tagGenerations) bump(tags []string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.gens == nil {
+		g.gens = make(map[string]uint64)
+	}
+	for _, tag := range tags {
+		g.gens[tag]++
+	}
+}
+
+// invalidateTags elimina lo cacheado bajo las etiquetas. La generación se incrementa antes de
+// borrar para que una carga en curso no vuelva a guardar su resultado después del borrado.
+// Se desacopla de la cancelación de la petición porque la escritura ya se confirmó.
+// También descarta el catálogo de puntaje si depende de alguna de ellas.
+func (r *VehicleRepository) invalidateTags(ctx context.Context, tags ...string) error {
+	r.gens.bump(tags)
+	for _, tag := range tags {
+		if tag == TagSearch || tag == TagCatalog {
+			r.scoring.Store(nil)
+			break
+		}
+	}
+	return r.cache.InvalidateTags(context.WithoutCancel(ctx), tags...)
+}
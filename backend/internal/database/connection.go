@@ -6,19 +6,34 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	_ "github.com/lib/pq"
 	"github.com/redis/go-redis/v9"
+	"github.com/vehiculos/backend/internal/cache"
 )
 
 type DB struct {
 	SQL   *sql.DB
-	Redis *redis.Client
-	ctx   context.Context
+	Cache cache.Cache
 }
 
 func NewConnection() (*DB, error) {
+	sqlDB, err := OpenPostgres()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DB{
+		SQL:   sqlDB,
+		Cache: newCache(),
+	}, nil
+}
+
+// OpenPostgres abre y verifica la conexión con PostgreSQL a partir de las variables DB_*,
+// sin inicializar la caché (lo usan también las migraciones)
+func OpenPostgres() (*sql.DB, error) {
 	// Configuración PostgreSQL
 	dbHost := getEnv("DB_HOST", "localhost")
 	dbPort := getEnv("DB_PORT", "5432")
@@ -49,9 +64,28 @@ func NewConnection() (*DB, error) {
 	defer cancel()
 
 	if err := sqlDB.PingContext(ctx); err != nil {
+		sqlDB.Close()
 		return nil, fmt.Errorf("error al conectar con PostgreSQL: %w", err)
 	}
 
+	log.Println("✓ Conexión establecida con PostgreSQL")
+	return sqlDB, nil
+}
+
+// newCache construye la caché según CACHE_DRIVER: "memory" usa sólo la caché del proceso
+// (desarrollo y pruebas sin Redis); "redis" (por defecto) usa Redis con respaldo en memoria
+func newCache() cache.Cache {
+	memorySize, err := strconv.Atoi(getEnv("CACHE_MEMORY_SIZE", "10000"))
+	if err != nil {
+		memorySize = 10000
+	}
+	memory := cache.NewMemory(memorySize)
+
+	if getEnv("CACHE_DRIVER", "redis") == "memory" {
+		log.Println("✓ Usando caché en memoria")
+		return memory
+	}
+
 	// Configuración Redis
 	redisHost := getEnv("REDIS_HOST", "localhost")
 	redisPort := getEnv("REDIS_PORT", "6379")
@@ -67,31 +101,28 @@ func NewConnection() (*DB, error) {
 		MinIdleConns: 2,
 		MaxRetries:   3,
 	})
+	redisCache := cache.NewRedis(rdb)
 
 	// Verificar conexión con Redis
-	if _, err := rdb.Ping(ctx).Result(); err != nil {
-		log.Printf("Advertencia: No se pudo conectar a Redis: %v", err)
-		// No retornamos error porque Redis es opcional (caché)
-	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	log.Println("✓ Conexión establecida con PostgreSQL")
-	if rdb != nil {
+	if err := redisCache.Ping(ctx); err != nil {
+		// No retornamos error porque Redis es opcional (caché): el circuito cambia a memoria
+		log.Printf("Advertencia: No se pudo conectar a Redis: %v", err)
+	} else {
 		log.Println("✓ Conexión establecida con Redis")
 	}
 
-	return &DB{
-		SQL:   sqlDB,
-		Redis: rdb,
-		ctx:   context.Background(),
-	}, nil
+	return cache.NewFallback(redisCache, memory, 3, 30*time.Second)
 }
 
 func (db *DB) Close() {
 	if db.SQL != nil {
 		db.SQL.Close()
 	}
-	if db.Redis != nil {
-		db.Redis.Close()
+	if db.Cache != nil {
+		db.Cache.Close()
 	}
 }
 
@@ -106,32 +137,3 @@ func getEnv(key, defaultValue string) string {
 func (db *DB) BeginTx(ctx context.Context) (*sql.Tx, error) {
 	return db.SQL.BeginTx(ctx, nil)
 }
-
-// Cache helpers
-func (db *DB) SetCache(key string, value string, expiration time.Duration) error {
-	if db.Redis == nil {
-		return nil // Si Redis no está disponible, no hacer nada
-	}
-	return db.Redis.Set(db.ctx, key, value, expiration).Err()
-}
-
-func (db *DB) GetCache(key string) (string, error) {
-	if db.Redis == nil {
-		return "", redis.Nil
-	}
-	return db.Redis.Get(db.ctx, key).Result()
-}
-
-func (db *DB) DeleteCache(pattern string) error {
-	if db.Redis == nil {
-		return nil
-	}
-	keys, err := db.Redis.Keys(db.ctx, pattern).Result()
-	if err != nil {
-		return err
-	}
-	if len(keys) > 0 {
-		return db.Redis.Del(db.ctx, keys...).Err()
-	}
-	return nil
-}
\ No newline at end of file
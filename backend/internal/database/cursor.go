@@ -0,0 +1,69 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// ErrInvalidCursor indica un cursor malformado o generado con otro ordenamiento u otros filtros
+var ErrInvalidCursor = errors.New("cursor inválido")
+
+// cursorPayload es el contenido del cursor antes de codificarlo en base64
+type cursorPayload struct {
+	Sort   string            `json:"s"`
+	Filter string            `json:"f"`
+	Values []json.RawMessage `json:"v"`
+	Before bool              `json:"b,omitempty"`
+}
+
+// EncodeCursor genera el cursor opaco que apunta a v con las llaves de orden activas. Guarda
+// el resumen de los filtros para rechazar el cursor si se usa con otra búsqueda.
+func EncodeCursor(filter models.SearchFilter, keys []SortKey, v models.Vehicle, before bool) string {
+	payload := cursorPayload{Sort: filter.SortBy, Filter: cursorFilterHash(filter), Before: before}
+	for _, k := range keys {
+		raw, _ := json.Marshal(k.Value(v))
+		payload.Values = append(payload.Values, raw)
+	}
+
+	data, _ := json.Marshal(payload)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor valida filter.Cursor contra el ordenamiento y los filtros actuales y devuelve
+// los valores de las llaves con el mismo tipo que produce SortKey.Value
+func DecodeCursor(filter models.SearchFilter, keys []SortKey) ([]interface{}, bool, error) {
+	data, err := base64.RawURLEncoding.DecodeString(filter.Cursor)
+	if err != nil {
+		return nil, false, ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, false, ErrInvalidCursor
+	}
+	if payload.Sort != filter.SortBy || payload.Filter != cursorFilterHash(filter) || len(payload.Values) != len(keys) {
+		return nil, false, ErrInvalidCursor
+	}
+
+	values := make([]interface{}, len(keys))
+	for i, k := range keys {
+		ptr := reflect.New(reflect.TypeOf(k.Value(models.Vehicle{})))
+		if err := json.Unmarshal(payload.Values[i], ptr.Interface()); err != nil {
+			return nil, false, ErrInvalidCursor
+		}
+		values[i] = ptr.Elem().Interface()
+	}
+
+	return values, payload.Before, nil
+}
+
+// cursorFilterHash resume los filtros de la búsqueda sin los campos de paginación, que
+// cambian de una página a otra, ni el orden, que el cursor guarda aparte
+func cursorFilterHash(filter models.SearchFilter) string {
+	filter.Page, filter.Limit, filter.Cursor, filter.Count, filter.SortBy = 0, 0, "", "", ""
+	return canonicalFilterHash(filter)
+}
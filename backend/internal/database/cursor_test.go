@@ -0,0 +1,92 @@
+package database
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	filter := models.SearchFilter{SortBy: "year_desc,created_asc", BrandID: []int{3, 1}}
+	keys, err := SearchSort(filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2024, 5, 1, 12, 30, 0, 123456000, time.UTC)
+	v := models.Vehicle{ID: 42, Year: 2023, CreatedAt: created}
+
+	filter.Cursor = EncodeCursor(filter, keys, v, true)
+	values, before, err := DecodeCursor(filter, keys)
+	if err != nil {
+		t.Fatalf("DecodeCursor: %v", err)
+	}
+	if !before {
+		t.Error("before = false, want true")
+	}
+	if want := []interface{}{2023, created, 42}; !reflect.DeepEqual(values, want) {
+		t.Errorf("valores = %#v, want %#v", values, want)
+	}
+}
+
+func TestDecodeCursorChecksFilter(t *testing.T) {
+	filter := models.SearchFilter{
+		SortBy:   "price_asc",
+		BrandID:  []int{1, 2},
+		PriceMax: 500000,
+		Query:    "Toyota Hibrido",
+		Limit:    20,
+	}
+	keys, err := SearchSort(filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := EncodeCursor(filter, keys, models.Vehicle{ID: 1, Price: 300000}, false)
+
+	tests := []struct {
+		name   string
+		modify func(f *models.SearchFilter)
+		valid  bool
+	}{
+		{"mismo filtro", func(f *models.SearchFilter) {}, true},
+		{"otro límite y página", func(f *models.SearchFilter) { f.Limit, f.Page, f.Count = 50, 3, models.CountNone }, true},
+		{"IDs en otro orden y búsqueda con otras mayúsculas", func(f *models.SearchFilter) {
+			f.BrandID = []int{2, 1}
+			f.Query = "toyota  HIBRIDO"
+		}, true},
+		{"otra marca", func(f *models.SearchFilter) { f.BrandID = []int{1} }, false},
+		{"otro precio máximo", func(f *models.SearchFilter) { f.PriceMax = 400000 }, false},
+		{"otra búsqueda", func(f *models.SearchFilter) { f.Query = "Mazda" }, false},
+		{"otras características", func(f *models.SearchFilter) { f.FeaturesAll = []string{"GPS"} }, false},
+		{"otro orden", func(f *models.SearchFilter) { f.SortBy = "price_desc" }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := filter
+			tt.modify(&f)
+			f.Cursor = token
+			keys, err := SearchSort(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _, err = DecodeCursor(f, keys)
+			if tt.valid && err != nil {
+				t.Errorf("DecodeCursor rechazó un filtro equivalente: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsMalformed(t *testing.T) {
+	keys, _ := SearchSort(models.SearchFilter{})
+	for _, token := range []string{"no-es-base64!", "bm8tanNvbg", "e30"} {
+		if _, _, err := DecodeCursor(models.SearchFilter{Cursor: token}, keys); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q) = %v, want ErrInvalidCursor", token, err)
+		}
+	}
+}
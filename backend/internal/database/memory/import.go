@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vehiculos/backend/internal/database"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+var _ database.ImportStore = (*Store)(nil)
+
+// FindVehicleID busca un vehículo por su llave natural (marca, modelo, año)
+func (s *Store) FindVehicleID(_ context.Context, brandID int, model string, year int) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if id, ok := s.findByKey(brandID, model, year); ok {
+		return id, nil
+	}
+	return 0, database.ErrVehicleNotFound
+}
+
+// CreateBrand da de alta una marca; si ya existe con ese nombre devuelve la existente
+func (s *Store) CreateBrand(_ context.Context, name string) (*models.Brand, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nextID := 1
+	for _, b := range s.brands {
+		if b.Name == name {
+			brand := b
+			return &brand, nil
+		}
+		if b.ID >= nextID {
+			nextID = b.ID + 1
+		}
+	}
+
+	now := time.Now()
+	brand := models.Brand{ID: nextID, Name: name, CreatedAt: now, UpdatedAt: now}
+	s.brands = append(s.brands, brand)
+	sort.Slice(s.brands, func(i, j int) bool { return s.brands[i].Name < s.brands[j].Name })
+	return &brand, nil
+}
+
+// UpsertVehicle inserta el vehículo o reemplaza el existente con la misma llave natural
+func (s *Store) UpsertVehicle(_ context.Context, input models.VehicleInput) (int, bool, error) {
+	input.Normalize()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.validateInput(input); err != nil {
+		return 0, false, err
+	}
+
+	if id, ok := s.findByKey(input.BrandID, input.Model, input.Year); ok {
+		rec := s.vehicles[id]
+		rec.vehicle = s.buildVehicle(id, input, rec.vehicle.CreatedAt)
+		return id, false, nil
+	}
+
+	s.nextVehicleID++
+	id := s.nextVehicleID
+	s.vehicles[id] = &vehicleRecord{vehicle: s.buildVehicle(id, input, time.Now())}
+	return id, true, nil
+}
+
+// InvalidateImport no hace nada: el store en memoria no tiene caché
+func (s *Store) InvalidateImport(_ context.Context, _ []int) {}
+
+// findByKey debe llamarse con el lock tomado
+func (s *Store) findByKey(brandID int, model string, year int) (int, bool) {
+	for id, rec := range s.vehicles {
+		v := rec.vehicle
+		if v.BrandID == brandID && v.Year == year && strings.EqualFold(v.Model, model) {
+			return id, true
+		}
+	}
+	return 0, false
+}
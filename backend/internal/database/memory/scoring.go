@@ -0,0 +1,23 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/scoring"
+)
+
+// GetScoringCatalog devuelve una copia de todos los vehículos con sus rangos. En memoria no
+// hace falta cachearlo: recorrer el catálogo no cuesta una consulta.
+func (s *Store) GetScoringCatalog(_ context.Context) (*scoring.Catalog, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	vehicles := make([]models.Vehicle, 0, len(s.vehicles))
+	for _, rec := range s.vehicles {
+		vehicles = append(vehicles, copyVehicle(rec.vehicle))
+	}
+	sort.Slice(vehicles, func(i, j int) bool { return vehicles[i].ID < vehicles[j].ID })
+	return scoring.NewCatalog(vehicles), nil
+}
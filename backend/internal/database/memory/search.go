@@ -0,0 +1,579 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vehiculos/backend/internal/database"
+	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/textnorm"
+)
+
+// Dimensiones de facetas; igual que en PostgreSQL, cada dimensión ignora su propio filtro
+const (
+	facetNone         = ""
+	facetBrand        = "brand"
+	facetType         = "type"
+	facetFuelType     = "fuel_type"
+	facetTransmission = "transmission"
+	facetPrice        = "price"
+	facetYear         = "year"
+	facetFeatures     = "features"
+)
+
+// wordSimilarityThreshold es la similitud mínima de trigramas con la que un término se acepta
+// como error de escritura. Sólo aplica en memoria: en PostgreSQL el operador <% usa
+// pg_trgm.word_similarity_threshold, cuyo valor por defecto es el mismo.
+const wordSimilarityThreshold = 0.6
+
+// SearchVehicles busca vehículos con la misma semántica de filtros, orden y cursores que el
+// repositorio de PostgreSQL
+func (s *Store) SearchVehicles(_ context.Context, filter models.SearchFilter) (*models.SearchPage, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = 20
+	}
+
+	keys, err := database.SearchSort(filter)
+	if err != nil {
+		return nil, err
+	}
+	var values []interface{}
+	before := false
+	if filter.Cursor != "" {
+		if values, before, err = database.DecodeCursor(filter, keys); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matches := rankVehicles(s.filterVehicles(filter, facetNone), newTextQuery(filter))
+	sortVehicles(matches, keys)
+	total := len(matches)
+
+	// fetched replica el orden de lectura de la consulta SQL, con una fila extra
+	var fetched []models.Vehicle
+	offset := (filter.Page - 1) * filter.Limit
+	switch {
+	case filter.Cursor == "":
+		fetched = window(matches, offset, offset+filter.Limit+1)
+	case !before:
+		start := sort.Search(len(matches), func(i int) bool {
+			return database.CompareToCursor(keys, matches[i], values) > 0
+		})
+		fetched = window(matches, start, start+filter.Limit+1)
+	default:
+		end := sort.Search(len(matches), func(i int) bool {
+			return database.CompareToCursor(keys, matches[i], values) >= 0
+		})
+		fetched = append([]models.Vehicle(nil), window(matches, end-filter.Limit-1, end)...)
+		for i, j := 0, len(fetched)-1; i < j; i, j = i+1, j-1 {
+			fetched[i], fetched[j] = fetched[j], fetched[i]
+		}
+	}
+
+	vehicles := make([]models.Vehicle, 0, len(fetched))
+	for _, v := range fetched {
+		vehicles = append(vehicles, copyVehicle(v))
+	}
+
+	hasPrevious := filter.Cursor != "" || offset > 0
+	page := database.NewSearchPage(filter, keys, vehicles, filter.Limit, before, hasPrevious)
+	if filter.Count != models.CountNone {
+		page.Total = &total
+	}
+	return &page, nil
+}
+
+// window recorta vehicles a [from, to) ajustando los límites al tamaño del slice
+func window(vehicles []models.Vehicle, from, to int) []models.Vehicle {
+	if from < 0 {
+		from = 0
+	}
+	if to > len(vehicles) {
+		to = len(vehicles)
+	}
+	if from >= to {
+		return nil
+	}
+	return vehicles[from:to]
+}
+
+// ExportVehicles llama a fn por cada vehículo que cumple el filtro, sin paginación. Los
+// vehículos se copian antes de llamar a fn para no retener el lock mientras se escribe.
+func (s *Store) ExportVehicles(_ context.Context, filter models.SearchFilter, fn func(models.Vehicle) error) error {
+	keys, err := database.SearchSort(filter)
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	matches := rankVehicles(s.filterVehicles(filter, facetNone), newTextQuery(filter))
+	sortVehicles(matches, keys)
+	vehicles := make([]models.Vehicle, 0, len(matches))
+	for _, v := range matches {
+		vehicles = append(vehicles, copyVehicle(v))
+	}
+	s.mu.RUnlock()
+
+	for _, v := range vehicles {
+		if err := fn(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetSearchFacets calcula los conteos por dimensión para el filtro dado
+func (s *Store) GetSearchFacets(_ context.Context, filter models.SearchFilter) (*models.SearchFacets, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	facets := &models.SearchFacets{
+		Brands:        []models.FacetCount{},
+		VehicleTypes:  []models.FacetCount{},
+		FuelTypes:     []models.FacetCount{},
+		Transmissions: []models.FacetCount{},
+	}
+
+	brandCounts := countBy(s.filterVehicles(filter, facetBrand), func(v models.Vehicle) int { return v.BrandID })
+	for _, b := range s.brands {
+		facets.Brands = append(facets.Brands, models.FacetCount{ID: b.ID, Name: b.Name, Count: brandCounts[b.ID]})
+	}
+	typeCounts := countBy(s.filterVehicles(filter, facetType), func(v models.Vehicle) int { return v.TypeID })
+	for _, t := range s.vehicleTypes {
+		facets.VehicleTypes = append(facets.VehicleTypes, models.FacetCount{ID: t.ID, Name: t.Name, Count: typeCounts[t.ID]})
+	}
+	fuelCounts := countBy(s.filterVehicles(filter, facetFuelType), func(v models.Vehicle) int { return v.FuelTypeID })
+	for _, f := range s.fuelTypes {
+		facets.FuelTypes = append(facets.FuelTypes, models.FacetCount{ID: f.ID, Name: f.Name, Count: fuelCounts[f.ID]})
+	}
+	transmissionCounts := countBy(s.filterVehicles(filter, facetTransmission), func(v models.Vehicle) int { return v.TransmissionID })
+	for _, t := range s.transmissions {
+		facets.Transmissions = append(facets.Transmissions, models.FacetCount{ID: t.ID, Name: t.Name, Count: transmissionCounts[t.ID]})
+	}
+
+	facets.Price = histogram(s.filterVehicles(filter, facetPrice), func(v models.Vehicle) float64 { return v.Price }, database.PriceBucketSize)
+	facets.Year = histogram(s.filterVehicles(filter, facetYear), func(v models.Vehicle) float64 { return float64(v.Year) }, 1)
+
+	return facets, nil
+}
+
+// GetFeatureCounts lista las características distintas con su número de vehículos, de la más
+// a la menos común, ignorando features_any como el repositorio de PostgreSQL
+func (s *Store) GetFeatureCounts(_ context.Context, filter models.SearchFilter) ([]models.FeatureCount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make(map[string]string)
+	vehicles := make(map[string]map[int]bool)
+	for _, rec := range s.filterVehicles(filter, facetFeatures) {
+		for _, f := range rec.vehicle.Features {
+			key := strings.ToLower(f)
+			if name, ok := names[key]; !ok || f < name {
+				names[key] = f
+			}
+			if vehicles[key] == nil {
+				vehicles[key] = make(map[int]bool)
+			}
+			vehicles[key][rec.vehicle.ID] = true
+		}
+	}
+
+	counts := make([]models.FeatureCount, 0, len(names))
+	for key, name := range names {
+		counts = append(counts, models.FeatureCount{Feature: name, Count: len(vehicles[key])})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return strings.ToLower(counts[i].Feature) < strings.ToLower(counts[j].Feature)
+	})
+	return counts, nil
+}
+
+// filterVehicles devuelve los vehículos que cumplen el filtro omitiendo la dimensión exclude;
+// debe llamarse con el lock tomado
+func (s *Store) filterVehicles(filter models.SearchFilter, exclude string) []*vehicleRecord {
+	text := newTextQuery(filter)
+	featuresAll := models.NormalizeFeatures(filter.FeaturesAll)
+	featuresAny := models.NormalizeFeatures(filter.FeaturesAny)
+
+	matches := []*vehicleRecord{}
+	for _, rec := range s.vehicles {
+		v := rec.vehicle
+
+		if exclude != facetBrand && len(filter.BrandID) > 0 && !containsInt(filter.BrandID, v.BrandID) {
+			continue
+		}
+		if exclude != facetType && len(filter.TypeID) > 0 && !containsInt(filter.TypeID, v.TypeID) {
+			continue
+		}
+		if exclude != facetFuelType && len(filter.FuelTypeID) > 0 && !containsInt(filter.FuelTypeID, v.FuelTypeID) {
+			continue
+		}
+		if exclude != facetTransmission && len(filter.TransmissionID) > 0 && !containsInt(filter.TransmissionID, v.TransmissionID) {
+			continue
+		}
+		if !matchesRanges(&filter, exclude, v) {
+			continue
+		}
+		if !text.empty() && !text.matches(searchText(v)) {
+			continue
+		}
+		if !hasAllFeatures(featuresAll, v.Features) {
+			continue
+		}
+		if exclude != facetFeatures && len(featuresAny) > 0 && !hasAnyFeature(featuresAny, v.Features) {
+			continue
+		}
+		if !matchesSpecs(filter.Specs, rec.specs) {
+			continue
+		}
+
+		matches = append(matches, rec)
+	}
+	return matches
+}
+
+// matchesRanges aplica los rangos numéricos omitiendo el de la dimensión exclude
+func matchesRanges(filter *models.SearchFilter, exclude string, v models.Vehicle) bool {
+	for _, r := range models.RangeFilters {
+		if r.Name != exclude && !r.Matches(filter, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// rankVehicles copia los vehículos (sin duplicar sus slices) con la relevancia de la búsqueda
+// de texto, para ordenar por relevance sin modificar los registros guardados
+func rankVehicles(recs []*vehicleRecord, text textQuery) []models.Vehicle {
+	vehicles := make([]models.Vehicle, len(recs))
+	for i, rec := range recs {
+		vehicles[i] = rec.vehicle
+		if !text.empty() {
+			vehicles[i].Relevance = text.relevance(searchText(rec.vehicle))
+		}
+	}
+	return vehicles
+}
+
+// sortVehicles ordena con las mismas llaves que la consulta SQL
+func sortVehicles(vehicles []models.Vehicle, keys []database.SortKey) {
+	sort.Slice(vehicles, func(i, j int) bool {
+		return database.CompareVehicles(keys, vehicles[i], vehicles[j]) < 0
+	})
+}
+
+// hasAllFeatures indica si features contiene todas las características buscadas (normalizadas)
+func hasAllFeatures(wanted, features []string) bool {
+	for _, w := range wanted {
+		if !hasAnyFeature([]string{w}, features) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasAnyFeature indica si features contiene alguna de las características buscadas (normalizadas)
+func hasAnyFeature(wanted, features []string) bool {
+	for _, f := range features {
+		lower := strings.ToLower(f)
+		for _, w := range wanted {
+			if lower == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesSpecs aplica los filtros numéricos de especificaciones; los valores no numéricos nunca coinciden
+func matchesSpecs(filters []models.SpecFilter, specs []models.VehicleSpec) bool {
+	for _, f := range filters {
+		if !models.ValidSpecOperator(f.Operator) {
+			continue
+		}
+
+		found := false
+		for _, spec := range specs {
+			if spec.Name != f.Name {
+				continue
+			}
+			value, err := strconv.ParseFloat(strings.TrimSpace(spec.Value), 64)
+			if err != nil {
+				continue
+			}
+			if compare(value, f.Operator, f.Value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func compare(value float64, op string, target float64) bool {
+	switch op {
+	case "=":
+		return value == target
+	case ">":
+		return value > target
+	case ">=":
+		return value >= target
+	case "<":
+		return value < target
+	case "<=":
+		return value <= target
+	}
+	return false
+}
+
+// spanishStopwords son las palabras vacías más comunes que plainto_tsquery('spanish') descarta
+var spanishStopwords = map[string]bool{
+	"de": true, "la": true, "el": true, "los": true, "las": true, "un": true, "una": true,
+	"y": true, "o": true, "en": true, "con": true, "para": true, "por": true, "del": true,
+	"al": true, "que": true, "se": true, "su": true, "sus": true, "a": true,
+}
+
+// queryTerms aproxima plainto_tsquery('spanish'): minúsculas sin acentos, sin palabras vacías
+// y con una reducción simple de plurales
+func queryTerms(query string) []string {
+	terms := []string{}
+	for _, word := range textnorm.Tokenize(query) {
+		if !spanishStopwords[word] {
+			terms = append(terms, stem(word))
+		}
+	}
+	return terms
+}
+
+// textQuery aproxima la búsqueda de texto de PostgreSQL: terms son las palabras de Query y
+// groups los grupos de sinónimos, cada uno con las palabras de sus frases alternativas
+type textQuery struct {
+	terms  []string
+	groups [][][]string
+}
+
+func newTextQuery(filter models.SearchFilter) textQuery {
+	q := textQuery{terms: queryTerms(filter.Query)}
+	for _, group := range filter.QueryExpansions {
+		alternatives := make([][]string, len(group))
+		for i, phrase := range group {
+			alternatives[i] = queryTerms(phrase)
+		}
+		q.groups = append(q.groups, alternatives)
+	}
+	return q
+}
+
+func (q textQuery) empty() bool {
+	return len(q.terms) == 0 && len(q.groups) == 0
+}
+
+// matches exige los términos de Query (por raíz o por trigramas) y, de cada grupo de
+// sinónimos, alguna frase con todas sus palabras por raíz, como el tsquery de los grupos
+func (q textQuery) matches(text string) bool {
+	return matchesTerms(q.terms, text) && q.groupTerms(text) != nil
+}
+
+// relevance puntúa con los términos de Query y los de la frase que coincidió en cada grupo
+func (q textQuery) relevance(text string) float64 {
+	terms := append(append([]string(nil), q.terms...), q.groupTerms(text)...)
+	if len(terms) == 0 {
+		return 0
+	}
+	return relevance(terms, text)
+}
+
+// groupTerms devuelve las palabras de la primera frase que coincide en cada grupo, o nil si
+// algún grupo no coincide
+func (q textQuery) groupTerms(text string) []string {
+	words := textnorm.Tokenize(text)
+	terms := []string{}
+	for _, group := range q.groups {
+		found := false
+		for _, alternative := range group {
+			if len(alternative) > 0 && containsAll(alternative, words) {
+				terms = append(terms, alternative...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	return terms
+}
+
+// containsAll indica si todos los términos aparecen por raíz en words
+func containsAll(terms, words []string) bool {
+	for _, term := range terms {
+		found := false
+		for _, word := range words {
+			if strings.HasPrefix(stem(word), term) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// searchText aproxima vehicles.search_text: marca, modelo (también sin separadores, para que
+// "cx5" encuentre "CX-5"), tipo, características y descripción
+func searchText(v models.Vehicle) string {
+	parts := []string{v.Model, strings.Join(textnorm.Tokenize(v.Model), "")}
+	if v.Brand != nil {
+		parts = append(parts, v.Brand.Name)
+	}
+	if v.Type != nil {
+		parts = append(parts, v.Type.Name)
+	}
+	parts = append(parts, v.Features...)
+	parts = append(parts, v.Description)
+	return strings.Join(parts, " ")
+}
+
+// matchesTerms aproxima la condición de PostgreSQL: cada término debe aparecer en el texto,
+// como el operador & de tsquery, ya sea por su raíz o, ante errores de escritura, por
+// similitud de trigramas con alguna palabra
+func matchesTerms(terms []string, text string) bool {
+	words := textnorm.Tokenize(text)
+	for _, term := range terms {
+		if bestMatch(term, words) < wordSimilarityThreshold {
+			return false
+		}
+	}
+	return true
+}
+
+// relevance aproxima el puntaje combinado: la proporción de palabras del texto que coinciden
+// con algún término (como ts_rank, más coincidencias en un texto más corto puntúan más) más
+// la similitud de trigramas promedio ponderada con database.TrigramRankWeight
+func relevance(terms []string, text string) float64 {
+	words := textnorm.Tokenize(text)
+	if len(words) == 0 {
+		return 0
+	}
+	hits := 0
+	for _, word := range words {
+		for _, term := range terms {
+			if strings.HasPrefix(stem(word), term) {
+				hits++
+				break
+			}
+		}
+	}
+	similarity := 0.0
+	for _, term := range terms {
+		similarity += bestMatch(term, words)
+	}
+	return float64(hits)/float64(len(words)) + database.TrigramRankWeight*similarity/float64(len(terms))
+}
+
+// bestMatch es la mejor coincidencia de term con words: 1 si alguna raíz empieza con el
+// término y, si no, la mayor similitud de trigramas
+func bestMatch(term string, words []string) float64 {
+	best := 0.0
+	for _, word := range words {
+		stemmed := stem(word)
+		if strings.HasPrefix(stemmed, term) {
+			return 1
+		}
+		if sim := trigramSimilarity(term, stemmed); sim > best {
+			best = sim
+		}
+	}
+	return best
+}
+
+// trigramSimilarity es la fracción de los trigramas de term (con el relleno de pg_trgm: dos
+// espacios al inicio y uno al final) que también tiene word, como word_similarity
+func trigramSimilarity(term, word string) float64 {
+	termTrigrams := trigrams(term)
+	if len(termTrigrams) == 0 {
+		return 0
+	}
+	wordTrigrams := trigrams(word)
+	shared := 0
+	for t := range termTrigrams {
+		if wordTrigrams[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(termTrigrams))
+}
+
+func trigrams(word string) map[string]bool {
+	runes := []rune("  " + word + " ")
+	set := make(map[string]bool, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		set[string(runes[i:i+3])] = true
+	}
+	return set
+}
+
+func stem(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "es"):
+		return word[:len(word)-2]
+	case len(word) > 3 && strings.HasSuffix(word, "s"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func countBy(recs []*vehicleRecord, key func(models.Vehicle) int) map[int]int {
+	counts := make(map[int]int)
+	for _, rec := range recs {
+		counts[key(rec.vehicle)]++
+	}
+	return counts
+}
+
+// histogram agrupa los vehículos en rangos [Min, Max) de ancho bucketSize
+func histogram(recs []*vehicleRecord, value func(models.Vehicle) float64, bucketSize float64) []models.HistogramBucket {
+	counts := make(map[float64]int)
+	for _, rec := range recs {
+		counts[math.Floor(value(rec.vehicle)/bucketSize)]++
+	}
+
+	keys := make([]float64, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Float64s(keys)
+
+	buckets := []models.HistogramBucket{}
+	for _, k := range keys {
+		lower := k * bucketSize
+		buckets = append(buckets, models.HistogramBucket{Min: lower, Max: lower + bucketSize, Count: counts[k]})
+	}
+	return buckets
+}
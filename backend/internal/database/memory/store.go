@@ -0,0 +1,709 @@
+// Package memory implementa los stores del catálogo en memoria a partir de un archivo
+// JSON de semilla, para ejecutar la API y sus pruebas sin PostgreSQL ni Redis.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vehiculos/backend/internal/database"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// Seed es el formato del archivo JSON de semilla
+type Seed struct {
+	Brands        []models.Brand        `json:"brands"`
+	VehicleTypes  []models.VehicleType  `json:"vehicle_types"`
+	FuelTypes     []models.FuelType     `json:"fuel_types"`
+	Transmissions []models.Transmission `json:"transmissions"`
+	Vehicles      []SeedVehicle         `json:"vehicles"`
+}
+
+// SeedVehicle es un vehículo de la semilla con su galería y especificaciones
+type SeedVehicle struct {
+	models.VehicleInput
+	ID     int                   `json:"id"`
+	Images []models.VehicleImage `json:"images"`
+	Specs  []models.VehicleSpec  `json:"specs"`
+}
+
+type vehicleRecord struct {
+	vehicle models.Vehicle
+	images  []models.VehicleImage
+	specs   []models.VehicleSpec
+}
+
+// Store implementa VehicleStore, CatalogStore, UserSearchStore y UserPreferenceStore
+type Store struct {
+	mu sync.RWMutex
+
+	brands        []models.Brand
+	vehicleTypes  []models.VehicleType
+	fuelTypes     []models.FuelType
+	transmissions []models.Transmission
+
+	vehicles    map[int]*vehicleRecord
+	searches    map[int]*models.UserSearch
+	preferences []models.UserPreference
+
+	nextVehicleID    int
+	nextImageID      int
+	nextSpecID       int
+	nextSearchID     int
+	nextPreferenceID int
+}
+
+var (
+	_ database.VehicleStore        = (*Store)(nil)
+	_ database.CatalogStore        = (*Store)(nil)
+	_ database.UserSearchStore     = (*Store)(nil)
+	_ database.UserPreferenceStore = (*Store)(nil)
+)
+
+// LoadFile crea un Store a partir del archivo de semilla en path
+func LoadFile(path string) (*Store, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer semilla: %w", err)
+	}
+
+	var seed Seed
+	if err := json.Unmarshal(data, &seed); err != nil {
+		return nil, fmt.Errorf("error al interpretar semilla: %w", err)
+	}
+
+	return NewStore(seed)
+}
+
+// NewStore crea un Store con los datos de la semilla, validándolos igual que la base de datos
+func NewStore(seed Seed) (*Store, error) {
+	s := &Store{
+		brands:        append([]models.Brand(nil), seed.Brands...),
+		vehicleTypes:  append([]models.VehicleType(nil), seed.VehicleTypes...),
+		fuelTypes:     append([]models.FuelType(nil), seed.FuelTypes...),
+		transmissions: append([]models.Transmission(nil), seed.Transmissions...),
+		vehicles:      make(map[int]*vehicleRecord),
+		searches:      make(map[int]*models.UserSearch),
+	}
+
+	sort.Slice(s.brands, func(i, j int) bool { return s.brands[i].Name < s.brands[j].Name })
+	sort.Slice(s.vehicleTypes, func(i, j int) bool { return s.vehicleTypes[i].Name < s.vehicleTypes[j].Name })
+	sort.Slice(s.fuelTypes, func(i, j int) bool { return s.fuelTypes[i].Name < s.fuelTypes[j].Name })
+	sort.Slice(s.transmissions, func(i, j int) bool { return s.transmissions[i].Name < s.transmissions[j].Name })
+
+	now := time.Now()
+	for i, sv := range seed.Vehicles {
+		input := sv.VehicleInput
+		input.Normalize()
+		if err := s.validateInput(input); err != nil {
+			return nil, fmt.Errorf("vehículo %d de la semilla inválido: %w", i+1, err)
+		}
+
+		id := sv.ID
+		if id == 0 {
+			id = s.nextVehicleID + 1
+		}
+		if _, exists := s.vehicles[id]; exists {
+			return nil, fmt.Errorf("vehículo %d de la semilla con ID duplicado: %d", i+1, id)
+		}
+		if id > s.nextVehicleID {
+			s.nextVehicleID = id
+		}
+
+		// Los vehículos más recientes de la semilla se consideran los últimos agregados
+		createdAt := now.Add(time.Duration(i-len(seed.Vehicles)) * time.Second)
+		rec := &vehicleRecord{vehicle: s.buildVehicle(id, input, createdAt)}
+
+		for _, img := range sv.Images {
+			s.nextImageID++
+			img.ID = s.nextImageID
+			img.VehicleID = id
+			img.CreatedAt = createdAt
+			rec.images = append(rec.images, img)
+		}
+		for _, spec := range sv.Specs {
+			s.nextSpecID++
+			spec.ID = s.nextSpecID
+			spec.VehicleID = id
+			rec.specs = append(rec.specs, spec)
+		}
+		sortImages(rec.images)
+
+		s.vehicles[id] = rec
+	}
+
+	return s, nil
+}
+
+// GetBrands obtiene todas las marcas
+func (s *Store) GetBrands(_ context.Context) ([]models.Brand, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]models.Brand{}, s.brands...), nil
+}
+
+// GetVehicleTypes obtiene todos los tipos de vehículo
+func (s *Store) GetVehicleTypes(_ context.Context) ([]models.VehicleType, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]models.VehicleType{}, s.vehicleTypes...), nil
+}
+
+// GetFuelTypes obtiene todos los tipos de combustible
+func (s *Store) GetFuelTypes(_ context.Context) ([]models.FuelType, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]models.FuelType{}, s.fuelTypes...), nil
+}
+
+// GetTransmissions obtiene todos los tipos de transmisión
+func (s *Store) GetTransmissions(_ context.Context) ([]models.Transmission, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]models.Transmission{}, s.transmissions...), nil
+}
+
+// GetVehicleByID obtiene un vehículo con su galería y especificaciones
+func (s *Store) GetVehicleByID(_ context.Context, id int) (*models.Vehicle, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.vehicles[id]
+	if !ok {
+		return nil, database.ErrVehicleNotFound
+	}
+
+	v := copyVehicle(rec.vehicle)
+	v.Images = append([]models.VehicleImage{}, rec.images...)
+	v.Specs = models.GroupSpecsByCategory(sortedSpecs(rec.specs))
+	return &v, nil
+}
+
+// GetVehiclesByIDs obtiene varios vehículos respetando el orden de los IDs
+func (s *Store) GetVehiclesByIDs(_ context.Context, ids []int) ([]models.Vehicle, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	vehicles := []models.Vehicle{}
+	for _, id := range ids {
+		if rec, ok := s.vehicles[id]; ok {
+			vehicles = append(vehicles, copyVehicle(rec.vehicle))
+		}
+	}
+	return vehicles, nil
+}
+
+// CreateVehicle da de alta un vehículo
+func (s *Store) CreateVehicle(ctx context.Context, input models.VehicleInput) (*models.Vehicle, error) {
+	input.Normalize()
+
+	s.mu.Lock()
+	if err := s.validateWrite(0, input); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	s.nextVehicleID++
+	id := s.nextVehicleID
+	s.vehicles[id] = &vehicleRecord{vehicle: s.buildVehicle(id, input, time.Now())}
+	s.mu.Unlock()
+
+	return s.GetVehicleByID(ctx, id)
+}
+
+// UpdateVehicle reemplaza los datos de un vehículo
+func (s *Store) UpdateVehicle(ctx context.Context, id int, input models.VehicleInput) (*models.Vehicle, error) {
+	input.Normalize()
+
+	s.mu.Lock()
+	rec, ok := s.vehicles[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, database.ErrVehicleNotFound
+	}
+	if err := s.validateWrite(id, input); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	updated := s.buildVehicle(id, input, rec.vehicle.CreatedAt)
+	rec.vehicle = updated
+	s.mu.Unlock()
+
+	return s.GetVehicleByID(ctx, id)
+}
+
+// PatchVehicle aplica una actualización parcial
+func (s *Store) PatchVehicle(ctx context.Context, id int, patch models.VehiclePatch) (*models.Vehicle, error) {
+	s.mu.Lock()
+	rec, ok := s.vehicles[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, database.ErrVehicleNotFound
+	}
+
+	input := inputFromVehicle(rec.vehicle)
+	patch.ApplyTo(&input)
+	input.Normalize()
+	if err := s.validateWrite(id, input); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	rec.vehicle = s.buildVehicle(id, input, rec.vehicle.CreatedAt)
+	s.mu.Unlock()
+
+	return s.GetVehicleByID(ctx, id)
+}
+
+// DeleteVehicle elimina un vehículo
+func (s *Store) DeleteVehicle(_ context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.vehicles[id]; !ok {
+		return database.ErrVehicleNotFound
+	}
+	delete(s.vehicles, id)
+
+	for _, search := range s.searches {
+		if search.SelectedVehicleID != nil && *search.SelectedVehicleID == id {
+			search.SelectedVehicleID = nil
+		}
+	}
+	return nil
+}
+
+// GetVehicleImages obtiene la galería del vehículo, opcionalmente filtrada por tipo
+func (s *Store) GetVehicleImages(_ context.Context, vehicleID int, imageType string) ([]models.VehicleImage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	images := []models.VehicleImage{}
+	if rec, ok := s.vehicles[vehicleID]; ok {
+		for _, img := range rec.images {
+			if imageType == "" || img.ImageType == imageType {
+				images = append(images, img)
+			}
+		}
+	}
+	return images, nil
+}
+
+// AddVehicleImage agrega una imagen al final de la galería salvo que se indique su posición
+func (s *Store) AddVehicleImage(_ context.Context, img *models.VehicleImage, displayOrder *int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.vehicles[img.VehicleID]
+	if !ok {
+		return database.ErrVehicleNotFound
+	}
+
+	if displayOrder != nil {
+		img.DisplayOrder = *displayOrder
+	} else {
+		img.DisplayOrder = 0
+		for _, existing := range rec.images {
+			if existing.DisplayOrder >= img.DisplayOrder {
+				img.DisplayOrder = existing.DisplayOrder + 1
+			}
+		}
+	}
+
+	s.nextImageID++
+	img.ID = s.nextImageID
+	img.CreatedAt = time.Now()
+	rec.images = append(rec.images, *img)
+	sortImages(rec.images)
+	return nil
+}
+
+// ReorderVehicleImages asigna display_order según la posición de cada ID
+func (s *Store) ReorderVehicleImages(_ context.Context, vehicleID int, imageIDs []int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.vehicles[vehicleID]
+	if !ok || len(imageIDs) != len(rec.images) {
+		return database.ErrInvalidImageOrder
+	}
+
+	position := make(map[int]int, len(imageIDs))
+	for i, id := range imageIDs {
+		position[id] = i
+	}
+	for _, img := range rec.images {
+		if _, ok := position[img.ID]; !ok {
+			return database.ErrInvalidImageOrder
+		}
+	}
+	if len(position) != len(rec.images) {
+		return database.ErrInvalidImageOrder
+	}
+
+	for i := range rec.images {
+		rec.images[i].DisplayOrder = position[rec.images[i].ID]
+	}
+	sortImages(rec.images)
+	return nil
+}
+
+// DeleteVehicleImage elimina una imagen de la galería
+func (s *Store) DeleteVehicleImage(_ context.Context, vehicleID, imageID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rec, ok := s.vehicles[vehicleID]; ok {
+		for i, img := range rec.images {
+			if img.ID == imageID {
+				rec.images = append(rec.images[:i], rec.images[i+1:]...)
+				return nil
+			}
+		}
+	}
+	return database.ErrVehicleImageNotFound
+}
+
+// GetVehicleSpecs obtiene las especificaciones del vehículo
+func (s *Store) GetVehicleSpecs(_ context.Context, vehicleID int) ([]models.VehicleSpec, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if rec, ok := s.vehicles[vehicleID]; ok {
+		return sortedSpecs(rec.specs), nil
+	}
+	return []models.VehicleSpec{}, nil
+}
+
+// UpsertVehicleSpecs crea o actualiza especificaciones por nombre
+func (s *Store) UpsertVehicleSpecs(_ context.Context, vehicleID int, specs []models.VehicleSpec) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.vehicles[vehicleID]
+	if !ok {
+		return database.ErrVehicleNotFound
+	}
+
+	for _, spec := range specs {
+		spec.VehicleID = vehicleID
+		replaced := false
+		for i := range rec.specs {
+			if rec.specs[i].Name == spec.Name {
+				spec.ID = rec.specs[i].ID
+				rec.specs[i] = spec
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.nextSpecID++
+			spec.ID = s.nextSpecID
+			rec.specs = append(rec.specs, spec)
+		}
+	}
+	return nil
+}
+
+// DeleteVehicleSpec elimina una especificación por nombre
+func (s *Store) DeleteVehicleSpec(_ context.Context, vehicleID int, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rec, ok := s.vehicles[vehicleID]; ok {
+		for i, spec := range rec.specs {
+			if spec.Name == name {
+				rec.specs = append(rec.specs[:i], rec.specs[i+1:]...)
+				return nil
+			}
+		}
+	}
+	return database.ErrVehicleSpecNotFound
+}
+
+// CreateUserSearch guarda una búsqueda del usuario
+func (s *Store) CreateUserSearch(_ context.Context, search *models.UserSearch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if search.SelectedVehicleID != nil {
+		if _, ok := s.vehicles[*search.SelectedVehicleID]; !ok {
+			return database.ErrVehicleNotFound
+		}
+	}
+
+	s.nextSearchID++
+	search.ID = s.nextSearchID
+	search.CreatedAt = time.Now()
+	stored := *search
+	s.searches[search.ID] = &stored
+	return nil
+}
+
+// SetSelectedVehicle registra el vehículo elegido tras una búsqueda
+func (s *Store) SetSelectedVehicle(_ context.Context, searchID int, sessionID string, vehicleID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.vehicles[vehicleID]; !ok {
+		return database.ErrVehicleNotFound
+	}
+	search, ok := s.searches[searchID]
+	if !ok || search.SessionID != sessionID {
+		return database.ErrUserSearchNotFound
+	}
+	search.SelectedVehicleID = &vehicleID
+	return nil
+}
+
+// CreatePreference guarda una preferencia de la sesión
+func (s *Store) CreatePreference(_ context.Context, pref *models.UserPreference) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextPreferenceID++
+	pref.ID = s.nextPreferenceID
+	pref.CreatedAt = time.Now()
+	s.preferences = append(s.preferences, *pref)
+	return nil
+}
+
+// GetPreferencesBySession obtiene las preferencias de la sesión en orden cronológico
+func (s *Store) GetPreferencesBySession(_ context.Context, sessionID string) ([]models.UserPreference, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	prefs := []models.UserPreference{}
+	for _, p := range s.preferences {
+		if p.SessionID == sessionID {
+			prefs = append(prefs, p)
+		}
+	}
+	return prefs, nil
+}
+
+// DeletePreference elimina una preferencia de la sesión
+func (s *Store) DeletePreference(_ context.Context, sessionID string, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, p := range s.preferences {
+		if p.ID == id && p.SessionID == sessionID {
+			s.preferences = append(s.preferences[:i], s.preferences[i+1:]...)
+			return nil
+		}
+	}
+	return database.ErrUserPreferenceNotFound
+}
+
+// DeleteSessionPreferences elimina todas las preferencias de la sesión
+func (s *Store) DeleteSessionPreferences(_ context.Context, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.preferences[:0]
+	for _, p := range s.preferences {
+		if p.SessionID != sessionID {
+			kept = append(kept, p)
+		}
+	}
+	s.preferences = kept
+	return nil
+}
+
+// validateInput aplica las validaciones del modelo y las llaves foráneas del esquema
+func (s *Store) validateInput(input models.VehicleInput) error {
+	if err := input.Validate(); err != nil {
+		return err
+	}
+
+	errs := models.ValidationErrors{}
+	if s.brand(input.BrandID) == nil {
+		errs["brand_id"] = "no existe"
+	}
+	if s.vehicleType(input.TypeID) == nil {
+		errs["type_id"] = "no existe"
+	}
+	if s.fuelType(input.FuelTypeID) == nil {
+		errs["fuel_type_id"] = "no existe"
+	}
+	if s.transmission(input.TransmissionID) == nil {
+		errs["transmission_id"] = "no existe"
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateWrite valida la entrada y, como el índice único de PostgreSQL, rechaza que otro
+// vehículo distinto de id comparta la llave natural; debe llamarse con el lock tomado
+func (s *Store) validateWrite(id int, input models.VehicleInput) error {
+	if err := s.validateInput(input); err != nil {
+		return err
+	}
+	if existing, ok := s.findByKey(input.BrandID, input.Model, input.Year); ok && existing != id {
+		return database.ErrDuplicateVehicle
+	}
+	return nil
+}
+
+// buildVehicle arma el modelo completo con sus catálogos; debe llamarse con el lock tomado
+func (s *Store) buildVehicle(id int, in models.VehicleInput, createdAt time.Time) models.Vehicle {
+	features := append([]string{}, in.Features...)
+	sort.Strings(features)
+
+	v := models.Vehicle{
+		ID:             id,
+		BrandID:        in.BrandID,
+		Model:          in.Model,
+		Year:           in.Year,
+		TypeID:         in.TypeID,
+		Price:          in.Price,
+		Currency:       in.Currency,
+		FuelTypeID:     in.FuelTypeID,
+		TransmissionID: in.TransmissionID,
+		Doors:          in.Doors,
+		Seats:          in.Seats,
+		EngineSize:     in.EngineSize,
+		Horsepower:     in.Horsepower,
+		Torque:         in.Torque,
+		FuelEconomy:    in.FuelEconomy,
+		TankCapacity:   in.TankCapacity,
+		CargoSpace:     in.CargoSpace,
+		ImageURL:       in.ImageURL,
+		Description:    in.Description,
+		Features:       features,
+		SafetyRating:   in.SafetyRating,
+		CreatedAt:      createdAt,
+		UpdatedAt:      time.Now(),
+	}
+
+	if b := s.brand(in.BrandID); b != nil {
+		brand := *b
+		v.Brand = &brand
+	}
+	if t := s.vehicleType(in.TypeID); t != nil {
+		vType := *t
+		v.Type = &vType
+	}
+	if f := s.fuelType(in.FuelTypeID); f != nil {
+		fuelType := *f
+		v.FuelType = &fuelType
+	}
+	if t := s.transmission(in.TransmissionID); t != nil {
+		transmission := *t
+		v.Transmission = &transmission
+	}
+
+	return v
+}
+
+func (s *Store) brand(id int) *models.Brand {
+	for i := range s.brands {
+		if s.brands[i].ID == id {
+			return &s.brands[i]
+		}
+	}
+	return nil
+}
+
+func (s *Store) vehicleType(id int) *models.VehicleType {
+	for i := range s.vehicleTypes {
+		if s.vehicleTypes[i].ID == id {
+			return &s.vehicleTypes[i]
+		}
+	}
+	return nil
+}
+
+func (s *Store) fuelType(id int) *models.FuelType {
+	for i := range s.fuelTypes {
+		if s.fuelTypes[i].ID == id {
+			return &s.fuelTypes[i]
+		}
+	}
+	return nil
+}
+
+func (s *Store) transmission(id int) *models.Transmission {
+	for i := range s.transmissions {
+		if s.transmissions[i].ID == id {
+			return &s.transmissions[i]
+		}
+	}
+	return nil
+}
+
+func inputFromVehicle(v models.Vehicle) models.VehicleInput {
+	return models.VehicleInput{
+		BrandID:        v.BrandID,
+		Model:          v.Model,
+		Year:           v.Year,
+		TypeID:         v.TypeID,
+		Price:          v.Price,
+		Currency:       v.Currency,
+		FuelTypeID:     v.FuelTypeID,
+		TransmissionID: v.TransmissionID,
+		Doors:          v.Doors,
+		Seats:          v.Seats,
+		EngineSize:     v.EngineSize,
+		Horsepower:     v.Horsepower,
+		Torque:         v.Torque,
+		FuelEconomy:    v.FuelEconomy,
+		TankCapacity:   v.TankCapacity,
+		CargoSpace:     v.CargoSpace,
+		ImageURL:       v.ImageURL,
+		Description:    v.Description,
+		Features:       append([]string{}, v.Features...),
+		SafetyRating:   v.SafetyRating,
+	}
+}
+
+// copyVehicle evita que quien recibe el vehículo modifique el estado interno del store
+func copyVehicle(v models.Vehicle) models.Vehicle {
+	c := v
+	c.Features = append([]string{}, v.Features...)
+	if v.Brand != nil {
+		brand := *v.Brand
+		c.Brand = &brand
+	}
+	if v.Type != nil {
+		vType := *v.Type
+		c.Type = &vType
+	}
+	if v.FuelType != nil {
+		fuelType := *v.FuelType
+		c.FuelType = &fuelType
+	}
+	if v.Transmission != nil {
+		transmission := *v.Transmission
+		c.Transmission = &transmission
+	}
+	c.Images = nil
+	c.Specs = nil
+	return c
+}
+
+func sortImages(images []models.VehicleImage) {
+	sort.SliceStable(images, func(i, j int) bool {
+		if images[i].DisplayOrder != images[j].DisplayOrder {
+			return images[i].DisplayOrder < images[j].DisplayOrder
+		}
+		return images[i].ID < images[j].ID
+	})
+}
+
+func sortedSpecs(specs []models.VehicleSpec) []models.VehicleSpec {
+	sorted := append([]models.VehicleSpec{}, specs...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Category != sorted[j].Category {
+			return sorted[i].Category < sorted[j].Category
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
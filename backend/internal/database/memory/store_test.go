@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/vehiculos/backend/internal/database"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+func testSeed() Seed {
+	return Seed{
+		Brands:        []models.Brand{{ID: 2, Name: "Toyota"}, {ID: 1, Name: "Mazda"}},
+		VehicleTypes:  []models.VehicleType{{ID: 1, Name: "Sedan"}},
+		FuelTypes:     []models.FuelType{{ID: 1, Name: "Gasolina"}},
+		Transmissions: []models.Transmission{{ID: 1, Name: "Automática"}},
+		Vehicles: []SeedVehicle{
+			{ID: 5, VehicleInput: models.VehicleInput{
+				BrandID: 2, Model: " Corolla ", Year: 2024, TypeID: 1, Price: 390000,
+				FuelTypeID: 1, TransmissionID: 1, Features: []string{"Cámara", "Bluetooth", "Cámara"},
+			}},
+		},
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewStore(testSeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	brands, _ := s.GetBrands(ctx)
+	if brands[0].Name != "Mazda" || brands[1].Name != "Toyota" {
+		t.Errorf("marcas = %+v, want ordenadas por nombre", brands)
+	}
+
+	v, err := s.GetVehicleByID(ctx, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Model != "Corolla" || v.Currency != "MXN" || v.Brand == nil || v.Brand.Name != "Toyota" {
+		t.Errorf("vehículo = %+v, want normalizado y con su marca", v)
+	}
+	if !reflect.DeepEqual(v.Features, []string{"Bluetooth", "Cámara"}) {
+		t.Errorf("características = %q, want sin duplicados y ordenadas", v.Features)
+	}
+
+	// Las copias devueltas no comparten memoria con el store
+	v.Features[0] = "modificada"
+	v.Brand.Name = "modificada"
+	again, _ := s.GetVehicleByID(ctx, 5)
+	if again.Features[0] != "Bluetooth" || again.Brand.Name != "Toyota" {
+		t.Errorf("el store se modificó a través de una copia: %+v", again)
+	}
+
+	// Las altas continúan después del mayor ID de la semilla
+	created, err := s.CreateVehicle(ctx, models.VehicleInput{
+		BrandID: 1, Model: "CX-5", Year: 2024, TypeID: 1, FuelTypeID: 1, TransmissionID: 1,
+	})
+	if err != nil || created.ID != 6 {
+		t.Errorf("CreateVehicle = %+v, %v; want ID 6", created, err)
+	}
+}
+
+func TestNewStoreRejectsInvalidSeed(t *testing.T) {
+	tests := map[string]func(*Seed){
+		"marca inexistente":  func(s *Seed) { s.Vehicles[0].BrandID = 9 },
+		"año fuera de rango": func(s *Seed) { s.Vehicles[0].Year = 1800 },
+		"ID duplicado": func(s *Seed) {
+			dup := s.Vehicles[0]
+			dup.Model = "Camry"
+			s.Vehicles = append(s.Vehicles, dup)
+		},
+	}
+	for name, mutate := range tests {
+		seed := testSeed()
+		mutate(&seed)
+		if _, err := NewStore(seed); err == nil {
+			t.Errorf("%s: NewStore no devolvió error", name)
+		}
+	}
+}
+
+func TestStoreWrites(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewStore(testSeed())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.CreateVehicle(ctx, models.VehicleInput{
+		BrandID: 2, Model: "corolla", Year: 2024, TypeID: 1, FuelTypeID: 1, TransmissionID: 1,
+	})
+	if !errors.Is(err, database.ErrDuplicateVehicle) {
+		t.Errorf("alta duplicada = %v, want ErrDuplicateVehicle", err)
+	}
+
+	_, err = s.UpdateVehicle(ctx, 5, models.VehicleInput{BrandID: 2, Model: "Corolla", Year: 2024, TypeID: 1, FuelTypeID: 9, TransmissionID: 1})
+	var errs models.ValidationErrors
+	if !errors.As(err, &errs) || errs["fuel_type_id"] == "" {
+		t.Errorf("catálogo inexistente = %v, want error en fuel_type_id", err)
+	}
+
+	price := 410000.0
+	patched, err := s.PatchVehicle(ctx, 5, models.VehiclePatch{Price: &price})
+	if err != nil || patched.Price != price || patched.Model != "Corolla" {
+		t.Errorf("PatchVehicle = %+v, %v", patched, err)
+	}
+
+	// Borrar el vehículo limpia la selección de las búsquedas que lo referencian
+	id := 5
+	search := models.UserSearch{SessionID: "s1", SelectedVehicleID: &id}
+	if err := s.CreateUserSearch(ctx, &search); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeleteVehicle(ctx, 5); err != nil {
+		t.Fatal(err)
+	}
+	if s.searches[search.ID].SelectedVehicleID != nil {
+		t.Error("la búsqueda conserva el vehículo eliminado")
+	}
+	if _, err := s.GetVehicleByID(ctx, 5); !errors.Is(err, database.ErrVehicleNotFound) {
+		t.Errorf("GetVehicleByID tras borrar = %v", err)
+	}
+	if err := s.DeleteVehicle(ctx, 5); !errors.Is(err, database.ErrVehicleNotFound) {
+		t.Errorf("borrar dos veces = %v", err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seed.json")
+	if err := os.WriteFile(path, []byte(`{"brands": [{"id": 1, "name": "Mazda"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if brands, _ := s.GetBrands(context.Background()); len(brands) != 1 {
+		t.Errorf("marcas = %+v", brands)
+	}
+
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "no-existe.json")); err == nil {
+		t.Error("LoadFile de un archivo inexistente no devolvió error")
+	}
+}
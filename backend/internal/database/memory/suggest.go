@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vehiculos/backend/internal/database"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+var _ database.SuggestionStore = (*Store)(nil)
+
+// ListSuggestions lista las marcas con vehículos, cada modelo por marca con su rango de años
+// y las características, agrupando las variantes de mayúsculas como la base de datos
+func (s *Store) ListSuggestions(_ context.Context) ([]models.Suggestion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	brands := make(map[int]*models.Suggestion)
+	vehicleModels := make(map[string]*models.Suggestion)
+	features := make(map[string]*models.Suggestion)
+	featureVehicles := make(map[string]map[int]bool)
+
+	for _, rec := range s.vehicles {
+		v := rec.vehicle
+		brandName := ""
+		if v.Brand != nil {
+			brandName = v.Brand.Name
+		}
+
+		brand, ok := brands[v.BrandID]
+		if !ok {
+			brand = &models.Suggestion{Type: models.SuggestionBrand, Text: brandName, BrandID: v.BrandID, Brand: brandName}
+			brands[v.BrandID] = brand
+		}
+		brand.Count++
+
+		key := fmt.Sprintf("%d:%s", v.BrandID, strings.ToLower(v.Model))
+		model, ok := vehicleModels[key]
+		if !ok {
+			model = &models.Suggestion{
+				Type: models.SuggestionModel, BrandID: v.BrandID, Brand: brandName,
+				Model: v.Model, YearMin: v.Year, YearMax: v.Year,
+			}
+			vehicleModels[key] = model
+		}
+		if v.Model < model.Model {
+			model.Model = v.Model
+		}
+		if v.Year < model.YearMin {
+			model.YearMin = v.Year
+		}
+		if v.Year > model.YearMax {
+			model.YearMax = v.Year
+		}
+		model.Text = brandName + " " + model.Model
+		model.Count++
+
+		for _, f := range v.Features {
+			lower := strings.ToLower(f)
+			feature, ok := features[lower]
+			if !ok {
+				feature = &models.Suggestion{Type: models.SuggestionFeature, Text: f, Feature: lower}
+				features[lower] = feature
+				featureVehicles[lower] = make(map[int]bool)
+			}
+			if f < feature.Text {
+				feature.Text = f
+			}
+			featureVehicles[lower][v.ID] = true
+		}
+	}
+
+	suggestions := make([]models.Suggestion, 0, len(brands)+len(vehicleModels)+len(features))
+	for _, b := range brands {
+		suggestions = append(suggestions, *b)
+	}
+	for _, m := range vehicleModels {
+		suggestions = append(suggestions, *m)
+	}
+	for lower, f := range features {
+		f.Count = len(featureVehicles[lower])
+		suggestions = append(suggestions, *f)
+	}
+	return suggestions, nil
+}
+
+// CatalogVersion cambia con cada alta, modificación o baja de un vehículo
+func (s *Store) CatalogVersion(_ context.Context) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var updatedAt time.Time
+	for _, rec := range s.vehicles {
+		if rec.vehicle.UpdatedAt.After(updatedAt) {
+			updatedAt = rec.vehicle.UpdatedAt
+		}
+	}
+	return fmt.Sprintf("%d:%d", len(s.vehicles), updatedAt.UnixNano()), nil
+}
@@ -0,0 +1,288 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// SortKey es una llave de ordenamiento: la expresión SQL y el mismo valor leído del modelo,
+// que usan los cursores y el backend en memoria para ordenar igual que PostgreSQL
+type SortKey struct {
+	Column string
+	Desc   bool
+	Value  func(v models.Vehicle) interface{}
+}
+
+// ErrInvalidSort indica un sort_by con un campo desconocido, sin dirección o repetido
+var ErrInvalidSort = errors.New("sort_by inválido")
+
+// Llaves de price_per_hp: primero se separan los vehículos sin potencia registrada, que van al
+// final en ambas direcciones, y luego se ordena por el cociente calculado en float8 para que
+// coincida con la división de Go y el cursor compare valores idénticos
+const (
+	hasHorsepower      = `CASE WHEN COALESCE(v.horsepower, 0) > 0 THEN 0 ELSE 1 END`
+	pricePerHorsepower = `CASE WHEN COALESCE(v.horsepower, 0) > 0 THEN v.price::float8 / v.horsepower ELSE 0 END`
+)
+
+// sortField es un campo ordenable; fixed marca las llaves que no cambian de dirección
+type sortField struct {
+	keys  []SortKey
+	fixed []bool
+}
+
+// sortFields son los campos de sort_by; cada uno se pide con el sufijo _asc o _desc
+var sortFields = map[string]sortField{
+	"created":       {keys: []SortKey{{Column: "v.created_at", Value: vehicleCreatedAt}}},
+	"price":         {keys: []SortKey{{Column: "v.price", Value: vehiclePrice}}},
+	"year":          {keys: []SortKey{{Column: "v.year", Value: vehicleYear}}},
+	"fuel_economy":  {keys: []SortKey{{Column: "COALESCE(v.fuel_economy, 0)", Value: vehicleFuelEconomy}}},
+	"horsepower":    {keys: []SortKey{{Column: "COALESCE(v.horsepower, 0)", Value: vehicleHorsepower}}},
+	"torque":        {keys: []SortKey{{Column: "COALESCE(v.torque, 0)", Value: vehicleTorque}}},
+	"safety_rating": {keys: []SortKey{{Column: "COALESCE(v.safety_rating, 0)", Value: vehicleSafetyRating}}},
+	"cargo_space":   {keys: []SortKey{{Column: "COALESCE(v.cargo_space, 0)", Value: vehicleCargoSpace}}},
+	// brand y model se comparan por bytes con sólo A-Z en minúsculas: lower() bajo COLLATE "C"
+	// no toca otros caracteres y asciiLower hace lo mismo, así que PostgreSQL y strings.Compare
+	// ordenan igual sin importar la intercalación ni el locale de la base
+	"brand": {keys: []SortKey{{Column: `lower(b.name COLLATE "C")`, Value: vehicleBrandName}}},
+	"model": {keys: []SortKey{{Column: `lower(v.model COLLATE "C")`, Value: vehicleModel}}},
+	"price_per_hp": {
+		keys: []SortKey{
+			{Column: hasHorsepower, Value: vehicleHasHorsepower},
+			{Column: pricePerHorsepower, Value: vehiclePricePerHorsepower},
+		},
+		fixed: []bool{true, false},
+	},
+}
+
+// SearchSort devuelve las llaves de orden para filter.SortBy, una lista separada por comas
+// como "year_desc,price_asc". relevance ordena por el puntaje de la búsqueda de texto (sin
+// búsqueda todos empatan). Sin sort_by se ordena por relevance si hay búsqueda de texto y
+// por created_desc si no.
+// La última llave siempre es v.id para que el orden sea total y la paginación por cursor no
+// repita ni omita vehículos.
+func SearchSort(filter models.SearchFilter) ([]SortKey, error) {
+	var keys []SortKey
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(filter.SortBy, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, desc := part, true
+		switch {
+		case part == "relevance":
+		case strings.HasSuffix(part, "_desc"):
+			name = strings.TrimSuffix(part, "_desc")
+		case strings.HasSuffix(part, "_asc"):
+			name, desc = strings.TrimSuffix(part, "_asc"), false
+		default:
+			return nil, fmt.Errorf("%w: %q debe terminar en _asc o _desc", ErrInvalidSort, part)
+		}
+
+		if seen[name] {
+			return nil, fmt.Errorf("%w: %q aparece más de una vez", ErrInvalidSort, name)
+		}
+		seen[name] = true
+
+		if name == "relevance" {
+			keys = append(keys, SortKey{Column: relevanceExpr(filter), Desc: true, Value: vehicleRelevance})
+			continue
+		}
+
+		field, ok := sortFields[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: campo desconocido %q", ErrInvalidSort, name)
+		}
+		for i, k := range field.keys {
+			k.Desc = desc && (field.fixed == nil || !field.fixed[i])
+			keys = append(keys, k)
+		}
+	}
+
+	switch {
+	case len(keys) > 0:
+	case hasTextSearch(filter):
+		keys = []SortKey{{Column: relevanceExpr(filter), Desc: true, Value: vehicleRelevance}}
+	default:
+		keys = []SortKey{{Column: "v.created_at", Desc: true, Value: vehicleCreatedAt}}
+	}
+
+	return append(keys, SortKey{
+		Column: "v.id",
+		Desc:   keys[len(keys)-1].Desc,
+		Value:  func(v models.Vehicle) interface{} { return v.ID },
+	}), nil
+}
+
+func vehiclePrice(v models.Vehicle) interface{}        { return v.Price }
+func vehicleYear(v models.Vehicle) interface{}         { return v.Year }
+func vehicleFuelEconomy(v models.Vehicle) interface{}  { return v.FuelEconomy }
+func vehicleCreatedAt(v models.Vehicle) interface{}    { return v.CreatedAt }
+func vehicleHorsepower(v models.Vehicle) interface{}   { return v.Horsepower }
+func vehicleTorque(v models.Vehicle) interface{}       { return v.Torque }
+func vehicleSafetyRating(v models.Vehicle) interface{} { return v.SafetyRating }
+func vehicleCargoSpace(v models.Vehicle) interface{}   { return v.CargoSpace }
+func vehicleModel(v models.Vehicle) interface{}        { return asciiLower(v.Model) }
+func vehicleRelevance(v models.Vehicle) interface{}    { return v.Relevance }
+
+func vehicleBrandName(v models.Vehicle) interface{} {
+	if v.Brand == nil {
+		return ""
+	}
+	return asciiLower(v.Brand.Name)
+}
+
+// asciiLower pasa a minúsculas sólo A-Z, como lower() con la intercalación "C"
+func asciiLower(s string) string {
+	return strings.Map(func(r rune) rune {
+		if 'A' <= r && r <= 'Z' {
+			return r + ('a' - 'A')
+		}
+		return r
+	}, s)
+}
+
+func vehicleHasHorsepower(v models.Vehicle) interface{} {
+	if v.Horsepower > 0 {
+		return 0
+	}
+	return 1
+}
+
+func vehiclePricePerHorsepower(v models.Vehicle) interface{} {
+	if v.Horsepower > 0 {
+		return v.Price / float64(v.Horsepower)
+	}
+	return 0.0
+}
+
+// orderByClause construye el ORDER BY; reverse invierte cada dirección para leer hacia atrás
+func orderByClause(keys []SortKey, reverse bool) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		dir := "ASC"
+		if k.Desc != reverse {
+			dir = "DESC"
+		}
+		parts[i] = k.Column + " " + dir
+	}
+	return strings.Join(parts, ", ")
+}
+
+// keysetCondition filtra las filas posteriores (o anteriores, con before) a los valores del
+// cursor. Se expande como (a > x) OR (a = x AND b > y) ... porque las llaves pueden tener
+// direcciones distintas y la comparación de tuplas de SQL sólo admite una.
+func keysetCondition(keys []SortKey, values []interface{}, before bool, argStart int) (string, []interface{}) {
+	var alternatives []string
+	var args []interface{}
+	arg := argStart
+
+	for i, k := range keys {
+		var terms []string
+		for j := 0; j < i; j++ {
+			terms = append(terms, fmt.Sprintf("%s = $%d", keys[j].Column, arg))
+			args = append(args, values[j])
+			arg++
+		}
+
+		op := ">"
+		if k.Desc != before {
+			op = "<"
+		}
+		terms = append(terms, fmt.Sprintf("%s %s $%d", k.Column, op, arg))
+		args = append(args, values[i])
+		arg++
+
+		alternatives = append(alternatives, "("+strings.Join(terms, " AND ")+")")
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// CompareVehicles compara dos vehículos según las llaves: negativo si a va antes que b
+func CompareVehicles(keys []SortKey, a, b models.Vehicle) int {
+	for _, k := range keys {
+		if c := compareSortValues(k.Value(a), k.Value(b)); c != 0 {
+			if k.Desc {
+				return -c
+			}
+			return c
+		}
+	}
+	return 0
+}
+
+// CompareToCursor compara un vehículo con los valores de un cursor: negativo si el vehículo
+// va antes que la fila del cursor, positivo si va después
+func CompareToCursor(keys []SortKey, v models.Vehicle, values []interface{}) int {
+	for i, k := range keys {
+		if c := compareSortValues(k.Value(v), values[i]); c != 0 {
+			if k.Desc {
+				return -c
+			}
+			return c
+		}
+	}
+	return 0
+}
+
+func compareSortValues(a, b interface{}) int {
+	switch x := a.(type) {
+	case int:
+		y := b.(int)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	case float64:
+		y := b.(float64)
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	case string:
+		return strings.Compare(x, b.(string))
+	case time.Time:
+		return x.Compare(b.(time.Time))
+	}
+	return 0
+}
+
+// NewSearchPage arma la página a partir de las filas leídas en el orden de la consulta
+// (invertido si before) con una fila extra para saber si hay más. hasPrevious indica si la
+// página no es la primera cuando se avanza hacia adelante.
+func NewSearchPage(filter models.SearchFilter, keys []SortKey, fetched []models.Vehicle, limit int, before, hasPrevious bool) models.SearchPage {
+	hasMore := len(fetched) > limit
+	if hasMore {
+		fetched = fetched[:limit]
+	}
+	if before {
+		for i, j := 0, len(fetched)-1; i < j; i, j = i+1, j-1 {
+			fetched[i], fetched[j] = fetched[j], fetched[i]
+		}
+	}
+
+	page := models.SearchPage{Vehicles: fetched}
+	if len(fetched) == 0 {
+		return page
+	}
+
+	first, last := fetched[0], fetched[len(fetched)-1]
+	if (before && hasMore) || (!before && hasPrevious) {
+		page.PrevCursor = EncodeCursor(filter, keys, first, true)
+	}
+	if (!before && hasMore) || before {
+		page.NextCursor = EncodeCursor(filter, keys, last, false)
+	}
+	return page
+}
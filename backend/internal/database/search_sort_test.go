@@ -0,0 +1,187 @@
+package database
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+func TestKeysetCondition(t *testing.T) {
+	mixed, err := SearchSort(models.SearchFilter{SortBy: "price_asc,year_desc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := []interface{}{250000.0, 2022, 7}
+
+	tests := []struct {
+		name     string
+		keys     []SortKey
+		values   []interface{}
+		before   bool
+		argStart int
+		want     string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "una llave",
+			keys:     []SortKey{{Column: "v.id", Desc: true}},
+			values:   []interface{}{7},
+			argStart: 1,
+			want:     "((v.id < $1))",
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:     "varias llaves con direcciones mixtas",
+			keys:     mixed,
+			values:   values,
+			argStart: 3,
+			want: "((v.price > $3)" +
+				" OR (v.price = $4 AND v.year < $5)" +
+				" OR (v.price = $6 AND v.year = $7 AND v.id < $8))",
+			wantArgs: []interface{}{250000.0, 250000.0, 2022, 250000.0, 2022, 7},
+		},
+		{
+			name:     "hacia atrás invierte cada comparación",
+			keys:     mixed,
+			values:   values,
+			before:   true,
+			argStart: 1,
+			want: "((v.price < $1)" +
+				" OR (v.price = $2 AND v.year > $3)" +
+				" OR (v.price = $4 AND v.year = $5 AND v.id > $6))",
+			wantArgs: []interface{}{250000.0, 250000.0, 2022, 250000.0, 2022, 7},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args := keysetCondition(tt.keys, tt.values, tt.before, tt.argStart)
+			if got != tt.want {
+				t.Errorf("condición =\n  %s\nwant\n  %s", got, tt.want)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSearchSortPricePerHorsepowerKeepsMissingLast(t *testing.T) {
+	keys, err := SearchSort(models.SearchFilter{SortBy: "price_per_hp_desc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 3 || keys[0].Desc || !keys[1].Desc || !keys[2].Desc {
+		t.Errorf("direcciones = %v, %v, %v; want asc, desc, desc", keys[0].Desc, keys[1].Desc, keys[2].Desc)
+	}
+}
+
+func vehiclesWithIDs(ids ...int) []models.Vehicle {
+	vehicles := make([]models.Vehicle, len(ids))
+	for i, id := range ids {
+		vehicles[i] = models.Vehicle{ID: id, Price: float64(id * 1000)}
+	}
+	return vehicles
+}
+
+func TestNewSearchPageCursors(t *testing.T) {
+	filter := models.SearchFilter{SortBy: "price_asc"}
+	keys, err := SearchSort(filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		fetched     []models.Vehicle
+		before      bool
+		hasPrevious bool
+		wantIDs     []int
+		wantPrev    bool
+		wantNext    bool
+	}{
+		{"primera página", vehiclesWithIDs(1, 2, 3), false, false, []int{1, 2}, false, true},
+		{"página intermedia", vehiclesWithIDs(3, 4, 5), false, true, []int{3, 4}, true, true},
+		{"última página", vehiclesWithIDs(5), false, true, []int{5}, true, false},
+		{"hacia atrás con más páginas antes", vehiclesWithIDs(4, 3, 2), true, false, []int{3, 4}, true, true},
+		// Al volver hasta la primera página no queda página anterior
+		{"hacia atrás hasta la primera página", vehiclesWithIDs(2, 1), true, false, []int{1, 2}, false, true},
+		{"sin resultados", nil, false, true, []int{}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := NewSearchPage(filter, keys, tt.fetched, 2, tt.before, tt.hasPrevious)
+			ids := []int{}
+			for _, v := range page.Vehicles {
+				ids = append(ids, v.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+			if (page.PrevCursor != "") != tt.wantPrev {
+				t.Errorf("prev_cursor = %q, want presente = %v", page.PrevCursor, tt.wantPrev)
+			}
+			if (page.NextCursor != "") != tt.wantNext {
+				t.Errorf("next_cursor = %q, want presente = %v", page.NextCursor, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestSearchSort(t *testing.T) {
+	type key struct {
+		Column string
+		Desc   bool
+	}
+	tests := []struct {
+		name   string
+		filter models.SearchFilter
+		want   []key
+	}{
+		{"por defecto", models.SearchFilter{}, []key{{"v.created_at", true}, {"v.id", true}}},
+		{"varias llaves", models.SearchFilter{SortBy: "year_desc, price_asc"},
+			[]key{{"v.year", true}, {"v.price", false}, {"v.id", false}}},
+		{"nombre sin intercalación", models.SearchFilter{SortBy: "brand_asc"},
+			[]key{{`lower(b.name COLLATE "C")`, false}, {"v.id", false}}},
+		{"relevance por defecto con búsqueda", models.SearchFilter{Query: "suv"},
+			[]key{{relevanceExpr(models.SearchFilter{Query: "suv"}), true}, {"v.id", true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := SearchSort(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]key, len(keys))
+			for i, k := range keys {
+				got[i] = key{k.Column, k.Desc}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("llaves = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, sortBy := range []string{"color_asc", "price", "price_asc,price_desc"} {
+		if _, err := SearchSort(models.SearchFilter{SortBy: sortBy}); !errors.Is(err, ErrInvalidSort) {
+			t.Errorf("sort_by=%q: err = %v, want ErrInvalidSort", sortBy, err)
+		}
+	}
+}
+
+// TestAsciiLower revisa que sólo A-Z cambien, como lower() bajo COLLATE "C"; las letras
+// acentuadas quedan igual en ambos lados
+func TestAsciiLower(t *testing.T) {
+	tests := map[string]string{
+		"MAZDA CX-5": "mazda cx-5",
+		"ŠKODA":      "Škoda",
+		"Citroën":    "citroën",
+		"ÉBANO":      "Ébano",
+	}
+	for in, want := range tests {
+		if got := asciiLower(in); got != want {
+			t.Errorf("asciiLower(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+
+	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/scoring"
+)
+
+// CatalogStore expone los catálogos de filtros (marcas, tipos, combustibles, transmisiones)
+type CatalogStore interface {
+	GetBrands(ctx context.Context) ([]models.Brand, error)
+	GetVehicleTypes(ctx context.Context) ([]models.VehicleType, error)
+	GetFuelTypes(ctx context.Context) ([]models.FuelType, error)
+	GetTransmissions(ctx context.Context) ([]models.Transmission, error)
+}
+
+// VehicleStore expone la lectura, búsqueda y administración de vehículos
+type VehicleStore interface {
+	GetVehicleByID(ctx context.Context, id int) (*models.Vehicle, error)
+	GetVehiclesByIDs(ctx context.Context, ids []int) ([]models.Vehicle, error)
+	SearchVehicles(ctx context.Context, filter models.SearchFilter) (*models.SearchPage, error)
+	GetSearchFacets(ctx context.Context, filter models.SearchFilter) (*models.SearchFacets, error)
+	GetFeatureCounts(ctx context.Context, filter models.SearchFilter) ([]models.FeatureCount, error)
+	ExportVehicles(ctx context.Context, filter models.SearchFilter, fn func(models.Vehicle) error) error
+	GetScoringCatalog(ctx context.Context) (*scoring.Catalog, error)
+
+	CreateVehicle(ctx context.Context, input models.VehicleInput) (*models.Vehicle, error)
+	UpdateVehicle(ctx context.Context, id int, input models.VehicleInput) (*models.Vehicle, error)
+	PatchVehicle(ctx context.Context, id int, patch models.VehiclePatch) (*models.Vehicle, error)
+	DeleteVehicle(ctx context.Context, id int) error
+
+	GetVehicleImages(ctx context.Context, vehicleID int, imageType string) ([]models.VehicleImage, error)
+	AddVehicleImage(ctx context.Context, img *models.VehicleImage, displayOrder *int) error
+	ReorderVehicleImages(ctx context.Context, vehicleID int, imageIDs []int) error
+	DeleteVehicleImage(ctx context.Context, vehicleID, imageID int) error
+
+	GetVehicleSpecs(ctx context.Context, vehicleID int) ([]models.VehicleSpec, error)
+	UpsertVehicleSpecs(ctx context.Context, vehicleID int, specs []models.VehicleSpec) error
+	DeleteVehicleSpec(ctx context.Context, vehicleID int, name string) error
+}
+
+// ImportStore expone las escrituras de la importación masiva del catálogo. Los vehículos
+// se identifican por (marca, modelo sin distinguir mayúsculas, año). CreateBrand y
+// UpsertVehicle no invalidan la caché; InvalidateImport lo hace una vez para todo el lote.
+type ImportStore interface {
+	FindVehicleID(ctx context.Context, brandID int, model string, year int) (int, error)
+	CreateBrand(ctx context.Context, name string) (*models.Brand, error)
+	UpsertVehicle(ctx context.Context, input models.VehicleInput) (id int, created bool, err error)
+	InvalidateImport(ctx context.Context, vehicleIDs []int)
+}
+
+// SuggestionStore expone los datos del índice de autocompletado. CatalogVersion cambia
+// cada vez que se crea, modifica o elimina un vehículo, para saber cuándo reconstruirlo.
+type SuggestionStore interface {
+	ListSuggestions(ctx context.Context) ([]models.Suggestion, error)
+	CatalogVersion(ctx context.Context) (string, error)
+}
+
+// UserSearchStore guarda las búsquedas de los usuarios para analytics
+type UserSearchStore interface {
+	CreateUserSearch(ctx context.Context, search *models.UserSearch) error
+	SetSelectedVehicle(ctx context.Context, searchID int, sessionID string, vehicleID int) error
+}
+
+// UserPreferenceStore guarda las preferencias por sesión del asistente
+type UserPreferenceStore interface {
+	CreatePreference(ctx context.Context, pref *models.UserPreference) error
+	GetPreferencesBySession(ctx context.Context, sessionID string) ([]models.UserPreference, error)
+	DeletePreference(ctx context.Context, sessionID string, id int) error
+	DeleteSessionPreferences(ctx context.Context, sessionID string) error
+}
+
+var (
+	_ VehicleStore        = (*VehicleRepository)(nil)
+	_ CatalogStore        = (*VehicleRepository)(nil)
+	_ ImportStore         = (*VehicleRepository)(nil)
+	_ SuggestionStore     = (*VehicleRepository)(nil)
+	_ UserSearchStore     = (*UserSearchRepository)(nil)
+	_ UserPreferenceStore = (*UserPreferenceRepository)(nil)
+)
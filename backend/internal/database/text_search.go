@@ -0,0 +1,98 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// TrigramRankWeight es el peso de la similitud de trigramas en el puntaje combinado de la
+// búsqueda de texto sobre vehicles.search_text (migración 004)
+const TrigramRankWeight = 0.5
+
+// textSearchVector es el documento de texto completo; coincide con idx_vehicles_search_text_fts
+const textSearchVector = `to_tsvector('spanish', v.search_text)`
+
+// textQuery son las expresiones SQL de la búsqueda: Query (el texto que queda tras aplicar
+// los sinónimos) y los grupos de QueryExpansions, de los que basta una frase por grupo
+type textQuery struct {
+	term   string // Query normalizada; vacía si sólo hay grupos
+	all    string // tsquery con Query y todos los grupos
+	groups string // tsquery sólo con los grupos; vacía si no hay
+}
+
+// hasTextSearch indica si el filtro tiene búsqueda de texto
+func hasTextSearch(filter models.SearchFilter) bool {
+	return strings.TrimSpace(filter.Query) != "" || len(filter.QueryExpansions) > 0
+}
+
+// buildTextQuery arma las expresiones; value convierte cada texto en SQL (un parámetro en el
+// WHERE o un literal escapado en el ORDER BY)
+func buildTextQuery(filter models.SearchFilter, value func(string) string) textQuery {
+	var q textQuery
+	var groups []string
+	for _, group := range filter.QueryExpansions {
+		alternatives := make([]string, len(group))
+		for i, phrase := range group {
+			alternatives[i] = textSearchQuery(value(phrase))
+		}
+		groups = append(groups, "("+strings.Join(alternatives, " || ")+")")
+	}
+	q.groups = strings.Join(groups, " && ")
+
+	parts := groups
+	if strings.TrimSpace(filter.Query) != "" {
+		value := value(filter.Query)
+		q.term = normalizedSearchTerm(value)
+		parts = append([]string{textSearchQuery(value)}, groups...)
+	}
+	q.all = strings.Join(parts, " && ")
+	return q
+}
+
+// textSearchCondition filtra por la búsqueda: todas las palabras (con stemming en español y
+// sin acentos) o, como respaldo ante errores de escritura, una similitud de trigramas
+// suficiente con alguna parte del documento. Los grupos de sinónimos se exigen siempre.
+func textSearchCondition(filter models.SearchFilter, argStart int) (string, []interface{}) {
+	var args []interface{}
+	q := buildTextQuery(filter, func(text string) string {
+		args = append(args, text)
+		return fmt.Sprintf("$%d", argStart+len(args)-1)
+	})
+
+	switch {
+	case q.term == "":
+		return fmt.Sprintf("%s @@ (%s)", textSearchVector, q.groups), args
+	case q.groups == "":
+		return fmt.Sprintf("(%s @@ %s OR %s <%% v.search_text)", textSearchVector, q.all, q.term), args
+	}
+	return fmt.Sprintf("(%s @@ (%s) OR (%s <%% v.search_text AND %s @@ (%s)))",
+		textSearchVector, q.all, q.term, textSearchVector, q.groups), args
+}
+
+// relevanceExpr es el puntaje combinado de la fila: ts_rank más la similitud de trigramas
+// ponderada. Los textos van como literales escapados porque las llaves de orden son
+// expresiones sin parámetros, y se convierte a float8 para que el valor leído en Go sea
+// exactamente el que compara el cursor.
+func relevanceExpr(filter models.SearchFilter) string {
+	if !hasTextSearch(filter) {
+		return "0::float8"
+	}
+	q := buildTextQuery(filter, pq.QuoteLiteral)
+	score := fmt.Sprintf("ts_rank(%s, %s)", textSearchVector, q.all)
+	if q.term != "" {
+		score += fmt.Sprintf(" + %v * word_similarity(%s, v.search_text)", TrigramRankWeight, q.term)
+	}
+	return "(" + score + ")::float8"
+}
+
+func textSearchQuery(value string) string {
+	return fmt.Sprintf("plainto_tsquery('spanish', %s)", normalizedSearchTerm(value))
+}
+
+// normalizedSearchTerm aplica a la consulta la misma normalización que a search_text
+func normalizedSearchTerm(value string) string {
+	return fmt.Sprintf("lower(immutable_unaccent(%s))", value)
+}
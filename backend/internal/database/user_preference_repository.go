@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// ErrUserPreferenceNotFound indica que la preferencia no existe en la sesión
+var ErrUserPreferenceNotFound = errors.New("preferencia no encontrada")
+
+type UserPreferenceRepository struct {
+	db *DB
+}
+
+func NewUserPreferenceRepository(db *DB) *UserPreferenceRepository {
+	return &UserPreferenceRepository{db: db}
+}
+
+// CreatePreference guarda una preferencia de la sesión
+func (r *UserPreferenceRepository) CreatePreference(ctx context.Context, pref *models.UserPreference) error {
+	query := `
+		INSERT INTO user_preferences (session_id, preference_type, preference_value)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	return r.db.SQL.QueryRowContext(ctx, query,
+		pref.SessionID, string(pref.PreferenceType), pref.PreferenceValue,
+	).Scan(&pref.ID, &pref.CreatedAt)
+}
+
+// GetPreferencesBySession obtiene las preferencias de una sesión en orden cronológico
+func (r *UserPreferenceRepository) GetPreferencesBySession(ctx context.Context, sessionID string) ([]models.UserPreference, error) {
+	query := `
+		SELECT id, session_id, COALESCE(preference_type, ''), COALESCE(preference_value, ''), created_at
+		FROM user_preferences
+		WHERE session_id = $1
+		ORDER BY created_at, id
+	`
+	rows, err := r.db.SQL.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prefs := []models.UserPreference{}
+	for rows.Next() {
+		var p models.UserPreference
+		var prefType string
+		if err := rows.Scan(&p.ID, &p.SessionID, &prefType, &p.PreferenceValue, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		p.PreferenceType = models.PreferenceType(prefType)
+		prefs = append(prefs, p)
+	}
+
+	return prefs, rows.Err()
+}
+
+// DeletePreference elimina una preferencia concreta de la sesión
+func (r *UserPreferenceRepository) DeletePreference(ctx context.Context, sessionID string, id int) error {
+	result, err := r.db.SQL.ExecContext(ctx,
+		`DELETE FROM user_preferences WHERE id = $1 AND session_id = $2`, id, sessionID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUserPreferenceNotFound
+	}
+	return nil
+}
+
+// DeleteSessionPreferences elimina todas las preferencias de la sesión
+func (r *UserPreferenceRepository) DeleteSessionPreferences(ctx context.Context, sessionID string) error {
+	_, err := r.db.SQL.ExecContext(ctx, `DELETE FROM user_preferences WHERE session_id = $1`, sessionID)
+	return err
+}
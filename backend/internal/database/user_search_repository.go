@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// ErrUserSearchNotFound indica que la búsqueda no existe o pertenece a otra sesión
+var ErrUserSearchNotFound = errors.New("búsqueda no encontrada")
+
+type UserSearchRepository struct {
+	db *DB
+}
+
+func NewUserSearchRepository(db *DB) *UserSearchRepository {
+	return &UserSearchRepository{db: db}
+}
+
+// CreateUserSearch guarda una búsqueda con sus filtros como JSONB
+func (r *UserSearchRepository) CreateUserSearch(ctx context.Context, search *models.UserSearch) error {
+	filters, err := json.Marshal(search.Filters)
+	if err != nil {
+		return fmt.Errorf("error al serializar filtros: %w", err)
+	}
+
+	query := `
+		INSERT INTO user_searches (search_query, filters, results_count, selected_vehicle_id, session_id)
+		VALUES (NULLIF($1, ''), $2, $3, $4, NULLIF($5, ''))
+		RETURNING id, created_at
+	`
+
+	err = r.db.SQL.QueryRowContext(ctx, query,
+		search.SearchQuery, filters, search.ResultsCount, search.SelectedVehicleID, search.SessionID,
+	).Scan(&search.ID, &search.CreatedAt)
+	if isForeignKeyViolation(err) {
+		return ErrVehicleNotFound
+	}
+	return err
+}
+
+// SetSelectedVehicle registra el vehículo que el usuario eligió tras una búsqueda
+func (r *UserSearchRepository) SetSelectedVehicle(ctx context.Context, searchID int, sessionID string, vehicleID int) error {
+	query := `
+		UPDATE user_searches
+		SET selected_vehicle_id = $1
+		WHERE id = $2 AND session_id = $3
+	`
+
+	result, err := r.db.SQL.ExecContext(ctx, query, vehicleID, searchID, sessionID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrVehicleNotFound
+		}
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUserSearchNotFound
+	}
+	return nil
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23503"
+}
+
+// SearchLogger registra búsquedas en segundo plano para no añadir latencia a las peticiones.
+// Si la cola está llena las búsquedas se descartan: la analítica no debe frenar el catálogo.
+type SearchLogger struct {
+	repo   UserSearchStore
+	queue  chan models.UserSearch
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+func NewSearchLogger(repo UserSearchStore, bufferSize int) *SearchLogger {
+	l := &SearchLogger{
+		repo:  repo,
+		queue: make(chan models.UserSearch, bufferSize),
+	}
+
+	l.wg.Add(1)
+	go l.run()
+
+	return l
+}
+
+// Log encola una búsqueda sin bloquear
+func (l *SearchLogger) Log(search models.UserSearch) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.closed {
+		return
+	}
+
+	select {
+	case l.queue <- search:
+	default:
+		log.Println("Advertencia: cola de búsquedas llena, se descarta el registro")
+	}
+}
+
+// Close deja de aceptar búsquedas y espera a que se guarden las pendientes
+func (l *SearchLogger) Close() {
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.queue)
+	}
+	l.mu.Unlock()
+
+	l.wg.Wait()
+}
+
+func (l *SearchLogger) run() {
+	defer l.wg.Done()
+
+	for search := range l.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := l.repo.CreateUserSearch(ctx, &search); err != nil {
+			log.Printf("Error al registrar búsqueda: %v", err)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// recordingSearchStore guarda en memoria las búsquedas que recibe
+type recordingSearchStore struct {
+	mu       sync.Mutex
+	searches []models.UserSearch
+}
+
+func (s *recordingSearchStore) CreateUserSearch(_ context.Context, search *models.UserSearch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.searches = append(s.searches, *search)
+	return nil
+}
+
+func (s *recordingSearchStore) SetSelectedVehicle(context.Context, int, string, int) error {
+	return nil
+}
+
+func TestSearchLoggerFlushesOnClose(t *testing.T) {
+	store := &recordingSearchStore{}
+	logger := NewSearchLogger(store, 10)
+
+	for _, q := range []string{"suv", "sedan", "pickup"} {
+		logger.Log(models.UserSearch{SearchQuery: q, SessionID: "s1"})
+	}
+	logger.Close()
+
+	if len(store.searches) != 3 {
+		t.Fatalf("búsquedas guardadas = %d, want 3", len(store.searches))
+	}
+	for i, q := range []string{"suv", "sedan", "pickup"} {
+		if store.searches[i].SearchQuery != q {
+			t.Errorf("búsqueda %d = %q, want %q", i, store.searches[i].SearchQuery, q)
+		}
+	}
+
+	// Después de cerrar se descarta sin bloquear ni entrar en pánico
+	logger.Log(models.UserSearch{SearchQuery: "tarde"})
+	logger.Close()
+	if len(store.searches) != 3 {
+		t.Errorf("se guardó una búsqueda después de Close")
+	}
+}
@@ -0,0 +1,272 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/lib/pq"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// ErrDuplicateVehicle indica que ya existe otro vehículo con la misma marca, modelo y año
+var ErrDuplicateVehicle = errors.New("ya existe para esa marca y año")
+
+// naturalKeyIndex es el índice único de la llave natural (marca, modelo, año)
+const naturalKeyIndex = "idx_vehicles_natural_key"
+
+// constraintFields traduce las llaves foráneas de vehicles al campo de entrada correspondiente
+var constraintFields = map[string]string{
+	"vehicles_brand_id_fkey":        "brand_id",
+	"vehicles_type_id_fkey":         "type_id",
+	"vehicles_fuel_type_id_fkey":    "fuel_type_id",
+	"vehicles_transmission_id_fkey": "transmission_id",
+}
+
+// CreateVehicle inserta un vehículo y sus características en una sola transacción
+func (r *VehicleRepository) CreateVehicle(ctx context.Context, input models.VehicleInput) (*models.Vehicle, error) {
+	input.Normalize()
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO vehicles (
+			brand_id, model, year, type_id, price, currency, fuel_type_id, transmission_id,
+			doors, seats, engine_size, horsepower, torque, fuel_economy, tank_capacity,
+			cargo_space, image_url, description, safety_rating
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		RETURNING id
+	`
+
+	var id int
+	err = tx.QueryRowContext(ctx, query, vehicleInputArgs(input)...).Scan(&id)
+	if err != nil {
+		return nil, translateWriteError(err)
+	}
+
+	if err := replaceFeatures(ctx, tx, id, input.Features); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	r.invalidateVehicle(ctx, id)
+	return r.GetVehicleByID(ctx, id)
+}
+
+// UpdateVehicle reemplaza todos los datos de un vehículo, incluidas sus características
+func (r *VehicleRepository) UpdateVehicle(ctx context.Context, id int, input models.VehicleInput) (*models.Vehicle, error) {
+	input.Normalize()
+	if err := input.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := updateVehicleRow(ctx, tx, id, input); err != nil {
+		return nil, err
+	}
+	if err := replaceFeatures(ctx, tx, id, input.Features); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	r.invalidateVehicle(ctx, id)
+	return r.GetVehicleByID(ctx, id)
+}
+
+// PatchVehicle aplica una actualización parcial sobre el estado actual del vehículo.
+// La fila se bloquea con FOR UPDATE para que dos parches simultáneos no se pisen.
+func (r *VehicleRepository) PatchVehicle(ctx context.Context, id int, patch models.VehiclePatch) (*models.Vehicle, error) {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT
+			brand_id, model, year, type_id, price, COALESCE(currency, 'MXN'),
+			fuel_type_id, transmission_id, COALESCE(doors, 0), COALESCE(seats, 0),
+			COALESCE(engine_size, 0), COALESCE(horsepower, 0), COALESCE(torque, 0),
+			COALESCE(fuel_economy, 0), COALESCE(tank_capacity, 0), COALESCE(cargo_space, 0),
+			COALESCE(image_url, ''), COALESCE(description, ''), COALESCE(safety_rating, 0),
+			COALESCE((SELECT array_agg(feature ORDER BY feature) FROM vehicle_features WHERE vehicle_id = $1), '{}')
+		FROM vehicles
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	var in models.VehicleInput
+	var features pq.StringArray
+	err = tx.QueryRowContext(ctx, query, id).Scan(
+		&in.BrandID, &in.Model, &in.Year, &in.TypeID, &in.Price, &in.Currency,
+		&in.FuelTypeID, &in.TransmissionID, &in.Doors, &in.Seats,
+		&in.EngineSize, &in.Horsepower, &in.Torque,
+		&in.FuelEconomy, &in.TankCapacity, &in.CargoSpace,
+		&in.ImageURL, &in.Description, &in.SafetyRating,
+		&features,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrVehicleNotFound
+		}
+		return nil, err
+	}
+	in.Features = features
+
+	patch.ApplyTo(&in)
+	in.Normalize()
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := updateVehicleRow(ctx, tx, id, in); err != nil {
+		return nil, err
+	}
+	if patch.Features != nil {
+		if err := replaceFeatures(ctx, tx, id, in.Features); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	r.invalidateVehicle(ctx, id)
+	return r.GetVehicleByID(ctx, id)
+}
+
+// DeleteVehicle elimina un vehículo; las características se borran en cascada
+func (r *VehicleRepository) DeleteVehicle(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Las búsquedas registradas conservan el historial aunque el vehículo desaparezca
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE user_searches SET selected_vehicle_id = NULL WHERE selected_vehicle_id = $1`, id); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM vehicles WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrVehicleNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	r.invalidateVehicle(ctx, id)
+	return nil
+}
+
+func updateVehicleRow(ctx context.Context, tx *sql.Tx, id int, input models.VehicleInput) error {
+	query := `
+		UPDATE vehicles SET
+			brand_id = $1, model = $2, year = $3, type_id = $4, price = $5, currency = $6,
+			fuel_type_id = $7, transmission_id = $8, doors = $9, seats = $10, engine_size = $11,
+			horsepower = $12, torque = $13, fuel_economy = $14, tank_capacity = $15,
+			cargo_space = $16, image_url = $17, description = $18, safety_rating = $19
+		WHERE id = $20
+	`
+
+	args := append(vehicleInputArgs(input), id)
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateWriteError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrVehicleNotFound
+	}
+	return nil
+}
+
+// replaceFeatures sustituye el conjunto de características del vehículo
+func replaceFeatures(ctx context.Context, tx *sql.Tx, vehicleID int, features []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM vehicle_features WHERE vehicle_id = $1`, vehicleID); err != nil {
+		return err
+	}
+	if len(features) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO vehicle_features (vehicle_id, feature)
+		SELECT $1, unnest($2::text[])
+		ON CONFLICT (vehicle_id, feature) DO NOTHING
+	`, vehicleID, pq.Array(features))
+	return err
+}
+
+// vehicleInputArgs devuelve los parámetros de INSERT y UPDATE en el orden de sus columnas.
+// Sin dato de asientos se guarda NULL, porque la columna exige al menos 1.
+func vehicleInputArgs(in models.VehicleInput) []interface{} {
+	seats := sql.NullInt64{Int64: int64(in.Seats), Valid: in.Seats > 0}
+	return []interface{}{
+		in.BrandID, in.Model, in.Year, in.TypeID, in.Price, in.Currency,
+		in.FuelTypeID, in.TransmissionID, in.Doors, seats, in.EngineSize,
+		in.Horsepower, in.Torque, in.FuelEconomy, in.TankCapacity,
+		in.CargoSpace, in.ImageURL, in.Description, in.SafetyRating,
+	}
+}
+
+// translateWriteError convierte violaciones de llave foránea en errores de validación y
+// la violación de la llave natural en ErrDuplicateVehicle
+func translateWriteError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	switch pqErr.Code {
+	case "23503":
+		if field, ok := constraintFields[pqErr.Constraint]; ok {
+			return models.ValidationErrors{field: "no existe"}
+		}
+	case "23505":
+		if pqErr.Constraint == naturalKeyIndex {
+			return ErrDuplicateVehicle
+		}
+	}
+	return err
+}
+
+// invalidateVehicle invalida el detalle del vehículo y las búsquedas cacheadas que podrían incluirlo
+func (r *VehicleRepository) invalidateVehicle(ctx context.Context, id int) {
+	if err := r.invalidateTags(ctx, VehicleTag(id), TagSearch); err != nil {
+		log.Printf("Error al invalidar caché del vehículo %d: %v", id, err)
+	}
+}
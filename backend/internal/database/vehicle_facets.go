@@ -0,0 +1,174 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// PriceBucketSize es el ancho de cada rango del histograma de precios (MXN)
+const PriceBucketSize = 100000
+
+// GetSearchFacets calcula los conteos por marca, tipo, combustible, transmisión,
+// precio y año para el filtro dado. Cada dimensión ignora su propio filtro.
+func (r *VehicleRepository) GetSearchFacets(ctx context.Context, filter models.SearchFilter) (*models.SearchFacets, error) {
+	// La paginación, el orden y el modo de conteo no afectan a las facetas
+	filter.Page, filter.Limit, filter.SortBy = 0, 0, ""
+	filter.Cursor, filter.Count = "", ""
+
+	key := filterCacheKey("facets:", filter)
+	return cacheAside(ctx, r, key, searchCachePolicy, []string{TagSearch},
+		func(ctx context.Context) (*models.SearchFacets, error) {
+			return r.computeSearchFacets(ctx, filter)
+		})
+}
+
+func (r *VehicleRepository) computeSearchFacets(ctx context.Context, filter models.SearchFilter) (*models.SearchFacets, error) {
+	var facets models.SearchFacets
+	var err error
+
+	if facets.Brands, err = r.countByCatalog(ctx, filter, facetBrand, "brands", "v.brand_id"); err != nil {
+		return nil, err
+	}
+	if facets.VehicleTypes, err = r.countByCatalog(ctx, filter, facetType, "vehicle_types", "v.type_id"); err != nil {
+		return nil, err
+	}
+	if facets.FuelTypes, err = r.countByCatalog(ctx, filter, facetFuelType, "fuel_types", "v.fuel_type_id"); err != nil {
+		return nil, err
+	}
+	if facets.Transmissions, err = r.countByCatalog(ctx, filter, facetTransmission, "transmissions", "v.transmission_id"); err != nil {
+		return nil, err
+	}
+	if facets.Price, err = r.histogram(ctx, filter, facetPrice, "v.price", PriceBucketSize); err != nil {
+		return nil, err
+	}
+	if facets.Year, err = r.histogram(ctx, filter, facetYear, "v.year", 1); err != nil {
+		return nil, err
+	}
+
+	return &facets, nil
+}
+
+// GetFeatureCounts lista las características distintas con el número de vehículos que las
+// tienen entre los que cumplen el filtro, de la más a la menos común. features_any no se
+// aplica para que la selección múltiple siga mostrando las demás opciones.
+func (r *VehicleRepository) GetFeatureCounts(ctx context.Context, filter models.SearchFilter) ([]models.FeatureCount, error) {
+	filter.Page, filter.Limit, filter.SortBy = 0, 0, ""
+	filter.Cursor, filter.Count = "", ""
+
+	key := filterCacheKey("features:", filter)
+	return cacheAside(ctx, r, key, searchCachePolicy, []string{TagSearch},
+		func(ctx context.Context) ([]models.FeatureCount, error) {
+			return r.countFeatures(ctx, filter)
+		})
+}
+
+func (r *VehicleRepository) countFeatures(ctx context.Context, filter models.SearchFilter) ([]models.FeatureCount, error) {
+	conditions, args := buildSearchConditions(filter, facetFeatures)
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Las variantes de mayúsculas se agrupan y se muestra la primera alfabéticamente
+	query := fmt.Sprintf(`
+		SELECT MIN(f.feature), COUNT(DISTINCT v.id)
+		FROM vehicle_features f
+		JOIN vehicles v ON v.id = f.vehicle_id
+		%s
+		GROUP BY lower(f.feature)
+		ORDER BY COUNT(DISTINCT v.id) DESC, lower(f.feature)
+	`, whereClause)
+
+	rows, err := r.db.SQL.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []models.FeatureCount{}
+	for rows.Next() {
+		var fc models.FeatureCount
+		if err := rows.Scan(&fc.Feature, &fc.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, fc)
+	}
+
+	return counts, rows.Err()
+}
+
+// countByCatalog cuenta vehículos por cada fila de la tabla de catálogo, incluyendo las que tienen 0
+func (r *VehicleRepository) countByCatalog(ctx context.Context, filter models.SearchFilter, dimension, table, column string) ([]models.FacetCount, error) {
+	conditions, args := buildSearchConditions(filter, dimension)
+
+	joinConditions := []string{fmt.Sprintf("%s = c.id", column)}
+	joinConditions = append(joinConditions, conditions...)
+
+	query := fmt.Sprintf(`
+		SELECT c.id, c.name, COUNT(v.id)
+		FROM %s c
+		LEFT JOIN vehicles v ON %s
+		GROUP BY c.id, c.name
+		ORDER BY c.name
+	`, table, strings.Join(joinConditions, " AND "))
+
+	rows, err := r.db.SQL.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []models.FacetCount{}
+	for rows.Next() {
+		var fc models.FacetCount
+		if err := rows.Scan(&fc.ID, &fc.Name, &fc.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, fc)
+	}
+
+	return counts, rows.Err()
+}
+
+// histogram agrupa los vehículos en rangos de ancho bucketSize sobre la columna dada
+func (r *VehicleRepository) histogram(ctx context.Context, filter models.SearchFilter, dimension, column string, bucketSize float64) ([]models.HistogramBucket, error) {
+	conditions, args := buildSearchConditions(filter, dimension)
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT floor(%s / $%d) AS bucket, COUNT(*)
+		FROM vehicles v
+		%s
+		GROUP BY bucket
+		ORDER BY bucket
+	`, column, len(args)+1, whereClause)
+	args = append(args, bucketSize)
+
+	rows, err := r.db.SQL.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []models.HistogramBucket{}
+	for rows.Next() {
+		var bucket float64
+		var hb models.HistogramBucket
+		if err := rows.Scan(&bucket, &hb.Count); err != nil {
+			return nil, err
+		}
+		hb.Min = bucket * bucketSize
+		hb.Max = hb.Min + bucketSize
+		buckets = append(buckets, hb)
+	}
+
+	return buckets, rows.Err()
+}
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// ErrVehicleImageNotFound indica que la imagen no existe para el vehículo
+var ErrVehicleImageNotFound = errors.New("imagen no encontrada")
+
+// ErrInvalidImageOrder indica que el nuevo orden no incluye exactamente las imágenes del vehículo
+var ErrInvalidImageOrder = errors.New("el orden debe incluir todas las imágenes del vehículo")
+
+// GetVehicleImages obtiene la galería del vehículo, opcionalmente filtrada por tipo
+func (r *VehicleRepository) GetVehicleImages(ctx context.Context, vehicleID int, imageType string) ([]models.VehicleImage, error) {
+	query := `
+		SELECT id, vehicle_id, image_url, COALESCE(image_type, ''), COALESCE(display_order, 0), created_at
+		FROM vehicle_images
+		WHERE vehicle_id = $1 AND ($2 = '' OR image_type = $2)
+		ORDER BY display_order, id
+	`
+	rows, err := r.db.SQL.QueryContext(ctx, query, vehicleID, imageType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	images := []models.VehicleImage{}
+	for rows.Next() {
+		var img models.VehicleImage
+		if err := rows.Scan(&img.ID, &img.VehicleID, &img.ImageURL, &img.ImageType, &img.DisplayOrder, &img.CreatedAt); err != nil {
+			return nil, err
+		}
+		images = append(images, img)
+	}
+
+	return images, rows.Err()
+}
+
+// AddVehicleImage agrega una imagen al final de la galería salvo que se indique su posición
+func (r *VehicleRepository) AddVehicleImage(ctx context.Context, img *models.VehicleImage, displayOrder *int) error {
+	query := `
+		INSERT INTO vehicle_images (vehicle_id, image_url, image_type, display_order)
+		VALUES ($1, $2, $3, COALESCE($4, (SELECT COALESCE(MAX(display_order), -1) + 1 FROM vehicle_images WHERE vehicle_id = $1)))
+		RETURNING id, display_order, created_at
+	`
+	err := r.db.SQL.QueryRowContext(ctx, query, img.VehicleID, img.ImageURL, img.ImageType, displayOrder).
+		Scan(&img.ID, &img.DisplayOrder, &img.CreatedAt)
+	if isForeignKeyViolation(err) {
+		return ErrVehicleNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	r.invalidateVehicle(ctx, img.VehicleID)
+	return nil
+}
+
+// ReorderVehicleImages asigna display_order según la posición de cada ID en imageIDs
+func (r *VehicleRepository) ReorderVehicleImages(ctx context.Context, vehicleID int, imageIDs []int) error {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var total, matched int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE id = ANY($2))
+		FROM vehicle_images
+		WHERE vehicle_id = $1
+	`, vehicleID, pq.Array(imageIDs)).Scan(&total, &matched)
+	if err != nil {
+		return err
+	}
+	if total != len(imageIDs) || matched != len(imageIDs) {
+		return ErrInvalidImageOrder
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE vehicle_images vi
+		SET display_order = o.position - 1
+		FROM unnest($2::int[]) WITH ORDINALITY AS o(id, position)
+		WHERE vi.id = o.id AND vi.vehicle_id = $1
+	`, vehicleID, pq.Array(imageIDs))
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	r.invalidateVehicle(ctx, vehicleID)
+	return nil
+}
+
+// DeleteVehicleImage elimina una imagen de la galería del vehículo
+func (r *VehicleRepository) DeleteVehicleImage(ctx context.Context, vehicleID, imageID int) error {
+	result, err := r.db.SQL.ExecContext(ctx,
+		`DELETE FROM vehicle_images WHERE id = $1 AND vehicle_id = $2`, imageID, vehicleID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrVehicleImageNotFound
+	}
+
+	r.invalidateVehicle(ctx, vehicleID)
+	return nil
+}
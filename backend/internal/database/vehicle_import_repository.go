@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// FindVehicleID busca un vehículo por su llave natural (marca, modelo, año)
+func (r *VehicleRepository) FindVehicleID(ctx context.Context, brandID int, model string, year int) (int, error) {
+	var id int
+	err := r.db.SQL.QueryRowContext(ctx,
+		`SELECT id FROM vehicles WHERE brand_id = $1 AND lower(model) = lower($2) AND year = $3`,
+		brandID, model, year,
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrVehicleNotFound
+	}
+	return id, err
+}
+
+// CreateBrand da de alta una marca; si ya existe con ese nombre devuelve la existente
+func (r *VehicleRepository) CreateBrand(ctx context.Context, name string) (*models.Brand, error) {
+	query := `
+		WITH inserted AS (
+			INSERT INTO brands (name) VALUES ($1)
+			ON CONFLICT (name) DO NOTHING
+			RETURNING id, name, COALESCE(logo, ''), COALESCE(country, ''), created_at, updated_at
+		)
+		SELECT * FROM inserted
+		UNION ALL
+		SELECT id, name, COALESCE(logo, ''), COALESCE(country, ''), created_at, updated_at
+		FROM brands WHERE name = $1
+		LIMIT 1
+	`
+
+	var b models.Brand
+	err := r.db.SQL.QueryRowContext(ctx, query, name).Scan(
+		&b.ID, &b.Name, &b.Logo, &b.Country, &b.CreatedAt, &b.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// UpsertVehicle inserta el vehículo o, si ya existe su llave natural, reemplaza sus datos
+// y características. created indica si la fila es nueva.
+func (r *VehicleRepository) UpsertVehicle(ctx context.Context, input models.VehicleInput) (int, bool, error) {
+	input.Normalize()
+	if err := input.Validate(); err != nil {
+		return 0, false, err
+	}
+
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO vehicles (
+			brand_id, model, year, type_id, price, currency, fuel_type_id, transmission_id,
+			doors, seats, engine_size, horsepower, torque, fuel_economy, tank_capacity,
+			cargo_space, image_url, description, safety_rating
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		ON CONFLICT (brand_id, lower(model), year) DO UPDATE SET
+			model = EXCLUDED.model, type_id = EXCLUDED.type_id, price = EXCLUDED.price,
+			currency = EXCLUDED.currency, fuel_type_id = EXCLUDED.fuel_type_id,
+			transmission_id = EXCLUDED.transmission_id, doors = EXCLUDED.doors, seats = EXCLUDED.seats,
+			engine_size = EXCLUDED.engine_size, horsepower = EXCLUDED.horsepower, torque = EXCLUDED.torque,
+			fuel_economy = EXCLUDED.fuel_economy, tank_capacity = EXCLUDED.tank_capacity,
+			cargo_space = EXCLUDED.cargo_space, image_url = EXCLUDED.image_url,
+			description = EXCLUDED.description, safety_rating = EXCLUDED.safety_rating
+		RETURNING id, (xmax = 0)
+	`
+
+	var id int
+	var created bool
+	if err := tx.QueryRowContext(ctx, query, vehicleInputArgs(input)...).Scan(&id, &created); err != nil {
+		return 0, false, translateWriteError(err)
+	}
+
+	if err := replaceFeatures(ctx, tx, id, input.Features); err != nil {
+		return 0, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, false, err
+	}
+	return id, created, nil
+}
+
+// InvalidateImport invalida en una sola llamada las búsquedas, los catálogos y el detalle de
+// los vehículos escritos por una importación
+func (r *VehicleRepository) InvalidateImport(ctx context.Context, vehicleIDs []int) {
+	tags := []string{TagSearch, TagCatalog}
+	for _, id := range vehicleIDs {
+		tags = append(tags, VehicleTag(id))
+	}
+	if err := r.invalidateTags(ctx, tags...); err != nil {
+		log.Printf("Error al invalidar caché de la importación: %v", err)
+	}
+}
@@ -2,191 +2,220 @@ package database
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/lib/pq"
+	"github.com/vehiculos/backend/internal/cache"
 	"github.com/vehiculos/backend/internal/models"
+	"golang.org/x/sync/singleflight"
 )
 
+// ErrVehicleNotFound indica que un vehículo referenciado no existe
+var ErrVehicleNotFound = errors.New("vehículo no encontrado")
+
 type VehicleRepository struct {
-	db *DB
+	db    *DB
+	cache cache.Cache
+	group singleflight.Group
+	gens  tagGenerations
+
+	scoring           atomic.Pointer[scoringSnapshot]
+	scoringRefreshing atomic.Bool
 }
 
 func NewVehicleRepository(db *DB) *VehicleRepository {
-	return &VehicleRepository{db: db}
+	return &VehicleRepository{db: db, cache: db.Cache}
 }
 
-// GetAllVehicles obtiene todos los vehículos con paginación
-func (r *VehicleRepository) GetAllVehicles(ctx context.Context, page, limit int) ([]models.Vehicle, int, error) {
-	if page < 1 {
-		page = 1
-	}
-	if limit < 1 || limit > 100 {
-		limit = 20
-	}
+// vehicleColumns son las columnas que leen todas las consultas de vehículos; las columnas
+// opcionales del esquema se normalizan con COALESCE para que el Scan nunca reciba NULL
+const vehicleColumns = `
+	v.id, v.brand_id, v.model, v.year, v.type_id,
+	v.price, COALESCE(v.currency, 'MXN'), v.fuel_type_id, v.transmission_id,
+	COALESCE(v.doors, 0), COALESCE(v.seats, 0), COALESCE(v.engine_size, 0),
+	COALESCE(v.horsepower, 0), COALESCE(v.torque, 0),
+	COALESCE(v.fuel_economy, 0), COALESCE(v.tank_capacity, 0), COALESCE(v.cargo_space, 0),
+	COALESCE(v.image_url, ''), COALESCE(v.description, ''), COALESCE(v.safety_rating, 0),
+	v.created_at, v.updated_at,
+	b.id, b.name, COALESCE(b.logo, ''), COALESCE(b.country, ''),
+	vt.id, vt.name,
+	ft.id, ft.name,
+	t.id, t.name`
+
+// vehicleJoins une el vehículo con sus catálogos
+const vehicleJoins = `
+	FROM vehicles v
+	JOIN brands b ON v.brand_id = b.id
+	JOIN vehicle_types vt ON v.type_id = vt.id
+	JOIN fuel_types ft ON v.fuel_type_id = ft.id
+	JOIN transmissions t ON v.transmission_id = t.id`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	offset := (page - 1) * limit
+// scanVehicle lee una fila con las columnas de vehicleColumns; extra recibe las columnas
+// adicionales que la consulta seleccione después de ellas
+func scanVehicle(row rowScanner, extra ...interface{}) (models.Vehicle, error) {
+	var v models.Vehicle
+	var brand models.Brand
+	var vType models.VehicleType
+	var fuelType models.FuelType
+	var transmission models.Transmission
 
-	// Contar total de vehículos
-	var total int
-	countQuery := `SELECT COUNT(*) FROM vehicles`
-	err := r.db.SQL.QueryRowContext(ctx, countQuery).Scan(&total)
+	dest := []interface{}{
+		&v.ID, &v.BrandID, &v.Model, &v.Year, &v.TypeID,
+		&v.Price, &v.Currency, &v.FuelTypeID, &v.TransmissionID,
+		&v.Doors, &v.Seats, &v.EngineSize, &v.Horsepower, &v.Torque,
+		&v.FuelEconomy, &v.TankCapacity, &v.CargoSpace,
+		&v.ImageURL, &v.Description, &v.SafetyRating,
+		&v.CreatedAt, &v.UpdatedAt,
+		&brand.ID, &brand.Name, &brand.Logo, &brand.Country,
+		&vType.ID, &vType.Name,
+		&fuelType.ID, &fuelType.Name,
+		&transmission.ID, &transmission.Name,
+	}
+	err := row.Scan(append(dest, extra...)...)
 	if err != nil {
-		return nil, 0, err
+		return v, err
 	}
 
-	// Obtener vehículos con información relacionada
-	query := `
-		SELECT 
-			v.id, v.brand_id, v.model, v.year, v.type_id, 
-			v.price, v.currency, v.fuel_type_id, v.transmission_id,
-			v.doors, v.seats, v.engine_size, v.horsepower, v.torque,
-			v.fuel_economy, v.tank_capacity, v.cargo_space,
-			v.image_url, v.description, v.safety_rating,
-			v.created_at, v.updated_at,
-			b.name, b.logo, b.country,
-			vt.name, ft.name, t.name
-		FROM vehicles v
-		JOIN brands b ON v.brand_id = b.id
-		JOIN vehicle_types vt ON v.type_id = vt.id
-		JOIN fuel_types ft ON v.fuel_type_id = ft.id
-		JOIN transmissions t ON v.transmission_id = t.id
-		ORDER BY v.created_at DESC
-		LIMIT $1 OFFSET $2
-	`
+	v.Brand = &brand
+	v.Type = &vType
+	v.FuelType = &fuelType
+	v.Transmission = &transmission
+	v.Features = []string{}
+
+	return v, nil
+}
+
+// queryVehicles ejecuta una consulta de vehículos y carga sus características en una sola
+// consulta adicional, de modo que el número de consultas no depende del número de filas
+func (r *VehicleRepository) queryVehicles(ctx context.Context, query string, args ...interface{}) ([]models.Vehicle, error) {
+	return r.collectVehicles(ctx, false, query, args...)
+}
+
+// queryRankedVehicles es queryVehicles para consultas que agregan la relevancia como última columna
+func (r *VehicleRepository) queryRankedVehicles(ctx context.Context, query string, args ...interface{}) ([]models.Vehicle, error) {
+	return r.collectVehicles(ctx, true, query, args...)
+}
 
-	rows, err := r.db.SQL.QueryContext(ctx, query, limit, offset)
+func (r *VehicleRepository) collectVehicles(ctx context.Context, ranked bool, query string, args ...interface{}) ([]models.Vehicle, error) {
+	rows, err := r.db.SQL.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
-	var vehicles []models.Vehicle
+	vehicles := []models.Vehicle{}
 	for rows.Next() {
 		var v models.Vehicle
-		var brand models.Brand
-		var vType models.VehicleType
-		var fuelType models.FuelType
-		var transmission models.Transmission
-
-		err := rows.Scan(
-			&v.ID, &v.BrandID, &v.Model, &v.Year, &v.TypeID,
-			&v.Price, &v.Currency, &v.FuelTypeID, &v.TransmissionID,
-			&v.Doors, &v.Seats, &v.EngineSize, &v.Horsepower, &v.Torque,
-			&v.FuelEconomy, &v.TankCapacity, &v.CargoSpace,
-			&v.ImageURL, &v.Description, &v.SafetyRating,
-			&v.CreatedAt, &v.UpdatedAt,
-			&brand.Name, &brand.Logo, &brand.Country,
-			&vType.Name, &fuelType.Name, &transmission.Name,
-		)
-		if err != nil {
-			return nil, 0, err
+		var relevance float64
+		if ranked {
+			v, err = scanVehicle(rows, &relevance)
+		} else {
+			v, err = scanVehicle(rows)
 		}
-
-		v.Brand = &brand
-		v.Type = &vType
-		v.FuelType = &fuelType
-		v.Transmission = &transmission
-
-		// Obtener características
-		features, err := r.getVehicleFeatures(ctx, v.ID)
-		if err == nil {
-			v.Features = features
+		if err != nil {
+			return nil, err
 		}
-
+		v.Relevance = relevance
 		vehicles = append(vehicles, v)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	return vehicles, total, nil
+	if err := r.loadFeatures(ctx, vehicles); err != nil {
+		return nil, err
+	}
+
+	return vehicles, nil
 }
 
 // GetVehicleByID obtiene un vehículo por su ID
 func (r *VehicleRepository) GetVehicleByID(ctx context.Context, id int) (*models.Vehicle, error) {
 	// Intentar obtener del caché
 	cacheKey := fmt.Sprintf("vehicle:%d", id)
-	if cached, err := r.db.GetCache(cacheKey); err == nil {
+	if cached, err := r.cache.Get(ctx, cacheKey); err == nil {
 		var vehicle models.Vehicle
 		if err := json.Unmarshal([]byte(cached), &vehicle); err == nil {
 			return &vehicle, nil
 		}
 	}
+	tags := []string{VehicleTag(id)}
+	gens := r.gens.snapshot(tags)
 
-	query := `
-		SELECT 
-			v.id, v.brand_id, v.model, v.year, v.type_id, 
-			v.price, v.currency, v.fuel_type_id, v.transmission_id,
-			v.doors, v.seats, v.engine_size, v.horsepower, v.torque,
-			v.fuel_economy, v.tank_capacity, v.cargo_space,
-			v.image_url, v.description, v.safety_rating,
-			v.created_at, v.updated_at,
-			b.id, b.name, b.logo, b.country,
-			vt.id, vt.name, 
-			ft.id, ft.name, 
-			t.id, t.name
-		FROM vehicles v
-		JOIN brands b ON v.brand_id = b.id
-		JOIN vehicle_types vt ON v.type_id = vt.id
-		JOIN fuel_types ft ON v.fuel_type_id = ft.id
-		JOIN transmissions t ON v.transmission_id = t.id
+	query := `SELECT ` + vehicleColumns + vehicleJoins + `
 		WHERE v.id = $1
 	`
 
-	var v models.Vehicle
-	var brand models.Brand
-	var vType models.VehicleType
-	var fuelType models.FuelType
-	var transmission models.Transmission
-
-	err := r.db.SQL.QueryRowContext(ctx, query, id).Scan(
-		&v.ID, &v.BrandID, &v.Model, &v.Year, &v.TypeID,
-		&v.Price, &v.Currency, &v.FuelTypeID, &v.TransmissionID,
-		&v.Doors, &v.Seats, &v.EngineSize, &v.Horsepower, &v.Torque,
-		&v.FuelEconomy, &v.TankCapacity, &v.CargoSpace,
-		&v.ImageURL, &v.Description, &v.SafetyRating,
-		&v.CreatedAt, &v.UpdatedAt,
-		&brand.ID, &brand.Name, &brand.Logo, &brand.Country,
-		&vType.ID, &vType.Name,
-		&fuelType.ID, &fuelType.Name,
-		&transmission.ID, &transmission.Name,
-	)
-
+	vehicles, err := r.queryVehicles(ctx, query, id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("vehículo no encontrado")
-		}
 		return nil, err
 	}
+	if len(vehicles) == 0 {
+		return nil, ErrVehicleNotFound
+	}
+	v := vehicles[0]
 
-	v.Brand = &brand
-	v.Type = &vType
-	v.FuelType = &fuelType
-	v.Transmission = &transmission
+	// Galería de imágenes (sólo en el detalle)
+	if v.Images, err = r.GetVehicleImages(ctx, v.ID, ""); err != nil {
+		return nil, err
+	}
 
-	// Obtener características
-	features, err := r.getVehicleFeatures(ctx, v.ID)
-	if err == nil {
-		v.Features = features
+	specs, err := r.GetVehicleSpecs(ctx, v.ID)
+	if err != nil {
+		return nil, err
 	}
+	v.Specs = models.GroupSpecsByCategory(specs)
 
-	// Guardar en caché
-	if data, err := json.Marshal(v); err == nil {
-		r.db.SetCache(cacheKey, string(data), 5*time.Minute)
+	// Guardar en caché etiquetado para que cualquier escritura del vehículo lo invalide. Las
+	// marcas sólo se crean, nunca se modifican, así que el detalle no depende de ellas.
+	if data, err := json.Marshal(v); err == nil && !r.gens.changed(tags, gens) {
+		r.cache.Set(ctx, cacheKey, string(data), 5*time.Minute, tags...)
 	}
 
 	return &v, nil
 }
 
-// SearchVehicles busca vehículos con filtros
-func (r *VehicleRepository) SearchVehicles(ctx context.Context, filter models.SearchFilter) ([]models.Vehicle, int, error) {
+// GetVehiclesByIDs obtiene varios vehículos (con características) respetando el orden
+// de los IDs solicitados
+func (r *VehicleRepository) GetVehiclesByIDs(ctx context.Context, ids []int) ([]models.Vehicle, error) {
+	query := `SELECT ` + vehicleColumns + vehicleJoins + `
+		WHERE v.id = ANY($1)
+		ORDER BY array_position($1, v.id)
+	`
+
+	return r.queryVehicles(ctx, query, pq.Array(ids))
+}
+
+// Dimensiones de facetas; al calcular los conteos de una dimensión se ignora su propio filtro
+// para que la selección múltiple siga mostrando las demás opciones
+const (
+	facetNone         = ""
+	facetBrand        = "brand"
+	facetType         = "type"
+	facetFuelType     = "fuel_type"
+	facetTransmission = "transmission"
+	facetPrice        = "price"
+	facetYear         = "year"
+	facetFeatures     = "features"
+)
+
+// buildSearchConditions construye las condiciones WHERE del filtro omitiendo la dimensión exclude
+func buildSearchConditions(filter models.SearchFilter, exclude string) ([]string, []interface{}) {
 	var conditions []string
 	var args []interface{}
 	argCounter := 1
 
 	// Construir condiciones WHERE dinámicamente
-	if len(filter.BrandID) > 0 {
+	if len(filter.BrandID) > 0 && exclude != facetBrand {
 		placeholders := make([]string, len(filter.BrandID))
 		for i, id := range filter.BrandID {
 			placeholders[i] = fmt.Sprintf("$%d", argCounter)
@@ -196,7 +225,7 @@ func (r *VehicleRepository) SearchVehicles(ctx context.Context, filter models.Se
 		conditions = append(conditions, fmt.Sprintf("v.brand_id IN (%s)", strings.Join(placeholders, ",")))
 	}
 
-	if len(filter.TypeID) > 0 {
+	if len(filter.TypeID) > 0 && exclude != facetType {
 		placeholders := make([]string, len(filter.TypeID))
 		for i, id := range filter.TypeID {
 			placeholders[i] = fmt.Sprintf("$%d", argCounter)
@@ -206,7 +235,7 @@ func (r *VehicleRepository) SearchVehicles(ctx context.Context, filter models.Se
 		conditions = append(conditions, fmt.Sprintf("v.type_id IN (%s)", strings.Join(placeholders, ",")))
 	}
 
-	if len(filter.FuelTypeID) > 0 {
+	if len(filter.FuelTypeID) > 0 && exclude != facetFuelType {
 		placeholders := make([]string, len(filter.FuelTypeID))
 		for i, id := range filter.FuelTypeID {
 			placeholders[i] = fmt.Sprintf("$%d", argCounter)
@@ -216,7 +245,7 @@ func (r *VehicleRepository) SearchVehicles(ctx context.Context, filter models.Se
 		conditions = append(conditions, fmt.Sprintf("v.fuel_type_id IN (%s)", strings.Join(placeholders, ",")))
 	}
 
-	if len(filter.TransmissionID) > 0 {
+	if len(filter.TransmissionID) > 0 && exclude != facetTransmission {
 		placeholders := make([]string, len(filter.TransmissionID))
 		for i, id := range filter.TransmissionID {
 			placeholders[i] = fmt.Sprintf("$%d", argCounter)
@@ -226,56 +255,144 @@ func (r *VehicleRepository) SearchVehicles(ctx context.Context, filter models.Se
 		conditions = append(conditions, fmt.Sprintf("v.transmission_id IN (%s)", strings.Join(placeholders, ",")))
 	}
 
-	if filter.PriceMin > 0 {
-		conditions = append(conditions, fmt.Sprintf("v.price >= $%d", argCounter))
-		args = append(args, filter.PriceMin)
-		argCounter++
+	// Rangos numéricos; cada dimensión de faceta (price, year) ignora su propio rango. Los
+	// valores NULL cuentan como 0, igual que en el orden y en el backend en memoria.
+	for _, r := range models.RangeFilters {
+		if r.Name == exclude {
+			continue
+		}
+		column := "v." + r.Name
+		if !notNullColumns[r.Name] {
+			column = "COALESCE(" + column + ", 0)"
+		}
+		lower, upper := r.Bounds(&filter)
+		if lower > 0 {
+			conditions = append(conditions, fmt.Sprintf("%s >= $%d", column, argCounter))
+			args = append(args, lower)
+			argCounter++
+		}
+		if upper > 0 {
+			conditions = append(conditions, fmt.Sprintf("%s <= $%d", column, argCounter))
+			args = append(args, upper)
+			argCounter++
+		}
 	}
 
-	if filter.PriceMax > 0 {
-		conditions = append(conditions, fmt.Sprintf("v.price <= $%d", argCounter))
-		args = append(args, filter.PriceMax)
+	// Búsqueda de texto sobre marca, modelo, tipo, características y descripción
+	if hasTextSearch(filter) {
+		condition, textArgs := textSearchCondition(filter, argCounter)
+		conditions = append(conditions, condition)
+		args = append(args, textArgs...)
+		argCounter += len(textArgs)
+	}
+
+	// Características en minúsculas: features_all cuenta las coincidencias distintas y
+	// features_any basta con una. El listado de características ignora features_any, igual
+	// que las facetas de selección múltiple.
+	featuresAll := models.NormalizeFeatures(filter.FeaturesAll)
+	featuresAny := models.NormalizeFeatures(filter.FeaturesAny)
+	if len(featuresAll) > 0 {
+		conditions = append(conditions, fmt.Sprintf(`(
+			SELECT COUNT(DISTINCT lower(f.feature)) FROM vehicle_features f
+			WHERE f.vehicle_id = v.id AND lower(f.feature) = ANY($%d)
+		) = $%d`, argCounter, argCounter+1))
+		args = append(args, pq.Array(featuresAll), len(featuresAll))
+		argCounter += 2
+	}
+
+	if len(featuresAny) > 0 && exclude != facetFeatures {
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM vehicle_features f
+			WHERE f.vehicle_id = v.id AND lower(f.feature) = ANY($%d)
+		)`, argCounter))
+		args = append(args, pq.Array(featuresAny))
 		argCounter++
 	}
 
-	if filter.YearMin > 0 {
-		conditions = append(conditions, fmt.Sprintf("v.year >= $%d", argCounter))
-		args = append(args, filter.YearMin)
-		argCounter++
+	// Especificaciones numéricas; los valores no numéricos nunca coinciden
+	for _, spec := range filter.Specs {
+		op, ok := specOperators[spec.Operator]
+		if !ok {
+			continue
+		}
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM vehicle_specs s
+			WHERE s.vehicle_id = v.id AND s.spec_name = $%d
+			AND CASE WHEN s.spec_value ~ '^\s*-?[0-9]+(\.[0-9]+)?\s*$' THEN trim(s.spec_value)::numeric END %s $%d
+		)`, argCounter, op, argCounter+1))
+		args = append(args, spec.Name, spec.Value)
+		argCounter += 2
 	}
 
-	if filter.YearMax > 0 {
-		conditions = append(conditions, fmt.Sprintf("v.year <= $%d", argCounter))
-		args = append(args, filter.YearMax)
-		argCounter++
+	return conditions, args
+}
+
+// notNullColumns son las columnas de rango que se comparan sin COALESCE para poder usar sus índices
+var notNullColumns = map[string]bool{
+	"price": true,
+	"year":  true,
+}
+
+// specOperators lista blanca de operadores SQL para los filtros de especificaciones
+var specOperators = map[string]string{
+	"=":  "=",
+	">":  ">",
+	">=": ">=",
+	"<":  "<",
+	"<=": "<=",
+}
+
+// SearchVehicles busca vehículos con filtros; los resultados se cachean por filtro canónico.
+// Con Cursor se pagina por keyset sobre las llaves de orden; sin él, por Page/Limit.
+func (r *VehicleRepository) SearchVehicles(ctx context.Context, filter models.SearchFilter) (*models.SearchPage, error) {
+	normalizePagination(&filter)
+
+	key := filterCacheKey("search:", filter)
+	page, err := cacheAside(ctx, r, key, searchCachePolicy, []string{TagSearch},
+		func(ctx context.Context) (models.SearchPage, error) {
+			return r.searchVehicles(ctx, filter)
+		})
+	if err != nil {
+		return nil, err
 	}
 
-	if filter.DoorsMin > 0 {
-		conditions = append(conditions, fmt.Sprintf("v.doors >= $%d", argCounter))
-		args = append(args, filter.DoorsMin)
-		argCounter++
+	return &page, nil
+}
+
+// normalizePagination aplica los valores por defecto de página y límite
+func normalizePagination(filter *models.SearchFilter) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = 20
 	}
+}
 
-	if filter.SeatsMin > 0 {
-		conditions = append(conditions, fmt.Sprintf("v.seats >= $%d", argCounter))
-		args = append(args, filter.SeatsMin)
-		argCounter++
+func (r *VehicleRepository) searchVehicles(ctx context.Context, filter models.SearchFilter) (models.SearchPage, error) {
+	conditions, args := buildSearchConditions(filter, facetNone)
+	keys, err := SearchSort(filter)
+	if err != nil {
+		return models.SearchPage{}, err
 	}
 
-	if filter.FuelEconomyMin > 0 {
-		conditions = append(conditions, fmt.Sprintf("v.fuel_economy >= $%d", argCounter))
-		args = append(args, filter.FuelEconomyMin)
-		argCounter++
+	// El total no depende del cursor, así que se cuenta antes de agregar la condición keyset
+	total, estimated, err := r.countVehicles(ctx, filter.Count, conditions, args)
+	if err != nil {
+		return models.SearchPage{}, err
 	}
 
-	// Búsqueda de texto
-	if filter.Query != "" {
-		conditions = append(conditions, fmt.Sprintf(
-			"to_tsvector('spanish', v.model || ' ' || COALESCE(v.description, '')) @@ plainto_tsquery('spanish', $%d)",
-			argCounter,
-		))
-		args = append(args, filter.Query)
-		argCounter++
+	offset := (filter.Page - 1) * filter.Limit
+	before := false
+	if filter.Cursor != "" {
+		values, isBefore, err := DecodeCursor(filter, keys)
+		if err != nil {
+			return models.SearchPage{}, err
+		}
+		condition, keysetArgs := keysetCondition(keys, values, isBefore, len(args)+1)
+		conditions = append(conditions, condition)
+		args = append(args, keysetArgs...)
+		offset, before = 0, isBefore
 	}
 
 	// Construir query WHERE
@@ -284,122 +401,155 @@ func (r *VehicleRepository) SearchVehicles(ctx context.Context, filter models.Se
 		whereClause = "WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	// Contar total con filtros
-	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM vehicles v %s`, whereClause)
-	var total int
-	err := r.db.SQL.QueryRowContext(ctx, countQuery, args...).Scan(&total)
+	// Se pide una fila extra para saber si existe una página siguiente
+	argCounter := len(args) + 1
+	query := fmt.Sprintf(`SELECT %s, %s %s
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, vehicleColumns, relevanceExpr(filter), vehicleJoins, whereClause, orderByClause(keys, before), argCounter, argCounter+1)
+
+	args = append(args, filter.Limit+1, offset)
+
+	vehicles, err := r.queryRankedVehicles(ctx, query, args...)
 	if err != nil {
-		return nil, 0, err
+		return models.SearchPage{}, err
 	}
 
-	// Ordenamiento
-	orderBy := "v.created_at DESC"
-	switch filter.SortBy {
-	case "price_asc":
-		orderBy = "v.price ASC"
-	case "price_desc":
-		orderBy = "v.price DESC"
-	case "year_desc":
-		orderBy = "v.year DESC"
-	case "fuel_economy_desc":
-		orderBy = "v.fuel_economy DESC"
+	page := NewSearchPage(filter, keys, vehicles, filter.Limit, before, filter.Cursor != "" || offset > 0)
+	page.Total = total
+	page.TotalEstimated = estimated
+	return page, nil
+}
+
+// countVehicles cuenta según el modo: exact con COUNT(*), estimate con el número de filas
+// que calcula el planificador (barato en catálogos grandes) y none sin consultar
+func (r *VehicleRepository) countVehicles(ctx context.Context, mode string, conditions []string, args []interface{}) (*int, bool, error) {
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
 	}
 
-	// Paginación
-	if filter.Page < 1 {
-		filter.Page = 1
+	switch mode {
+	case models.CountNone:
+		return nil, false, nil
+
+	case models.CountEstimate:
+		var plan []byte
+		query := fmt.Sprintf(`EXPLAIN (FORMAT JSON) SELECT 1 FROM vehicles v %s`, whereClause)
+		if err := r.db.SQL.QueryRowContext(ctx, query, args...).Scan(&plan); err != nil {
+			return nil, false, err
+		}
+
+		var explain []struct {
+			Plan struct {
+				Rows float64 `json:"Plan Rows"`
+			} `json:"Plan"`
+		}
+		if err := json.Unmarshal(plan, &explain); err != nil || len(explain) == 0 {
+			return nil, false, fmt.Errorf("error al interpretar el plan de conteo: %v", err)
+		}
+		total := int(explain[0].Plan.Rows)
+		return &total, true, nil
 	}
-	if filter.Limit < 1 || filter.Limit > 100 {
-		filter.Limit = 20
+
+	var total int
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM vehicles v %s`, whereClause)
+	if err := r.db.SQL.QueryRowContext(ctx, query, args...).Scan(&total); err != nil {
+		return nil, false, err
 	}
-	offset := (filter.Page - 1) * filter.Limit
+	return &total, false, nil
+}
 
-	// Query principal
-	query := fmt.Sprintf(`
-		SELECT 
-			v.id, v.brand_id, v.model, v.year, v.type_id, 
-			v.price, v.currency, v.fuel_type_id, v.transmission_id,
-			v.doors, v.seats, v.engine_size, v.horsepower, v.torque,
-			v.fuel_economy, v.tank_capacity, v.cargo_space,
-			v.image_url, v.description, v.safety_rating,
-			v.created_at, v.updated_at,
-			b.name, b.logo, b.country,
-			vt.name, ft.name, t.name
-		FROM vehicles v
-		JOIN brands b ON v.brand_id = b.id
-		JOIN vehicle_types vt ON v.type_id = vt.id
-		JOIN fuel_types ft ON v.fuel_type_id = ft.id
-		JOIN transmissions t ON v.transmission_id = t.id
+// ExportVehicles recorre todos los vehículos que cumplen el filtro, sin paginación ni caché,
+// y llama a fn por cada uno en el orden pedido. Las filas se leen del cursor conforme se
+// escriben, de modo que la memoria no crece con el tamaño del catálogo.
+func (r *VehicleRepository) ExportVehicles(ctx context.Context, filter models.SearchFilter, fn func(models.Vehicle) error) error {
+	conditions, args := buildSearchConditions(filter, facetNone)
+	keys, err := SearchSort(filter)
+	if err != nil {
+		return err
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`SELECT %s,
+			COALESCE((SELECT array_agg(f.feature ORDER BY f.feature) FROM vehicle_features f WHERE f.vehicle_id = v.id), '{}')
+		%s
 		%s
 		ORDER BY %s
-		LIMIT $%d OFFSET $%d
-	`, whereClause, orderBy, argCounter, argCounter+1)
-
-	args = append(args, filter.Limit, offset)
+	`, vehicleColumns, vehicleJoins, whereClause, orderByClause(keys, false))
 
 	rows, err := r.db.SQL.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, 0, err
+		return err
 	}
 	defer rows.Close()
 
-	var vehicles []models.Vehicle
 	for rows.Next() {
-		var v models.Vehicle
-		var brand models.Brand
-		var vType models.VehicleType
-		var fuelType models.FuelType
-		var transmission models.Transmission
-
-		err := rows.Scan(
-			&v.ID, &v.BrandID, &v.Model, &v.Year, &v.TypeID,
-			&v.Price, &v.Currency, &v.FuelTypeID, &v.TransmissionID,
-			&v.Doors, &v.Seats, &v.EngineSize, &v.Horsepower, &v.Torque,
-			&v.FuelEconomy, &v.TankCapacity, &v.CargoSpace,
-			&v.ImageURL, &v.Description, &v.SafetyRating,
-			&v.CreatedAt, &v.UpdatedAt,
-			&brand.Name, &brand.Logo, &brand.Country,
-			&vType.Name, &fuelType.Name, &transmission.Name,
-		)
+		var features pq.StringArray
+		v, err := scanVehicle(rows, &features)
 		if err != nil {
-			return nil, 0, err
+			return err
+		}
+		v.Features = features
+		if err := fn(v); err != nil {
+			return err
 		}
+	}
 
-		v.Brand = &brand
-		v.Type = &vType
-		v.FuelType = &fuelType
-		v.Transmission = &transmission
+	return rows.Err()
+}
 
-		vehicles = append(vehicles, v)
+// loadFeatures carga las características de todos los vehículos con una única consulta
+func (r *VehicleRepository) loadFeatures(ctx context.Context, vehicles []models.Vehicle) error {
+	if len(vehicles) == 0 {
+		return nil
 	}
 
-	return vehicles, total, nil
-}
+	ids := make([]int, len(vehicles))
+	index := make(map[int][]int, len(vehicles))
+	for i, v := range vehicles {
+		ids[i] = v.ID
+		index[v.ID] = append(index[v.ID], i)
+	}
 
-// Métodos auxiliares
-func (r *VehicleRepository) getVehicleFeatures(ctx context.Context, vehicleID int) ([]string, error) {
-	query := `SELECT feature FROM vehicle_features WHERE vehicle_id = $1`
-	rows, err := r.db.SQL.QueryContext(ctx, query, vehicleID)
+	query := `
+		SELECT vehicle_id, feature
+		FROM vehicle_features
+		WHERE vehicle_id = ANY($1)
+		ORDER BY vehicle_id, feature
+	`
+	rows, err := r.db.SQL.QueryContext(ctx, query, pq.Array(ids))
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	var features []string
 	for rows.Next() {
+		var vehicleID int
 		var feature string
-		if err := rows.Scan(&feature); err != nil {
-			return nil, err
+		if err := rows.Scan(&vehicleID, &feature); err != nil {
+			return err
+		}
+		for _, i := range index[vehicleID] {
+			vehicles[i].Features = append(vehicles[i].Features, feature)
 		}
-		features = append(features, feature)
 	}
 
-	return features, nil
+	return rows.Err()
 }
 
 // GetBrands obtiene todas las marcas
 func (r *VehicleRepository) GetBrands(ctx context.Context) ([]models.Brand, error) {
-	query := `SELECT id, name, logo, country FROM brands ORDER BY name`
+	return cacheAside(ctx, r, "catalog:brands", catalogCachePolicy, []string{TagCatalog}, r.loadBrands)
+}
+
+func (r *VehicleRepository) loadBrands(ctx context.Context) ([]models.Brand, error) {
+	query := `SELECT id, name, COALESCE(logo, ''), COALESCE(country, '') FROM brands ORDER BY name`
 	rows, err := r.db.SQL.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
@@ -421,6 +571,10 @@ func (r *VehicleRepository) GetBrands(ctx context.Context) ([]models.Brand, erro
 
 // GetVehicleTypes obtiene todos los tipos de vehículo
 func (r *VehicleRepository) GetVehicleTypes(ctx context.Context) ([]models.VehicleType, error) {
+	return cacheAside(ctx, r, "catalog:vehicle_types", catalogCachePolicy, []string{TagCatalog}, r.loadVehicleTypes)
+}
+
+func (r *VehicleRepository) loadVehicleTypes(ctx context.Context) ([]models.VehicleType, error) {
 	query := `SELECT id, name FROM vehicle_types ORDER BY name`
 	rows, err := r.db.SQL.QueryContext(ctx, query)
 	if err != nil {
@@ -443,6 +597,10 @@ func (r *VehicleRepository) GetVehicleTypes(ctx context.Context) ([]models.Vehic
 
 // GetFuelTypes obtiene todos los tipos de combustible
 func (r *VehicleRepository) GetFuelTypes(ctx context.Context) ([]models.FuelType, error) {
+	return cacheAside(ctx, r, "catalog:fuel_types", catalogCachePolicy, []string{TagCatalog}, r.loadFuelTypes)
+}
+
+func (r *VehicleRepository) loadFuelTypes(ctx context.Context) ([]models.FuelType, error) {
 	query := `SELECT id, name FROM fuel_types ORDER BY name`
 	rows, err := r.db.SQL.QueryContext(ctx, query)
 	if err != nil {
@@ -465,6 +623,10 @@ func (r *VehicleRepository) GetFuelTypes(ctx context.Context) ([]models.FuelType
 
 // GetTransmissions obtiene todos los tipos de transmisión
 func (r *VehicleRepository) GetTransmissions(ctx context.Context) ([]models.Transmission, error) {
+	return cacheAside(ctx, r, "catalog:transmissions", catalogCachePolicy, []string{TagCatalog}, r.loadTransmissions)
+}
+
+func (r *VehicleRepository) loadTransmissions(ctx context.Context) ([]models.Transmission, error) {
 	query := `SELECT id, name FROM transmissions ORDER BY name`
 	rows, err := r.db.SQL.QueryContext(ctx, query)
 	if err != nil {
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/scoring"
+)
+
+// scoringCheckInterval es cada cuánto se compara la versión del catálogo con la del snapshot
+const scoringCheckInterval = 5 * time.Second
+
+// scoringTags son las etiquetas cuyas invalidaciones descartan el snapshot de puntaje
+var scoringTags = []string{TagSearch, TagCatalog}
+
+// scoringSnapshot es el catálogo de puntaje construido para una versión del catálogo
+type scoringSnapshot struct {
+	version   string
+	catalog   *scoring.Catalog
+	checkedAt time.Time
+}
+
+// GetScoringCatalog devuelve todos los vehículos con los rangos de sus atributos, para
+// puntuar similares y recomendaciones sin recorrer la tabla en cada petición. El catálogo se
+// guarda en memoria del proceso y se comparte entre peticiones, que no deben modificarlo.
+// Cada scoringCheckInterval se compara CatalogVersion en segundo plano y, si cambió, se
+// reconstruye; las escrituras de este proceso lo descartan de inmediato (invalidateTags).
+func (r *VehicleRepository) GetScoringCatalog(ctx context.Context) (*scoring.Catalog, error) {
+	snap := r.scoring.Load()
+	if snap == nil {
+		return r.refreshScoringCatalog(ctx)
+	}
+	if time.Since(snap.checkedAt) >= scoringCheckInterval {
+		r.refreshScoringInBackground()
+	}
+	return snap.catalog, nil
+}
+
+// refreshScoringCatalog reconstruye el snapshot si la versión del catálogo cambió. Las
+// revisiones concurrentes se agrupan en una sola.
+func (r *VehicleRepository) refreshScoringCatalog(ctx context.Context) (*scoring.Catalog, error) {
+	result, err, _ := r.group.Do("scoring:catalog", func() (interface{}, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+
+		gens := r.gens.snapshot(scoringTags)
+		version, err := r.CatalogVersion(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		current := r.scoring.Load()
+		if current != nil && current.version == version {
+			r.scoring.CompareAndSwap(current, &scoringSnapshot{version: version, catalog: current.catalog, checkedAt: time.Now()})
+			return current.catalog, nil
+		}
+
+		var vehicles []models.Vehicle
+		err = r.ExportVehicles(loadCtx, models.SearchFilter{}, func(v models.Vehicle) error {
+			vehicles = append(vehicles, v)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		catalog := scoring.NewCatalog(vehicles)
+		// Una escritura de este proceso durante la carga pudo dejarla obsoleta
+		if !r.gens.changed(scoringTags, gens) {
+			r.scoring.Store(&scoringSnapshot{version: version, catalog: catalog, checkedAt: time.Now()})
+		}
+		return catalog, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*scoring.Catalog), nil
+}
+
+// refreshScoringInBackground lanza una sola revisión de versión a la vez
+func (r *VehicleRepository) refreshScoringInBackground() {
+	if !r.scoringRefreshing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer r.scoringRefreshing.Store(false)
+		if _, err := r.refreshScoringCatalog(context.Background()); err != nil {
+			log.Printf("Error al reconstruir el catálogo de puntaje: %v", err)
+		}
+	}()
+}
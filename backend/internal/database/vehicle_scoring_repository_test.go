@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vehiculos/backend/internal/cache"
+	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/scoring"
+)
+
+func TestScoringCatalogSnapshot(t *testing.T) {
+	ctx := context.Background()
+	r := &VehicleRepository{cache: cache.NewMemory(10)}
+	catalog := scoring.NewCatalog([]models.Vehicle{{ID: 1, Price: 300000}})
+	r.scoring.Store(&scoringSnapshot{version: "1:1", catalog: catalog, checkedAt: time.Now()})
+
+	// Un snapshot reciente se sirve sin consultar la base de datos
+	got, err := r.GetScoringCatalog(ctx)
+	if err != nil || got != catalog {
+		t.Fatalf("GetScoringCatalog = %p, %v; want el snapshot", got, err)
+	}
+
+	if err := r.invalidateTags(ctx, VehicleTag(1)); err != nil {
+		t.Fatal(err)
+	}
+	if r.scoring.Load() == nil {
+		t.Error("invalidar sólo el detalle no debe descartar el snapshot")
+	}
+
+	if err := r.invalidateTags(ctx, VehicleTag(1), TagSearch); err != nil {
+		t.Fatal(err)
+	}
+	if r.scoring.Load() != nil {
+		t.Error("una escritura de vehículos debe descartar el snapshot")
+	}
+}
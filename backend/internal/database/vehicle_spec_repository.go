@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// ErrVehicleSpecNotFound indica que la especificación no existe para el vehículo
+var ErrVehicleSpecNotFound = errors.New("especificación no encontrada")
+
+// GetVehicleSpecs obtiene las especificaciones técnicas del vehículo
+func (r *VehicleRepository) GetVehicleSpecs(ctx context.Context, vehicleID int) ([]models.VehicleSpec, error) {
+	query := `
+		SELECT id, vehicle_id, spec_name, spec_value, COALESCE(spec_unit, ''), COALESCE(spec_category, '')
+		FROM vehicle_specs
+		WHERE vehicle_id = $1
+		ORDER BY spec_category, spec_name
+	`
+	rows, err := r.db.SQL.QueryContext(ctx, query, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	specs := []models.VehicleSpec{}
+	for rows.Next() {
+		var s models.VehicleSpec
+		if err := rows.Scan(&s.ID, &s.VehicleID, &s.Name, &s.Value, &s.Unit, &s.Category); err != nil {
+			return nil, err
+		}
+		specs = append(specs, s)
+	}
+
+	return specs, rows.Err()
+}
+
+// UpsertVehicleSpecs crea o actualiza (por spec_name) las especificaciones en una transacción
+func (r *VehicleRepository) UpsertVehicleSpecs(ctx context.Context, vehicleID int, specs []models.VehicleSpec) error {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO vehicle_specs (vehicle_id, spec_name, spec_value, spec_unit, spec_category)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''))
+		ON CONFLICT (vehicle_id, spec_name) DO UPDATE SET
+			spec_value = EXCLUDED.spec_value,
+			spec_unit = EXCLUDED.spec_unit,
+			spec_category = EXCLUDED.spec_category
+	`
+	for _, s := range specs {
+		if _, err := tx.ExecContext(ctx, query, vehicleID, s.Name, s.Value, s.Unit, s.Category); err != nil {
+			if isForeignKeyViolation(err) {
+				return ErrVehicleNotFound
+			}
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	r.invalidateVehicle(ctx, vehicleID)
+	return nil
+}
+
+// DeleteVehicleSpec elimina una especificación del vehículo
+func (r *VehicleRepository) DeleteVehicleSpec(ctx context.Context, vehicleID int, name string) error {
+	result, err := r.db.SQL.ExecContext(ctx,
+		`DELETE FROM vehicle_specs WHERE vehicle_id = $1 AND spec_name = $2`, vehicleID, name)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrVehicleSpecNotFound
+	}
+
+	r.invalidateVehicle(ctx, vehicleID)
+	return nil
+}
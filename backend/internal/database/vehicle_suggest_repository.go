@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// ListSuggestions lista las marcas con vehículos, cada modelo por marca con su rango de años
+// y las características, con el número de vehículos de cada una. Las variantes de mayúsculas
+// de un modelo o característica se agrupan y se muestra la primera alfabéticamente.
+func (r *VehicleRepository) ListSuggestions(ctx context.Context) ([]models.Suggestion, error) {
+	query := `
+		SELECT 'brand', b.name, b.id, b.name, '', '', 0, 0, COUNT(*)
+		FROM vehicles v
+		JOIN brands b ON b.id = v.brand_id
+		GROUP BY b.id, b.name
+
+		UNION ALL
+
+		SELECT 'model', b.name || ' ' || MIN(v.model), b.id, b.name, MIN(v.model), '',
+			MIN(v.year), MAX(v.year), COUNT(*)
+		FROM vehicles v
+		JOIN brands b ON b.id = v.brand_id
+		GROUP BY b.id, b.name, lower(v.model)
+
+		UNION ALL
+
+		SELECT 'feature', MIN(f.feature), 0, '', '', lower(f.feature), 0, 0, COUNT(DISTINCT f.vehicle_id)
+		FROM vehicle_features f
+		GROUP BY lower(f.feature)
+	`
+
+	rows, err := r.db.SQL.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := []models.Suggestion{}
+	for rows.Next() {
+		var s models.Suggestion
+		if err := rows.Scan(&s.Type, &s.Text, &s.BrandID, &s.Brand, &s.Model, &s.Feature, &s.YearMin, &s.YearMax, &s.Count); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, s)
+	}
+
+	return suggestions, rows.Err()
+}
+
+// CatalogVersion devuelve el contador de catalog_version, que los triggers de la migración
+// 005 incrementan en cada cambio de vehículos, características y catálogos relacionados
+func (r *VehicleRepository) CatalogVersion(ctx context.Context) (string, error) {
+	var version int64
+	err := r.db.SQL.QueryRowContext(ctx, `SELECT version FROM catalog_version`).Scan(&version)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(version, 10), nil
+}
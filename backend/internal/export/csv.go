@@ -0,0 +1,53 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/vehiculos/backend/internal/csvcell"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+type csvWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer) (Writer, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: cw, record: make([]string, len(columns))}, nil
+}
+
+func (c *csvWriter) Write(v models.Vehicle) error {
+	for i, value := range NewRow(v).values() {
+		c.record[i] = formatCell(value)
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// formatCell convierte una celda a texto sin notación exponencial; nil es una celda vacía.
+// El texto se neutraliza para que una hoja de cálculo no lo evalúe como fórmula; XLSX no lo
+// necesita porque sus celdas de texto son inlineStr y nunca se evalúan.
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return csvcell.Escape(v)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,129 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"reflect"
+	"testing"
+
+	"github.com/vehiculos/backend/internal/database/memory"
+	"github.com/vehiculos/backend/internal/importer"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+func TestCSVNeutralizesFormulas(t *testing.T) {
+	v := models.Vehicle{
+		ID:          7,
+		Brand:       &models.Brand{Name: "@Marca"},
+		Model:       "+Modelo",
+		Year:        2024,
+		Type:        &models.VehicleType{Name: "SUV"},
+		Price:       -1,
+		Features:    []string{"-Faros LED", "Cámara"},
+		Description: `=HYPERLINK("http://example.com","clic")`,
+	}
+
+	var buf bytes.Buffer
+	w, err := newCSVWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(v); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(bytes.NewReader(buf.Bytes())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cells := map[string]string{}
+	for i, name := range rows[0] {
+		cells[name] = rows[1][i]
+	}
+	want := map[string]string{
+		"brand":       "'@Marca",
+		"model":       "'+Modelo",
+		"type":        "SUV",
+		"price":       "-1",
+		"features":    "'-Faros LED|Cámara",
+		"description": `'=HYPERLINK("http://example.com","clic")`,
+	}
+	for name, value := range want {
+		if cells[name] != value {
+			t.Errorf("%s = %q, want %q", name, cells[name], value)
+		}
+	}
+
+	// El CSV exportado se reimporta con el texto original
+	records, rowErrors, err := importer.ParseCSV(bytes.NewReader(buf.Bytes()))
+	if err != nil || len(rowErrors) != 0 || len(records) != 1 {
+		t.Fatalf("ParseCSV: %v, %+v, %d registros", err, rowErrors, len(records))
+	}
+	rec := records[0]
+	if rec.Brand != "@Marca" || rec.Model != "+Modelo" || rec.Description != v.Description {
+		t.Errorf("registro reimportado = %+v", rec)
+	}
+	if !reflect.DeepEqual(rec.Features, v.Features) {
+		t.Errorf("features = %q, want %q", rec.Features, v.Features)
+	}
+}
+
+// TestCSVRoundTrip exporta un vehículo sin datos opcionales y lo reimporta en modo dry_run
+// sobre el mismo catálogo: debe reconocerse como actualización sin errores de validación
+func TestCSVRoundTrip(t *testing.T) {
+	store, err := memory.NewStore(memory.Seed{
+		Brands:        []models.Brand{{ID: 1, Name: "Nissan"}},
+		VehicleTypes:  []models.VehicleType{{ID: 1, Name: "Sedan"}},
+		FuelTypes:     []models.FuelType{{ID: 1, Name: "Gasolina"}},
+		Transmissions: []models.Transmission{{ID: 1, Name: "CVT"}},
+		Vehicles: []memory.SeedVehicle{{ID: 1, VehicleInput: models.VehicleInput{
+			BrandID: 1, Model: "Versa", Year: 2020, TypeID: 1, Price: 250000,
+			FuelTypeID: 1, TransmissionID: 1, Features: []string{"Bluetooth"},
+		}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w, err := newCSVWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.ExportVehicles(context.Background(), models.SearchFilter{}, w.Write)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(bytes.NewReader(buf.Bytes())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range rows[0] {
+		switch name {
+		case "doors", "seats", "horsepower", "fuel_economy", "safety_rating":
+			if rows[1][i] != "" {
+				t.Errorf("%s sin dato = %q, want celda vacía", name, rows[1][i])
+			}
+		}
+	}
+
+	records, rowErrors, err := importer.ParseCSV(bytes.NewReader(buf.Bytes()))
+	if err != nil || len(rowErrors) != 0 {
+		t.Fatalf("ParseCSV: %v, %+v", err, rowErrors)
+	}
+	result, err := importer.New(store, store).Run(context.Background(), records, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Failed != 0 || result.Updated != 1 {
+		t.Errorf("reimportación = %+v, want una actualización sin errores", result)
+	}
+}
@@ -0,0 +1,176 @@
+// Package export escribe listados de vehículos en CSV, JSON Lines o XLSX fila por fila,
+// para poder transmitir catálogos completos sin cargarlos en memoria.
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/vehiculos/backend/internal/csvcell"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// Writer escribe vehículos en un formato; Close completa el archivo y debe llamarse siempre
+type Writer interface {
+	Write(v models.Vehicle) error
+	Close() error
+}
+
+// Format describe un formato de exportación
+type Format struct {
+	Name        string
+	ContentType string
+	Extension   string
+	newWriter   func(w io.Writer) (Writer, error)
+}
+
+// NewWriter crea el escritor del formato sobre w
+func (f Format) NewWriter(w io.Writer) (Writer, error) {
+	return f.newWriter(w)
+}
+
+var formats = map[string]Format{
+	"csv": {
+		Name:        "csv",
+		ContentType: "text/csv; charset=utf-8",
+		Extension:   "csv",
+		newWriter:   newCSVWriter,
+	},
+	"jsonl": {
+		Name:        "jsonl",
+		ContentType: "application/x-ndjson",
+		Extension:   "jsonl",
+		newWriter:   newJSONLWriter,
+	},
+	"xlsx": {
+		Name:        "xlsx",
+		ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		Extension:   "xlsx",
+		newWriter:   newXLSXWriter,
+	},
+}
+
+// Lookup devuelve el formato por nombre (csv, jsonl, xlsx)
+func Lookup(name string) (Format, bool) {
+	f, ok := formats[name]
+	return f, ok
+}
+
+// Row es un vehículo aplanado con los catálogos por nombre. Las columnas coinciden con las
+// del importador, de modo que un CSV exportado se puede volver a importar.
+type Row struct {
+	ID           int      `json:"id"`
+	Brand        string   `json:"brand"`
+	Model        string   `json:"model"`
+	Year         int      `json:"year"`
+	Type         string   `json:"type"`
+	Price        float64  `json:"price"`
+	Currency     string   `json:"currency"`
+	FuelType     string   `json:"fuel_type"`
+	Transmission string   `json:"transmission"`
+	Doors        int      `json:"doors"`
+	Seats        int      `json:"seats"`
+	EngineSize   float64  `json:"engine_size"`
+	Horsepower   int      `json:"horsepower"`
+	Torque       int      `json:"torque"`
+	FuelEconomy  float64  `json:"fuel_economy"`
+	TankCapacity float64  `json:"tank_capacity"`
+	CargoSpace   float64  `json:"cargo_space"`
+	SafetyRating float64  `json:"safety_rating"`
+	Features     []string `json:"features"`
+	ImageURL     string   `json:"image_url"`
+	Description  string   `json:"description"`
+}
+
+// columns son los encabezados de CSV y XLSX, en el orden de Row.values
+var columns = []string{
+	"id", "brand", "model", "year", "type", "price", "currency", "fuel_type", "transmission",
+	"doors", "seats", "engine_size", "horsepower", "torque", "fuel_economy", "tank_capacity",
+	"cargo_space", "safety_rating", "features", "image_url", "description",
+}
+
+// NewRow aplana el vehículo
+func NewRow(v models.Vehicle) Row {
+	row := Row{
+		ID:           v.ID,
+		Model:        v.Model,
+		Year:         v.Year,
+		Price:        v.Price,
+		Currency:     v.Currency,
+		Doors:        v.Doors,
+		Seats:        v.Seats,
+		EngineSize:   v.EngineSize,
+		Horsepower:   v.Horsepower,
+		Torque:       v.Torque,
+		FuelEconomy:  v.FuelEconomy,
+		TankCapacity: v.TankCapacity,
+		CargoSpace:   v.CargoSpace,
+		SafetyRating: v.SafetyRating,
+		Features:     v.Features,
+		ImageURL:     v.ImageURL,
+		Description:  v.Description,
+	}
+	if row.Features == nil {
+		row.Features = []string{}
+	}
+	if v.Brand != nil {
+		row.Brand = v.Brand.Name
+	}
+	if v.Type != nil {
+		row.Type = v.Type.Name
+	}
+	if v.FuelType != nil {
+		row.FuelType = v.FuelType.Name
+	}
+	if v.Transmission != nil {
+		row.Transmission = v.Transmission.Name
+	}
+	return row
+}
+
+// values devuelve las celdas en el orden de columns; los números conservan su tipo para XLSX.
+// Las columnas numéricas opcionales sin dato (0) quedan vacías (nil), que el importador lee
+// como "sin dato" en lugar de como un valor que la validación rechazaría.
+func (r Row) values() []interface{} {
+	return []interface{}{
+		r.ID, r.Brand, r.Model, r.Year, r.Type, r.Price, r.Currency, r.FuelType, r.Transmission,
+		optionalInt(r.Doors), optionalInt(r.Seats), optionalFloat(r.EngineSize),
+		optionalInt(r.Horsepower), optionalInt(r.Torque), optionalFloat(r.FuelEconomy),
+		optionalFloat(r.TankCapacity), optionalFloat(r.CargoSpace), optionalFloat(r.SafetyRating),
+		strings.Join(r.Features, csvcell.FeatureSeparator), r.ImageURL, r.Description,
+	}
+}
+
+func optionalInt(n int) interface{} {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
+
+func optionalFloat(f float64) interface{} {
+	if f == 0 {
+		return nil
+	}
+	return f
+}
+
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) (Writer, error) {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &jsonlWriter{enc: enc}, nil
+}
+
+// Write escribe un objeto JSON por línea (Encode ya agrega el salto de línea)
+func (j *jsonlWriter) Write(v models.Vehicle) error {
+	return j.enc.Encode(NewRow(v))
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vehiculos/backend/internal/importer"
+)
+
+// maxImportSize limita el tamaño del archivo de importación (10 MB)
+const maxImportSize = 10 << 20
+
+type AdminImportHandler struct {
+	importer *importer.Importer
+}
+
+func NewAdminImportHandler(imp *importer.Importer) *AdminImportHandler {
+	return &AdminImportHandler{importer: imp}
+}
+
+// ImportVehicles importa vehículos desde un CSV o JSON. Acepta el archivo como multipart
+// (campo "file") o como cuerpo de la petición; ?dry_run=true sólo valida.
+func (h *AdminImportHandler) ImportVehicles(c *gin.Context) {
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+	format := c.Query("format")
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	var body io.Reader = c.Request.Body
+	var maxBytesErr *http.MaxBytesError
+	if file, header, err := c.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		body = file
+		if format == "" {
+			format = importer.FormatFromFilename(header.Filename)
+		}
+	} else if errors.As(err, &maxBytesErr) {
+		// Un multipart que excede el límite falla al parsear el formulario, antes de leer el archivo
+		respondImportTooLarge(c)
+		return
+	} else if format == "" {
+		format = formatFromContentType(c.GetHeader("Content-Type"))
+	}
+
+	if format == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Formato no reconocido, use format=csv o format=json",
+		})
+		return
+	}
+
+	result, err := h.importer.Import(c.Request.Context(), format, body, dryRun)
+	if err != nil {
+		switch {
+		case errors.As(err, &maxBytesErr):
+			respondImportTooLarge(c)
+		case errors.Is(err, importer.ErrInvalidFile):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Archivo de importación inválido",
+				"details": err.Error(),
+			})
+		default:
+			log.Printf("Error al importar vehículos: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error al importar vehículos",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func respondImportTooLarge(c *gin.Context) {
+	c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+		"error": "El archivo excede el tamaño máximo de 10 MB",
+	})
+}
+
+func formatFromContentType(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return importer.FormatCSV
+	case "application/json":
+		return importer.FormatJSON
+	}
+	return ""
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vehiculos/backend/internal/importer"
+)
+
+func TestImportVehiclesTooLarge(t *testing.T) {
+	store := newTestStore(t)
+	h := NewAdminImportHandler(importer.New(store, store))
+	router := gin.New()
+	router.POST("/api/admin/vehicles/import", h.ImportVehicles)
+
+	huge := strings.Repeat("x", maxImportSize+1)
+
+	var multipartBody bytes.Buffer
+	form := multipart.NewWriter(&multipartBody)
+	part, err := form.CreateFormFile("file", "vehiculos.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte(huge))
+	form.Close()
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+	}{
+		{"multipart", form.FormDataContentType(), multipartBody.Bytes()},
+		{"cuerpo directo", "text/csv", []byte(huge)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/vehicles/import?dry_run=true", bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusRequestEntityTooLarge {
+				t.Errorf("código = %d, want 413: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
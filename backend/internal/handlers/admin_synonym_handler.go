@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vehiculos/backend/internal/synonyms"
+)
+
+type AdminSynonymHandler struct {
+	synonyms *synonyms.Rewriter
+}
+
+func NewAdminSynonymHandler(rewriter *synonyms.Rewriter) *AdminSynonymHandler {
+	return &AdminSynonymHandler{synonyms: rewriter}
+}
+
+// ReloadSynonyms vuelve a leer el diccionario de sinónimos. Si el archivo es inválido se
+// responde 422 y las búsquedas siguen usando el diccionario anterior.
+func (h *AdminSynonymHandler) ReloadSynonyms(c *gin.Context) {
+	rules, err := h.synonyms.Reload(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "No se pudo recargar el diccionario de sinónimos",
+			"cause": err.Error(),
+			"rules": h.synonyms.Rules(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"rules": rules,
+	})
+}
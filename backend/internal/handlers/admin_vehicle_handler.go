@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vehiculos/backend/internal/database"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+type AdminVehicleHandler struct {
+	repo database.VehicleStore
+}
+
+func NewAdminVehicleHandler(repo database.VehicleStore) *AdminVehicleHandler {
+	return &AdminVehicleHandler{repo: repo}
+}
+
+// CreateVehicle da de alta un vehículo con sus características
+func (h *AdminVehicleHandler) CreateVehicle(c *gin.Context) {
+	var input models.VehicleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Datos de vehículo inválidos",
+		})
+		return
+	}
+
+	vehicle, err := h.repo.CreateVehicle(c.Request.Context(), input)
+	if err != nil {
+		respondWriteError(c, err, "Error al crear vehículo")
+		return
+	}
+
+	c.JSON(http.StatusCreated, vehicle)
+}
+
+// UpdateVehicle reemplaza por completo un vehículo
+func (h *AdminVehicleHandler) UpdateVehicle(c *gin.Context) {
+	id, ok := vehicleIDParam(c)
+	if !ok {
+		return
+	}
+
+	var input models.VehicleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Datos de vehículo inválidos",
+		})
+		return
+	}
+
+	vehicle, err := h.repo.UpdateVehicle(c.Request.Context(), id, input)
+	if err != nil {
+		respondWriteError(c, err, "Error al actualizar vehículo")
+		return
+	}
+
+	c.JSON(http.StatusOK, vehicle)
+}
+
+// PatchVehicle actualiza sólo los campos enviados
+func (h *AdminVehicleHandler) PatchVehicle(c *gin.Context) {
+	id, ok := vehicleIDParam(c)
+	if !ok {
+		return
+	}
+
+	var patch models.VehiclePatch
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Datos de vehículo inválidos",
+		})
+		return
+	}
+
+	vehicle, err := h.repo.PatchVehicle(c.Request.Context(), id, patch)
+	if err != nil {
+		respondWriteError(c, err, "Error al actualizar vehículo")
+		return
+	}
+
+	c.JSON(http.StatusOK, vehicle)
+}
+
+// DeleteVehicle elimina un vehículo
+func (h *AdminVehicleHandler) DeleteVehicle(c *gin.Context) {
+	id, ok := vehicleIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteVehicle(c.Request.Context(), id); err != nil {
+		respondWriteError(c, err, "Error al eliminar vehículo")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddVehicleImage agrega una imagen a la galería del vehículo
+func (h *AdminVehicleHandler) AddVehicleImage(c *gin.Context) {
+	id, ok := vehicleIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		ImageURL     string `json:"image_url" binding:"required"`
+		ImageType    string `json:"image_type"`
+		DisplayOrder *int   `json:"display_order"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Se requiere image_url",
+		})
+		return
+	}
+	if req.ImageType == "" {
+		req.ImageType = models.ImageTypeExterior
+	}
+	if !models.ValidImageType(req.ImageType) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Tipo de imagen inválido",
+		})
+		return
+	}
+	if req.DisplayOrder != nil && *req.DisplayOrder < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "display_order no puede ser negativo",
+		})
+		return
+	}
+
+	img := models.VehicleImage{
+		VehicleID: id,
+		ImageURL:  req.ImageURL,
+		ImageType: req.ImageType,
+	}
+	if err := h.repo.AddVehicleImage(c.Request.Context(), &img, req.DisplayOrder); err != nil {
+		respondWriteError(c, err, "Error al agregar imagen")
+		return
+	}
+
+	c.JSON(http.StatusCreated, img)
+}
+
+// ReorderVehicleImages cambia el orden de la galería según la lista de IDs recibida
+func (h *AdminVehicleHandler) ReorderVehicleImages(c *gin.Context) {
+	id, ok := vehicleIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		ImageIDs []int `json:"image_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Se requiere image_ids",
+		})
+		return
+	}
+
+	err := h.repo.ReorderVehicleImages(c.Request.Context(), id, req.ImageIDs)
+	if errors.Is(err, database.ErrInvalidImageOrder) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		respondWriteError(c, err, "Error al ordenar imágenes")
+		return
+	}
+
+	images, err := h.repo.GetVehicleImages(c.Request.Context(), id, "")
+	if err != nil {
+		respondWriteError(c, err, "Error al obtener imágenes")
+		return
+	}
+
+	c.JSON(http.StatusOK, images)
+}
+
+// DeleteVehicleImage elimina una imagen de la galería
+func (h *AdminVehicleHandler) DeleteVehicleImage(c *gin.Context) {
+	id, ok := vehicleIDParam(c)
+	if !ok {
+		return
+	}
+
+	imageID, err := strconv.Atoi(c.Param("image_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID de imagen inválido",
+		})
+		return
+	}
+
+	err = h.repo.DeleteVehicleImage(c.Request.Context(), id, imageID)
+	if errors.Is(err, database.ErrVehicleImageNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Imagen no encontrada",
+		})
+		return
+	}
+	if err != nil {
+		respondWriteError(c, err, "Error al eliminar imagen")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UpsertVehicleSpecs crea o actualiza especificaciones técnicas del vehículo
+func (h *AdminVehicleHandler) UpsertVehicleSpecs(c *gin.Context) {
+	id, ok := vehicleIDParam(c)
+	if !ok {
+		return
+	}
+
+	var specs []models.VehicleSpec
+	if err := c.ShouldBindJSON(&specs); err != nil || len(specs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Se requiere una lista de especificaciones",
+		})
+		return
+	}
+
+	// Los errores se indexan por posición: el nombre puede estar vacío o repetirse
+	errs := models.ValidationErrors{}
+	for i := range specs {
+		s := &specs[i]
+		s.Value = strings.TrimSpace(s.Value)
+		switch {
+		case !models.ValidSpecName(s.Name):
+			errs[fmt.Sprintf("specs[%d].name", i)] = "nombre inválido (snake_case, máximo 100 caracteres)"
+		case s.Value == "":
+			errs[fmt.Sprintf("specs[%d].value", i)] = "el valor es obligatorio"
+		case len(s.Unit) > 20:
+			errs[fmt.Sprintf("specs[%d].unit", i)] = "unidad de máximo 20 caracteres"
+		case len(s.Category) > 50:
+			errs[fmt.Sprintf("specs[%d].category", i)] = "categoría de máximo 50 caracteres"
+		}
+	}
+	if len(errs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Especificaciones inválidas",
+			"details": errs,
+		})
+		return
+	}
+
+	if err := h.repo.UpsertVehicleSpecs(c.Request.Context(), id, specs); err != nil {
+		respondWriteError(c, err, "Error al guardar especificaciones")
+		return
+	}
+
+	result, err := h.repo.GetVehicleSpecs(c.Request.Context(), id)
+	if err != nil {
+		respondWriteError(c, err, "Error al obtener especificaciones")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.GroupSpecsByCategory(result))
+}
+
+// DeleteVehicleSpec elimina una especificación por nombre
+func (h *AdminVehicleHandler) DeleteVehicleSpec(c *gin.Context) {
+	id, ok := vehicleIDParam(c)
+	if !ok {
+		return
+	}
+
+	err := h.repo.DeleteVehicleSpec(c.Request.Context(), id, c.Param("name"))
+	if errors.Is(err, database.ErrVehicleSpecNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Especificación no encontrada",
+		})
+		return
+	}
+	if err != nil {
+		respondWriteError(c, err, "Error al eliminar especificación")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// vehicleIDParam lee el parámetro :id y responde 400 si no es válido
+func vehicleIDParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// respondWriteError traduce los errores de escritura del repositorio a respuestas HTTP
+func respondWriteError(c *gin.Context, err error, message string) {
+	var validationErrs models.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Datos de vehículo inválidos",
+			"details": validationErrs,
+		})
+	case errors.Is(err, database.ErrDuplicateVehicle):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Vehículo duplicado",
+			"details": models.ValidationErrors{"model": err.Error()},
+		})
+	case errors.Is(err, database.ErrVehicleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Vehículo no encontrado",
+		})
+	default:
+		log.Printf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": message,
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newAdminTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	store := newTestStore(t)
+
+	h := NewAdminVehicleHandler(store)
+	router := gin.New()
+	router.POST("/api/admin/vehicles", h.CreateVehicle)
+	router.PUT("/api/admin/vehicles/:id", h.UpdateVehicle)
+	router.PATCH("/api/admin/vehicles/:id", h.PatchVehicle)
+	router.PUT("/api/admin/vehicles/:id/specs", h.UpsertVehicleSpecs)
+	return router
+}
+
+// adminWrite envía body como JSON y devuelve el código y los detalles de error de la respuesta
+func adminWrite(t *testing.T, router *gin.Engine, method, path string, body interface{}) (int, map[string]string) {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	w := sendJSON(router, method, path, string(data))
+	var resp struct {
+		Details map[string]string `json:"details"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("respuesta no es JSON (%d): %s", w.Code, w.Body.String())
+	}
+	return w.Code, resp.Details
+}
+
+func TestAdminVehicleNaturalKeyConflict(t *testing.T) {
+	router := newAdminTestRouter(t)
+
+	corolla := map[string]interface{}{
+		"brand_id": 1, "model": "COROLLA", "year": 2024, "type_id": 1, "price": 400000,
+		"fuel_type_id": 1, "transmission_id": 2, "seats": 5,
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		want   int
+	}{
+		{"alta duplicada", http.MethodPost, "/api/admin/vehicles", corolla, http.StatusConflict},
+		{"reemplazo sobre otro vehículo", http.MethodPut, "/api/admin/vehicles/7", corolla, http.StatusConflict},
+		{"parche sobre otro vehículo", http.MethodPatch, "/api/admin/vehicles/2",
+			map[string]interface{}{"model": "corolla"}, http.StatusConflict},
+		{"reemplazo del mismo vehículo", http.MethodPut, "/api/admin/vehicles/1", corolla, http.StatusOK},
+		{"parche que conserva la llave", http.MethodPatch, "/api/admin/vehicles/1",
+			map[string]interface{}{"price": 410000}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, details := adminWrite(t, router, tt.method, tt.path, tt.body)
+			if code != tt.want {
+				t.Fatalf("código = %d, se esperaba %d (detalles %v)", code, tt.want, details)
+			}
+			if tt.want == http.StatusConflict && details["model"] == "" {
+				t.Errorf("falta el detalle del campo model: %v", details)
+			}
+		})
+	}
+}
+
+func TestAdminUpsertSpecsErrorsByIndex(t *testing.T) {
+	router := newAdminTestRouter(t)
+
+	specs := []map[string]string{
+		{"name": "ground_clearance_mm", "value": "200", "unit": "mm"},
+		{"name": "", "value": "5"},
+		{"name": "", "value": "6"},
+		{"name": "wheelbase_mm", "value": " "},
+	}
+	code, details := adminWrite(t, router, http.MethodPut, "/api/admin/vehicles/1/specs", specs)
+	if code != http.StatusBadRequest {
+		t.Fatalf("código = %d, se esperaba 400", code)
+	}
+	want := []string{"specs[1].name", "specs[2].name", "specs[3].value"}
+	if len(details) != len(want) {
+		t.Errorf("detalles = %v, se esperaban %v", details, want)
+	}
+	for _, field := range want {
+		if details[field] == "" {
+			t.Errorf("falta el error de %s: %v", field, details)
+		}
+	}
+
+	code, _ = adminWrite(t, router, http.MethodPut, "/api/admin/vehicles/1/specs", specs[:1])
+	if code != http.StatusOK {
+		t.Errorf("especificación válida: código = %d", code)
+	}
+}
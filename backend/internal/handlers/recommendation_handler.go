@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vehiculos/backend/internal/database"
+	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/recommend"
+)
+
+type RecommendationHandler struct {
+	vehicles database.VehicleStore
+}
+
+func NewRecommendationHandler(vehicles database.VehicleStore) *RecommendationHandler {
+	return &RecommendationHandler{vehicles: vehicles}
+}
+
+// GetRecommendations ordena los vehículos según el perfil de necesidades del cuerpo JSON y
+// devuelve cada uno con el desglose de su puntaje por criterio
+func (h *RecommendationHandler) GetRecommendations(c *gin.Context) {
+	var req struct {
+		recommend.Profile
+		Limit int `json:"limit"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Perfil inválido",
+		})
+		return
+	}
+
+	if req.Limit == 0 {
+		req.Limit = recommend.DefaultLimit
+	}
+	if req.Limit < 1 || req.Limit > recommend.MaxLimit {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("limit debe estar entre 1 y %d", recommend.MaxLimit),
+		})
+		return
+	}
+
+	profile := req.Profile
+	profile.Normalize()
+	if err := profile.Validate(); err != nil {
+		var validationErrs models.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Perfil inválido",
+				"details": validationErrs,
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	catalog, err := h.vehicles.GetScoringCatalog(c.Request.Context())
+	if err != nil {
+		log.Printf("Error al obtener vehículos para recomendar: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error al obtener recomendaciones",
+		})
+		return
+	}
+
+	recommendations, candidates := recommend.Recommend(profile, catalog, req.Limit)
+	c.JSON(http.StatusOK, gin.H{
+		"profile":         profile,
+		"weights":         profile.Weights(),
+		"candidates":      candidates,
+		"recommendations": recommendations,
+	})
+}
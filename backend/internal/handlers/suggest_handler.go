@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vehiculos/backend/internal/suggest"
+)
+
+// defaultSuggestLimit es el número de sugerencias si no se indica limit
+const defaultSuggestLimit = 8
+
+type SuggestHandler struct {
+	suggester *suggest.Suggester
+}
+
+func NewSuggestHandler(suggester *suggest.Suggester) *SuggestHandler {
+	return &SuggestHandler{suggester: suggester}
+}
+
+// Suggest devuelve las marcas, modelos y características que completan q, para la caja de
+// búsqueda. Un q vacío devuelve una lista vacía.
+func (h *SuggestHandler) Suggest(c *gin.Context) {
+	q := c.Query("q")
+	if len(q) > maxQueryLength {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "La búsqueda es demasiado larga",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultSuggestLimit)))
+	if err != nil || limit < 1 || limit > suggest.MaxLimit {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "limit debe ser un entero entre 1 y " + strconv.Itoa(suggest.MaxLimit),
+		})
+		return
+	}
+
+	suggestions, err := h.suggester.Suggest(c.Request.Context(), q, limit)
+	if err != nil {
+		log.Printf("Error al obtener sugerencias: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error al obtener sugerencias",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":       q,
+		"suggestions": suggestions,
+	})
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vehiculos/backend/internal/database"
+	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/preferences"
+)
+
+type UserPreferenceHandler struct {
+	repo    database.UserPreferenceStore
+	catalog database.CatalogStore
+}
+
+func NewUserPreferenceHandler(repo database.UserPreferenceStore, catalog database.CatalogStore) *UserPreferenceHandler {
+	return &UserPreferenceHandler{repo: repo, catalog: catalog}
+}
+
+// CreatePreference guarda una preferencia tipada para la sesión del asistente
+func (h *UserPreferenceHandler) CreatePreference(c *gin.Context) {
+	var req struct {
+		SessionID       string `json:"sessionId"`
+		PreferenceType  string `json:"preferenceType"`
+		PreferenceValue string `json:"preferenceValue"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Datos de preferencia inválidos",
+		})
+		return
+	}
+
+	if req.SessionID == "" || len(req.SessionID) > maxSessionIDLength {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "sessionId inválido",
+		})
+		return
+	}
+
+	pref := models.UserPreference{
+		SessionID:       req.SessionID,
+		PreferenceType:  models.PreferenceType(strings.ToLower(req.PreferenceType)),
+		PreferenceValue: strings.TrimSpace(req.PreferenceValue),
+	}
+	catalog, err := h.loadCatalog(c.Request.Context())
+	if err != nil {
+		log.Printf("Error al obtener catálogos: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error al obtener catálogos",
+		})
+		return
+	}
+	if err := preferences.Validate(pref.PreferenceType, pref.PreferenceValue, catalog); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := h.repo.CreatePreference(c.Request.Context(), &pref); err != nil {
+		log.Printf("Error al guardar preferencia: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error al guardar preferencia",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, pref)
+}
+
+// GetPreferences lista las preferencias guardadas en la sesión
+func (h *UserPreferenceHandler) GetPreferences(c *gin.Context) {
+	prefs, err := h.repo.GetPreferencesBySession(c.Request.Context(), c.Param("session_id"))
+	if err != nil {
+		log.Printf("Error al obtener preferencias: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error al obtener preferencias",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// DeletePreference elimina una preferencia de la sesión
+func (h *UserPreferenceHandler) DeletePreference(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	err = h.repo.DeletePreference(c.Request.Context(), c.Param("session_id"), id)
+	if errors.Is(err, database.ErrUserPreferenceNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Preferencia no encontrada",
+		})
+		return
+	}
+	if err != nil {
+		log.Printf("Error al eliminar preferencia %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error al eliminar preferencia",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteSessionPreferences elimina todas las preferencias de la sesión
+func (h *UserPreferenceHandler) DeleteSessionPreferences(c *gin.Context) {
+	if err := h.repo.DeleteSessionPreferences(c.Request.Context(), c.Param("session_id")); err != nil {
+		log.Printf("Error al eliminar preferencias de la sesión: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error al eliminar preferencias",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetPreferenceFilter devuelve el SearchFilter derivado de las preferencias de la sesión
+func (h *UserPreferenceHandler) GetPreferenceFilter(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	prefs, err := h.repo.GetPreferencesBySession(ctx, c.Param("session_id"))
+	if err != nil {
+		log.Printf("Error al obtener preferencias: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error al obtener preferencias",
+		})
+		return
+	}
+
+	catalog, err := h.loadCatalog(ctx)
+	if err != nil {
+		log.Printf("Error al obtener catálogos: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error al obtener catálogos",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"preferences": prefs,
+		"filter":      preferences.DeriveSearchFilter(prefs, catalog),
+	})
+}
+
+// loadCatalog reúne los catálogos con los que se validan y traducen las preferencias
+func (h *UserPreferenceHandler) loadCatalog(ctx context.Context) (preferences.Catalog, error) {
+	var catalog preferences.Catalog
+	var err error
+	if catalog.Brands, err = h.catalog.GetBrands(ctx); err != nil {
+		return catalog, err
+	}
+	if catalog.VehicleTypes, err = h.catalog.GetVehicleTypes(ctx); err != nil {
+		return catalog, err
+	}
+	catalog.FuelTypes, err = h.catalog.GetFuelTypes(ctx)
+	return catalog, err
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+func newPreferenceTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	store := newTestStore(t)
+
+	h := NewUserPreferenceHandler(store, store)
+	router := gin.New()
+	router.POST("/api/user-preferences", h.CreatePreference)
+	router.GET("/api/user-preferences/:session_id", h.GetPreferences)
+	router.GET("/api/user-preferences/:session_id/filter", h.GetPreferenceFilter)
+	router.DELETE("/api/user-preferences/:session_id", h.DeleteSessionPreferences)
+	router.DELETE("/api/user-preferences/:session_id/:id", h.DeletePreference)
+	return router
+}
+
+func TestCreatePreferenceValidation(t *testing.T) {
+	router := newPreferenceTestRouter(t)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"presupuesto", `{"sessionId": "s1", "preferenceType": "budget", "preferenceValue": "350000"}`, http.StatusCreated},
+		{"tipo en mayúsculas", `{"sessionId": "s1", "preferenceType": "USAGE", "preferenceValue": "family"}`, http.StatusCreated},
+		{"sin sesión", `{"preferenceType": "budget", "preferenceValue": "350000"}`, http.StatusBadRequest},
+		{"tipo desconocido", `{"sessionId": "s1", "preferenceType": "color", "preferenceValue": "rojo"}`, http.StatusBadRequest},
+		{"valor inválido", `{"sessionId": "s1", "preferenceType": "seats", "preferenceValue": "muchos"}`, http.StatusBadRequest},
+		{"combustible sin acento", `{"sessionId": "s1", "preferenceType": "fuel", "preferenceValue": "hibrido"}`, http.StatusCreated},
+		{"marca inexistente", `{"sessionId": "s1", "preferenceType": "brands", "preferenceValue": "Mazda, Tesla"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := sendJSON(router, http.MethodPost, "/api/user-preferences", tt.body)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPreferenceSessionLifecycle(t *testing.T) {
+	router := newPreferenceTestRouter(t)
+	for _, body := range []string{
+		`{"sessionId": "s1", "preferenceType": "budget", "preferenceValue": "600000"}`,
+		`{"sessionId": "s1", "preferenceType": "brands", "preferenceValue": "Mazda"}`,
+		`{"sessionId": "s2", "preferenceType": "seats", "preferenceValue": "7"}`,
+	} {
+		if w := sendJSON(router, http.MethodPost, "/api/user-preferences", body); w.Code != http.StatusCreated {
+			t.Fatalf("alta: %d %s", w.Code, w.Body.String())
+		}
+	}
+
+	w := sendJSON(router, http.MethodGet, "/api/user-preferences/s1/filter", "")
+	var resp struct {
+		Preferences []models.UserPreference `json:"preferences"`
+		Filter      models.SearchFilter     `json:"filter"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("respuesta no es JSON (%d): %s", w.Code, w.Body.String())
+	}
+	if len(resp.Preferences) != 2 {
+		t.Errorf("preferencias de s1 = %d, want 2", len(resp.Preferences))
+	}
+	if resp.Filter.PriceMax != 600000 || !reflect.DeepEqual(resp.Filter.BrandID, []int{2}) {
+		t.Errorf("filtro = %+v, want price_max 600000 y marca 2", resp.Filter)
+	}
+
+	// Una sesión no puede borrar preferencias de otra
+	if w := sendJSON(router, http.MethodDelete, "/api/user-preferences/s1/3", ""); w.Code != http.StatusNotFound {
+		t.Errorf("borrar preferencia ajena: status = %d, want 404", w.Code)
+	}
+	if w := sendJSON(router, http.MethodDelete, "/api/user-preferences/s1/1", ""); w.Code != http.StatusNoContent {
+		t.Errorf("borrar preferencia: status = %d, want 204", w.Code)
+	}
+	if w := sendJSON(router, http.MethodDelete, "/api/user-preferences/s1", ""); w.Code != http.StatusNoContent {
+		t.Errorf("borrar sesión: status = %d, want 204", w.Code)
+	}
+
+	var remaining []models.UserPreference
+	for session, want := range map[string]int{"s1": 0, "s2": 1} {
+		w := sendJSON(router, http.MethodGet, "/api/user-preferences/"+session, "")
+		if err := json.Unmarshal(w.Body.Bytes(), &remaining); err != nil {
+			t.Fatalf("respuesta no es JSON (%d): %s", w.Code, w.Body.String())
+		}
+		if len(remaining) != want {
+			t.Errorf("preferencias de %s = %d, want %d", session, len(remaining), want)
+		}
+	}
+}
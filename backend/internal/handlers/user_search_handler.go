@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vehiculos/backend/internal/database"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+const (
+	maxSessionIDLength = 100
+	maxQueryLength     = 500
+)
+
+type UserSearchHandler struct {
+	repo database.UserSearchStore
+}
+
+func NewUserSearchHandler(repo database.UserSearchStore) *UserSearchHandler {
+	return &UserSearchHandler{repo: repo}
+}
+
+type createUserSearchRequest struct {
+	Query             string              `json:"query"`
+	Filters           models.SearchFilter `json:"filters"`
+	ResultsCount      *int                `json:"resultsCount"`
+	SelectedVehicleID *int                `json:"selectedVehicleId"`
+	SessionID         string              `json:"sessionId"`
+}
+
+// CreateUserSearch guarda una búsqueda del usuario para analytics
+func (h *UserSearchHandler) CreateUserSearch(c *gin.Context) {
+	var req createUserSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Datos de búsqueda inválidos",
+		})
+		return
+	}
+
+	if msg := validateUserSearch(req); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": msg,
+		})
+		return
+	}
+
+	search := models.UserSearch{
+		SearchQuery:       req.Query,
+		Filters:           req.Filters,
+		SelectedVehicleID: req.SelectedVehicleID,
+		SessionID:         req.SessionID,
+	}
+	if search.SearchQuery == "" {
+		search.SearchQuery = req.Filters.Query
+	}
+	if req.ResultsCount != nil {
+		search.ResultsCount = *req.ResultsCount
+	}
+
+	if err := h.repo.CreateUserSearch(c.Request.Context(), &search); err != nil {
+		if errors.Is(err, database.ErrVehicleNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "El vehículo seleccionado no existe",
+			})
+			return
+		}
+		log.Printf("Error al guardar búsqueda: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error al guardar búsqueda",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, search)
+}
+
+// SetSelectedVehicle registra el vehículo en el que el usuario hizo clic tras buscar
+func (h *UserSearchHandler) SetSelectedVehicle(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	var req struct {
+		SessionID string `json:"sessionId" binding:"required"`
+		VehicleID int    `json:"vehicleId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.VehicleID < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Se requieren sessionId y vehicleId",
+		})
+		return
+	}
+
+	err = h.repo.SetSelectedVehicle(c.Request.Context(), id, req.SessionID, req.VehicleID)
+	switch {
+	case errors.Is(err, database.ErrVehicleNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "El vehículo seleccionado no existe",
+		})
+	case errors.Is(err, database.ErrUserSearchNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Búsqueda no encontrada",
+		})
+	case err != nil:
+		log.Printf("Error al registrar el vehículo seleccionado en la búsqueda %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error al actualizar búsqueda",
+		})
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func validateUserSearch(req createUserSearchRequest) string {
+	switch {
+	case req.SessionID == "":
+		return "sessionId es obligatorio"
+	case len(req.SessionID) > maxSessionIDLength:
+		return "sessionId demasiado largo"
+	case len(req.Query) > maxQueryLength || len(req.Filters.Query) > maxQueryLength:
+		return "La búsqueda de texto es demasiado larga"
+	case req.ResultsCount != nil && *req.ResultsCount < 0:
+		return "resultsCount no puede ser negativo"
+	case req.SelectedVehicleID != nil && *req.SelectedVehicleID < 1:
+		return "selectedVehicleId inválido"
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+func newUserSearchTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	store := newTestStore(t)
+
+	h := NewUserSearchHandler(store)
+	router := gin.New()
+	router.POST("/api/user-searches", h.CreateUserSearch)
+	router.PATCH("/api/user-searches/:id/selection", h.SetSelectedVehicle)
+	return router
+}
+
+func sendJSON(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+	return w
+}
+
+func TestCreateUserSearch(t *testing.T) {
+	router := newUserSearchTestRouter(t)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"búsqueda válida", `{"sessionId": "s1", "query": "suv", "resultsCount": 3}`, http.StatusCreated},
+		{"query sólo en los filtros", `{"sessionId": "s1", "filters": {"query": "sedan"}}`, http.StatusCreated},
+		{"sin sesión", `{"query": "suv"}`, http.StatusBadRequest},
+		{"conteo negativo", `{"sessionId": "s1", "resultsCount": -1}`, http.StatusBadRequest},
+		{"vehículo inexistente", `{"sessionId": "s1", "selectedVehicleId": 99}`, http.StatusBadRequest},
+		{"JSON malformado", `{"sessionId": `, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := sendJSON(router, http.MethodPost, "/api/user-searches", tt.body)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+		})
+	}
+
+	w := sendJSON(router, http.MethodPost, "/api/user-searches", `{"sessionId": "s1", "filters": {"query": "sedan"}}`)
+	var search models.UserSearch
+	if err := json.Unmarshal(w.Body.Bytes(), &search); err != nil {
+		t.Fatal(err)
+	}
+	if search.ID == 0 || search.SearchQuery != "sedan" {
+		t.Errorf("búsqueda guardada = %+v, want ID y la query de los filtros", search)
+	}
+}
+
+func TestSetSelectedVehicle(t *testing.T) {
+	router := newUserSearchTestRouter(t)
+	if w := sendJSON(router, http.MethodPost, "/api/user-searches", `{"sessionId": "s1", "query": "suv"}`); w.Code != http.StatusCreated {
+		t.Fatalf("alta: %d %s", w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"selección válida", "/api/user-searches/1/selection", `{"sessionId": "s1", "vehicleId": 2}`, http.StatusNoContent},
+		{"otra sesión", "/api/user-searches/1/selection", `{"sessionId": "s2", "vehicleId": 2}`, http.StatusNotFound},
+		{"búsqueda inexistente", "/api/user-searches/9/selection", `{"sessionId": "s1", "vehicleId": 2}`, http.StatusNotFound},
+		{"vehículo inexistente", "/api/user-searches/1/selection", `{"sessionId": "s1", "vehicleId": 99}`, http.StatusBadRequest},
+		{"sin vehículo", "/api/user-searches/1/selection", `{"sessionId": "s1"}`, http.StatusBadRequest},
+		{"ID inválido", "/api/user-searches/uno/selection", `{"sessionId": "s1", "vehicleId": 2}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := sendJSON(router, http.MethodPatch, tt.path, tt.body)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+		})
+	}
+}
@@ -1,41 +1,60 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/vehiculos/backend/internal/compare"
 	"github.com/vehiculos/backend/internal/database"
+	"github.com/vehiculos/backend/internal/export"
 	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/similar"
+	"github.com/vehiculos/backend/internal/synonyms"
 )
 
 type VehicleHandler struct {
-	repo *database.VehicleRepository
+	vehicles     database.VehicleStore
+	catalog      database.CatalogStore
+	searchLogger *database.SearchLogger
+	synonyms     *synonyms.Rewriter
 }
 
-func NewVehicleHandler(repo *database.VehicleRepository) *VehicleHandler {
-	return &VehicleHandler{repo: repo}
+// NewVehicleHandler crea el handler; searchLogger puede ser nil para no registrar búsquedas y
+// rewriter nil para buscar el texto tal cual, sin diccionario de sinónimos
+func NewVehicleHandler(vehicles database.VehicleStore, catalog database.CatalogStore, searchLogger *database.SearchLogger, rewriter *synonyms.Rewriter) *VehicleHandler {
+	return &VehicleHandler{vehicles: vehicles, catalog: catalog, searchLogger: searchLogger, synonyms: rewriter}
 }
 
-// GetVehicles obtiene todos los vehículos con paginación
+// GetVehicles obtiene todos los vehículos con paginación por página o por cursor
 func (h *VehicleHandler) GetVehicles(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	var filter models.SearchFilter
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err := parsePaginationMode(c, &filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
 
-	vehicles, total, err := h.repo.GetAllVehicles(c.Request.Context(), page, limit)
+	page, err := h.vehicles.SearchVehicles(c.Request.Context(), filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Error al obtener vehículos",
-		})
+		respondSearchError(c, err, "Error al obtener vehículos")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"vehicles": vehicles,
-		"total":    total,
-		"page":     page,
-		"limit":    limit,
-	})
+	response := pageResponse(page)
+	response["page"] = filter.Page
+	response["limit"] = filter.Limit
+	c.JSON(http.StatusOK, response)
 }
 
 // GetVehicleByID obtiene un vehículo por su ID
@@ -48,7 +67,7 @@ func (h *VehicleHandler) GetVehicleByID(c *gin.Context) {
 		return
 	}
 
-	vehicle, err := h.repo.GetVehicleByID(c.Request.Context(), id)
+	vehicle, err := h.vehicles.GetVehicleByID(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "Vehículo no encontrado",
@@ -59,8 +78,339 @@ func (h *VehicleHandler) GetVehicleByID(c *gin.Context) {
 	c.JSON(http.StatusOK, vehicle)
 }
 
+// GetVehicleImages obtiene la galería del vehículo, filtrable con ?type=interior|exterior|engine
+func (h *VehicleHandler) GetVehicleImages(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	imageType := c.Query("type")
+	if imageType != "" && !models.ValidImageType(imageType) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Tipo de imagen inválido",
+		})
+		return
+	}
+
+	images, err := h.vehicles.GetVehicleImages(c.Request.Context(), id, imageType)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error al obtener imágenes",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, images)
+}
+
+// GetSimilarVehicles sugiere los vehículos más parecidos al indicado, con las razones del
+// parecido. ?constraints=cheaper,same_type,... limita los candidatos.
+func (h *VehicleHandler) GetSimilarVehicles(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "ID inválido",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(similar.DefaultLimit)))
+	if err != nil || limit < 1 || limit > similar.MaxLimit {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("limit debe ser un entero entre 1 y %d", similar.MaxLimit),
+		})
+		return
+	}
+
+	constraints, err := similar.ParseConstraints(c.QueryArray("constraints"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	target, err := h.vehicles.GetVehicleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrVehicleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": "Vehículo no encontrado",
+			})
+			return
+		}
+		log.Printf("Error al obtener vehículo %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error al obtener vehículos similares",
+		})
+		return
+	}
+
+	catalog, err := h.vehicles.GetScoringCatalog(ctx)
+	if err != nil {
+		log.Printf("Error al obtener candidatos similares a %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error al obtener vehículos similares",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"vehicle_id":  id,
+		"constraints": constraints,
+		"similar":     similar.Rank(*target, catalog, constraints, limit),
+	})
+}
+
 // SearchVehicles busca vehículos con filtros
 func (h *VehicleHandler) SearchVehicles(c *gin.Context) {
+	filter, err := parseSearchFilter(c)
+	if err != nil {
+		respondFilterError(c, err)
+		return
+	}
+	query := filter.Query
+	rewrites := h.rewriteQuery(c, &filter)
+
+	page, err := h.vehicles.SearchVehicles(c.Request.Context(), filter)
+	if err != nil {
+		respondSearchError(c, err, "Error al buscar vehículos")
+		return
+	}
+
+	response := pageResponse(page)
+	response["page"] = filter.Page
+	response["limit"] = filter.Limit
+	response["filters"] = filter
+	if len(rewrites) > 0 {
+		response["rewrites"] = rewrites
+	}
+
+	// Conteos por faceta para el FilterSidebar (opcional porque añade consultas)
+	if facets, _ := strconv.ParseBool(c.Query("facets")); facets {
+		result, err := h.vehicles.GetSearchFacets(c.Request.Context(), filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error al calcular facetas",
+			})
+			return
+		}
+		response["facets"] = result
+	}
+
+	// Registrar sólo la primera página para no duplicar la misma búsqueda al paginar
+	if h.searchLogger != nil && filter.Page <= 1 && filter.Cursor == "" {
+		resultsCount := len(page.Vehicles)
+		if page.Total != nil {
+			resultsCount = *page.Total
+		}
+		h.searchLogger.Log(models.UserSearch{
+			SearchQuery:  query,
+			Filters:      filter,
+			ResultsCount: resultsCount,
+			SessionID:    sessionID(c),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ExportVehicles descarga todos los vehículos que cumplen el filtro (sin el límite de
+// paginación) en ?format=csv|jsonl|xlsx
+func (h *VehicleHandler) ExportVehicles(c *gin.Context) {
+	format, ok := export.Lookup(c.DefaultQuery("format", "csv"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Formato inválido, use csv, jsonl o xlsx",
+		})
+		return
+	}
+
+	filter, err := parseSearchFilter(c)
+	if err != nil {
+		respondFilterError(c, err)
+		return
+	}
+	h.rewriteQuery(c, &filter)
+
+	// El escritor se crea con la primera fila para poder responder 500 si la consulta falla
+	// antes de enviar algo
+	var out export.Writer
+	start := func() error {
+		filename := fmt.Sprintf("vehiculos-%s.%s", time.Now().Format("20060102"), format.Extension)
+		c.Header("Content-Type", format.ContentType)
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		c.Status(http.StatusOK)
+		w, err := format.NewWriter(c.Writer)
+		out = w
+		return err
+	}
+
+	err = h.vehicles.ExportVehicles(c.Request.Context(), filter, func(v models.Vehicle) error {
+		if out == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return out.Write(v)
+	})
+	if err == nil && out == nil {
+		err = start()
+	}
+	if err == nil {
+		err = out.Close()
+	}
+
+	if err != nil {
+		if !c.Writer.Written() {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "Error al exportar vehículos",
+			})
+			return
+		}
+		// Los encabezados ya se enviaron: sólo queda cortar la descarga
+		log.Printf("Error al exportar vehículos: %v", err)
+		abortDownload(c)
+	}
+}
+
+// abortDownload cierra la conexión sin terminar la respuesta, para que el cliente vea una
+// descarga fallida en lugar de un archivo truncado que parece completo
+func abortDownload(c *gin.Context) {
+	if w, ok := c.Writer.(interface{ Unwrap() http.ResponseWriter }); ok {
+		if conn, _, err := http.NewResponseController(w.Unwrap()).Hijack(); err == nil {
+			conn.Close()
+			return
+		}
+	}
+	// Sin Hijack (HTTP/2) net/http corta el stream al recibir ErrAbortHandler
+	panic(http.ErrAbortHandler)
+}
+
+// parsePaginationMode lee cursor y count; el cursor se valida al decodificarlo en el store
+func parsePaginationMode(c *gin.Context, filter *models.SearchFilter) error {
+	filter.Cursor = c.Query("cursor")
+	filter.Count = c.Query("count")
+	if !models.ValidCountMode(filter.Count) {
+		return fmt.Errorf("count inválido: %q (use exact, estimate o none)", filter.Count)
+	}
+	return nil
+}
+
+// pageResponse arma la respuesta común de listados y búsquedas
+func pageResponse(page *models.SearchPage) gin.H {
+	response := gin.H{
+		"vehicles":    page.Vehicles,
+		"total":       page.Total,
+		"next_cursor": nil,
+		"prev_cursor": nil,
+	}
+	if page.NextCursor != "" {
+		response["next_cursor"] = page.NextCursor
+	}
+	if page.PrevCursor != "" {
+		response["prev_cursor"] = page.PrevCursor
+	}
+	if page.TotalEstimated {
+		response["total_estimated"] = true
+	}
+	return response
+}
+
+// respondSearchError responde 400 ante un cursor o un orden inválidos y 500 ante cualquier otro error
+func respondSearchError(c *gin.Context, err error, message string) {
+	if errors.Is(err, database.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Cursor inválido o generado con otro orden u otros filtros",
+		})
+		return
+	}
+	if errors.Is(err, database.ErrInvalidSort) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": message,
+	})
+}
+
+// rewriteQuery aplica el diccionario de sinónimos a la búsqueda de texto. Si el catálogo no
+// responde, la búsqueda sigue sin reescribir en lugar de fallar.
+func (h *VehicleHandler) rewriteQuery(c *gin.Context, filter *models.SearchFilter) []synonyms.Rewrite {
+	if h.synonyms == nil {
+		return nil
+	}
+	original := *filter
+	rewrites, err := h.synonyms.Rewrite(c.Request.Context(), filter)
+	if err != nil {
+		log.Printf("Error al aplicar sinónimos: %v", err)
+		*filter = original
+		return nil
+	}
+	return rewrites
+}
+
+// respondFilterError responde 400 con el detalle por parámetro cuando hay errores de validación
+func respondFilterError(c *gin.Context, err error) {
+	var validationErrs models.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Filtros inválidos",
+			"details": validationErrs,
+		})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": err.Error(),
+	})
+}
+
+// parseRangeFilters lee <atributo>_min y <atributo>_max de cada rango de models.RangeFilters.
+// Los valores malformados se reportan por parámetro en lugar de ignorarse.
+func parseRangeFilters(c *gin.Context, filter *models.SearchFilter) models.ValidationErrors {
+	errs := models.ValidationErrors{}
+	for _, r := range models.RangeFilters {
+		bounds := []struct {
+			param      string
+			intField   func(f *models.SearchFilter) *int
+			floatField func(f *models.SearchFilter) *float64
+		}{
+			{r.Name + "_min", r.IntMin, r.FloatMin},
+			{r.Name + "_max", r.IntMax, r.FloatMax},
+		}
+		for _, b := range bounds {
+			raw := strings.TrimSpace(c.Query(b.param))
+			if raw == "" {
+				continue
+			}
+			if r.Integer() {
+				n, err := strconv.Atoi(raw)
+				if err != nil {
+					errs[b.param] = "debe ser un número entero"
+					continue
+				}
+				*b.intField(filter) = n
+				continue
+			}
+			n, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				errs[b.param] = "debe ser un número"
+				continue
+			}
+			*b.floatField(filter) = n
+		}
+	}
+	return errs
+}
+
+// parseSearchFilter lee el filtro de búsqueda del query string y, en POST, del cuerpo JSON
+func parseSearchFilter(c *gin.Context) (models.SearchFilter, error) {
 	var filter models.SearchFilter
 	
 	// Obtener parámetros de query
@@ -68,32 +418,13 @@ func (h *VehicleHandler) SearchVehicles(c *gin.Context) {
 	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "20"))
 	filter.Query = c.Query("q")
 	filter.SortBy = c.Query("sort_by")
-	
-	// Parsear filtros de precio
-	if priceMin := c.Query("price_min"); priceMin != "" {
-		filter.PriceMin, _ = strconv.ParseFloat(priceMin, 64)
-	}
-	if priceMax := c.Query("price_max"); priceMax != "" {
-		filter.PriceMax, _ = strconv.ParseFloat(priceMax, 64)
+	if err := parsePaginationMode(c, &filter); err != nil {
+		return filter, err
 	}
 	
-	// Parsear filtros de año
-	if yearMin := c.Query("year_min"); yearMin != "" {
-		filter.YearMin, _ = strconv.Atoi(yearMin)
-	}
-	if yearMax := c.Query("year_max"); yearMax != "" {
-		filter.YearMax, _ = strconv.Atoi(yearMax)
-	}
-	
-	// Parsear otros filtros numéricos
-	if doorsMin := c.Query("doors_min"); doorsMin != "" {
-		filter.DoorsMin, _ = strconv.Atoi(doorsMin)
-	}
-	if seatsMin := c.Query("seats_min"); seatsMin != "" {
-		filter.SeatsMin, _ = strconv.Atoi(seatsMin)
-	}
-	if fuelEconomyMin := c.Query("fuel_economy_min"); fuelEconomyMin != "" {
-		filter.FuelEconomyMin, _ = strconv.ParseFloat(fuelEconomyMin, 64)
+	// Rangos numéricos: price_min, price_max, horsepower_min, ...
+	if errs := parseRangeFilters(c, &filter); len(errs) > 0 {
+		return filter, errs
 	}
 	
 	// Parsear arrays de IDs
@@ -129,33 +460,113 @@ func (h *VehicleHandler) SearchVehicles(c *gin.Context) {
 		}
 	}
 
-	// Si se envía el body como JSON, intentar parsearlo
+	// Características: features_all=Apple CarPlay&features_all=Quemacocos
+	filter.FeaturesAll = c.QueryArray("features_all")
+	filter.FeaturesAny = c.QueryArray("features_any")
+
+	// Filtros sobre especificaciones: spec[ground_clearance_mm]>=200
+	specs, err := parseSpecFilters(c)
+	if err != nil {
+		return filter, err
+	}
+	filter.Specs = specs
+
+	// En POST el cuerpo JSON complementa al query; un cuerpo vacío deja sólo los filtros del
+	// query, uno malformado es un error
 	if c.Request.Method == "POST" {
-		if err := c.ShouldBindJSON(&filter); err != nil {
-			// No es error crítico, los filtros ya se obtuvieron del query
+		if err := c.ShouldBindJSON(&filter); err != nil && !errors.Is(err, io.EOF) {
+			return filter, fmt.Errorf("cuerpo JSON inválido: %v", err)
+		}
+		for _, spec := range filter.Specs {
+			if !models.ValidSpecName(spec.Name) || !models.ValidSpecOperator(spec.Operator) {
+				return filter, fmt.Errorf("filtro de especificación inválido: %s", spec.Name)
+			}
+		}
+	}
+	// Las expansiones sólo las genera el diccionario de sinónimos
+	filter.QueryExpansions = nil
+	if errs := filter.ValidateRanges(); len(errs) > 0 {
+		return filter, errs
+	}
+	filter.FeaturesAll = models.NormalizeFeatures(filter.FeaturesAll)
+	filter.FeaturesAny = models.NormalizeFeatures(filter.FeaturesAny)
+	if len(filter.FeaturesAll) > models.MaxFeatureFilters || len(filter.FeaturesAny) > models.MaxFeatureFilters {
+		return filter, fmt.Errorf("se permiten hasta %d características por filtro", models.MaxFeatureFilters)
+	}
+	if !models.ValidCountMode(filter.Count) {
+		return filter, fmt.Errorf("count inválido: %q (use exact, estimate o none)", filter.Count)
+	}
+	if _, err := database.SearchSort(filter); err != nil {
+		return filter, err
+	}
+
+	return filter, nil
+}
+
+// CompareVehicles compara hasta 4 vehículos y marca los mejores y peores valores
+func (h *VehicleHandler) CompareVehicles(c *gin.Context) {
+	var req struct {
+		VehicleIDs []int `json:"vehicleIds" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Se requiere la lista vehicleIds",
+		})
+		return
+	}
+
+	// Eliminar IDs duplicados conservando el orden
+	seen := make(map[int]bool, len(req.VehicleIDs))
+	var ids []int
+	for _, id := range req.VehicleIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
 		}
 	}
 
-	vehicles, total, err := h.repo.SearchVehicles(c.Request.Context(), filter)
+	if len(ids) < 2 || len(ids) > compare.MaxVehicles {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Se pueden comparar entre 2 y %d vehículos", compare.MaxVehicles),
+		})
+		return
+	}
+
+	vehicles, err := h.vehicles.GetVehiclesByIDs(c.Request.Context(), ids)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Error al buscar vehículos",
+			"error": "Error al comparar vehículos",
+		})
+		return
+	}
+
+	if len(vehicles) != len(ids) {
+		found := make(map[int]bool, len(vehicles))
+		for _, v := range vehicles {
+			found[v.ID] = true
+		}
+		var missing []int
+		for _, id := range ids {
+			if !found[id] {
+				missing = append(missing, id)
+			}
+		}
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":       "Vehículo no encontrado",
+			"missing_ids": missing,
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"vehicles": vehicles,
-		"total":    total,
-		"page":     filter.Page,
-		"limit":    filter.Limit,
-		"filters":  filter,
+		"vehicles":   vehicles,
+		"comparison": compare.Build(vehicles),
 	})
 }
 
 // GetBrands obtiene todas las marcas
 func (h *VehicleHandler) GetBrands(c *gin.Context) {
-	brands, err := h.repo.GetBrands(c.Request.Context())
+	brands, err := h.catalog.GetBrands(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Error al obtener marcas",
@@ -168,7 +579,7 @@ func (h *VehicleHandler) GetBrands(c *gin.Context) {
 
 // GetVehicleTypes obtiene todos los tipos de vehículo
 func (h *VehicleHandler) GetVehicleTypes(c *gin.Context) {
-	types, err := h.repo.GetVehicleTypes(c.Request.Context())
+	types, err := h.catalog.GetVehicleTypes(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Error al obtener tipos de vehículo",
@@ -181,7 +592,7 @@ func (h *VehicleHandler) GetVehicleTypes(c *gin.Context) {
 
 // GetFuelTypes obtiene todos los tipos de combustible
 func (h *VehicleHandler) GetFuelTypes(c *gin.Context) {
-	types, err := h.repo.GetFuelTypes(c.Request.Context())
+	types, err := h.catalog.GetFuelTypes(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Error al obtener tipos de combustible",
@@ -194,7 +605,7 @@ func (h *VehicleHandler) GetFuelTypes(c *gin.Context) {
 
 // GetTransmissions obtiene todos los tipos de transmisión
 func (h *VehicleHandler) GetTransmissions(c *gin.Context) {
-	transmissions, err := h.repo.GetTransmissions(c.Request.Context())
+	transmissions, err := h.catalog.GetTransmissions(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Error al obtener tipos de transmisión",
@@ -205,9 +616,30 @@ func (h *VehicleHandler) GetTransmissions(c *gin.Context) {
 	c.JSON(http.StatusOK, transmissions)
 }
 
+// GetFeatures lista las características con el número de vehículos que las tienen; acepta los
+// mismos filtros que la búsqueda para que los conteos del sidebar sigan a la selección
+func (h *VehicleHandler) GetFeatures(c *gin.Context) {
+	filter, err := parseSearchFilter(c)
+	if err != nil {
+		respondFilterError(c, err)
+		return
+	}
+	h.rewriteQuery(c, &filter)
+
+	features, err := h.vehicles.GetFeatureCounts(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Error al obtener características",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, features)
+}
+
 // GetFilters obtiene todos los filtros disponibles (marcas, tipos, etc.)
 func (h *VehicleHandler) GetFilters(c *gin.Context) {
-	brands, err := h.repo.GetBrands(c.Request.Context())
+	brands, err := h.catalog.GetBrands(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Error al obtener filtros",
@@ -215,7 +647,7 @@ func (h *VehicleHandler) GetFilters(c *gin.Context) {
 		return
 	}
 
-	types, err := h.repo.GetVehicleTypes(c.Request.Context())
+	types, err := h.catalog.GetVehicleTypes(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Error al obtener filtros",
@@ -223,7 +655,7 @@ func (h *VehicleHandler) GetFilters(c *gin.Context) {
 		return
 	}
 
-	fuelTypes, err := h.repo.GetFuelTypes(c.Request.Context())
+	fuelTypes, err := h.catalog.GetFuelTypes(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Error al obtener filtros",
@@ -231,7 +663,7 @@ func (h *VehicleHandler) GetFilters(c *gin.Context) {
 		return
 	}
 
-	transmissions, err := h.repo.GetTransmissions(c.Request.Context())
+	transmissions, err := h.catalog.GetTransmissions(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Error al obtener filtros",
@@ -245,4 +677,78 @@ func (h *VehicleHandler) GetFilters(c *gin.Context) {
 		"fuel_types":    fuelTypes,
 		"transmissions": transmissions,
 	})
-}
\ No newline at end of file
+}
+// sessionID obtiene el identificador de sesión del header X-Session-ID o del query
+func sessionID(c *gin.Context) string {
+	id := c.GetHeader("X-Session-ID")
+	if id == "" {
+		id = c.Query("session_id")
+	}
+	if len(id) > maxSessionIDLength {
+		return ""
+	}
+	return id
+}
+
+// parseSpecFilters lee los filtros spec[nombre] del query. Al decodificar la URL,
+// "spec[x]>=200" llega como clave "spec[x]>" y valor "200", y "spec[x]>200" como
+// clave completa sin valor, así que se reconstruye la expresión antes de interpretarla.
+func parseSpecFilters(c *gin.Context) ([]models.SpecFilter, error) {
+	var specs []models.SpecFilter
+
+	for key, values := range c.Request.URL.Query() {
+		if !strings.HasPrefix(key, "spec[") {
+			continue
+		}
+		end := strings.Index(key, "]")
+		if end < 0 {
+			return nil, fmt.Errorf("filtro de especificación inválido: %s", key)
+		}
+
+		name := key[len("spec["):end]
+		suffix := key[end+1:]
+		if !models.ValidSpecName(name) {
+			return nil, fmt.Errorf("nombre de especificación inválido: %s", name)
+		}
+
+		for _, value := range values {
+			expr := suffix
+			switch {
+			case suffix == "" && value != "" && strings.ContainsRune("<>=", rune(value[0])):
+				expr = value
+			case value != "":
+				expr = suffix + "=" + value
+			}
+
+			spec, err := parseSpecExpression(name, expr)
+			if err != nil {
+				return nil, err
+			}
+			specs = append(specs, spec)
+		}
+	}
+
+	// Orden estable para que la misma URL produzca siempre el mismo filtro
+	sort.Slice(specs, func(i, j int) bool {
+		if specs[i].Name != specs[j].Name {
+			return specs[i].Name < specs[j].Name
+		}
+		return specs[i].Operator < specs[j].Operator
+	})
+
+	return specs, nil
+}
+
+// parseSpecExpression interpreta ">=200", "<5", "=3" como operador y valor numérico
+func parseSpecExpression(name, expr string) (models.SpecFilter, error) {
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(expr, op) {
+			value, err := strconv.ParseFloat(strings.TrimSpace(expr[len(op):]), 64)
+			if err != nil {
+				break
+			}
+			return models.SpecFilter{Name: name, Operator: op, Value: value}, nil
+		}
+	}
+	return models.SpecFilter{}, fmt.Errorf("valor inválido para spec[%s]: %s", name, expr)
+}
@@ -0,0 +1,516 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vehiculos/backend/internal/database/memory"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// testSeed es un catálogo pequeño con precios, potencias y especificaciones distintos para
+// que cada filtro y orden tenga un resultado inequívoco
+func testSeed() memory.Seed {
+	vehicle := func(id, brand int, model string, year, vehicleType, fuel, transmission int, price float64,
+		hp int, economy float64, features []string, clearance string) memory.SeedVehicle {
+		sv := memory.SeedVehicle{
+			ID: id,
+			VehicleInput: models.VehicleInput{
+				BrandID: brand, Model: model, Year: year, TypeID: vehicleType, FuelTypeID: fuel,
+				TransmissionID: transmission, Price: price, Horsepower: hp, FuelEconomy: economy,
+				Doors: 4, Seats: 5, Features: features,
+			},
+		}
+		if clearance != "" {
+			sv.Specs = []models.VehicleSpec{{Name: "ground_clearance_mm", Value: clearance, Unit: "mm", Category: "dimensions"}}
+		}
+		return sv
+	}
+
+	return memory.Seed{
+		Brands:        []models.Brand{{ID: 1, Name: "Toyota"}, {ID: 2, Name: "Mazda"}, {ID: 3, Name: "Ford"}},
+		VehicleTypes:  []models.VehicleType{{ID: 1, Name: "Sedan"}, {ID: 2, Name: "SUV"}, {ID: 3, Name: "Pickup"}},
+		FuelTypes:     []models.FuelType{{ID: 1, Name: "Gasolina"}, {ID: 2, Name: "Híbrido"}, {ID: 3, Name: "Eléctrico"}},
+		Transmissions: []models.Transmission{{ID: 1, Name: "Manual"}, {ID: 2, Name: "Automática"}},
+		Vehicles: []memory.SeedVehicle{
+			vehicle(1, 1, "Corolla", 2024, 1, 1, 2, 390000, 169, 16.5, []string{"Apple CarPlay", "Cámara de reversa"}, "135"),
+			vehicle(2, 1, "RAV4", 2024, 2, 2, 2, 720000, 219, 19, []string{"Apple CarPlay", "Quemacocos", "Cámara de reversa"}, "210"),
+			vehicle(3, 2, "CX-5", 2023, 2, 1, 2, 560000, 187, 14, []string{"Quemacocos", "Cámara de reversa"}, "193"),
+			vehicle(4, 2, "Mazda 3", 2022, 1, 1, 1, 430000, 155, 15.5, []string{"Apple CarPlay"}, ""),
+			vehicle(5, 3, "Ranger", 2023, 3, 1, 1, 650000, 210, 10, []string{"Cámara de reversa"}, "232"),
+			vehicle(6, 3, "Mustang Mach-E", 2024, 2, 3, 2, 1100000, 290, 0, []string{"Apple CarPlay", "Quemacocos"}, "140"),
+			vehicle(7, 1, "Hilux", 2021, 3, 1, 1, 520000, 164, 11, nil, ""),
+		},
+	}
+}
+
+// newTestStore crea un store en memoria con testSeed; cada prueba recibe su propia copia
+func newTestStore(t *testing.T) *memory.Store {
+	t.Helper()
+	store, err := memory.NewStore(testSeed())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	return store
+}
+
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	store := newTestStore(t)
+
+	h := NewVehicleHandler(store, store, nil, nil)
+	router := gin.New()
+	router.GET("/api/vehicles/search", h.SearchVehicles)
+	router.POST("/api/vehicles/search", h.SearchVehicles)
+	router.POST("/api/vehicles/compare", h.CompareVehicles)
+	router.GET("/api/features", h.GetFeatures)
+	return router
+}
+
+// searchResponse es la forma de la respuesta de búsqueda que revisan las pruebas
+type searchResponse struct {
+	Vehicles   []models.Vehicle     `json:"vehicles"`
+	Total      *int                 `json:"total"`
+	NextCursor *string              `json:"next_cursor"`
+	PrevCursor *string              `json:"prev_cursor"`
+	Facets     *models.SearchFacets `json:"facets"`
+	Error      string               `json:"error"`
+	Details    map[string]string    `json:"details"`
+}
+
+func (r searchResponse) ids() []int {
+	ids := []int{}
+	for _, v := range r.Vehicles {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func search(t *testing.T, router *gin.Engine, query url.Values) (int, searchResponse) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/vehicles/search?"+query.Encode(), nil))
+
+	var body searchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("respuesta no es JSON (%d): %s", w.Code, w.Body.String())
+	}
+	return w.Code, body
+}
+
+func TestSearchVehiclesFilters(t *testing.T) {
+	tests := []struct {
+		name  string
+		query url.Values
+		want  []int
+	}{
+		{"sin filtros", url.Values{}, []int{1, 4, 7, 3, 5, 2, 6}},
+		{"una marca", url.Values{"brand_id": {"1"}}, []int{1, 7, 2}},
+		{"varias marcas", url.Values{"brand_id": {"1", "2"}}, []int{1, 4, 7, 3, 2}},
+		{"tipo", url.Values{"type_id": {"2"}}, []int{3, 2, 6}},
+		{"combustibles", url.Values{"fuel_type_id": {"2", "3"}}, []int{2, 6}},
+		{"transmisión", url.Values{"transmission_id": {"1"}}, []int{4, 7, 5}},
+		{"rango de precio", url.Values{"price_min": {"500000"}, "price_max": {"700000"}}, []int{7, 3, 5}},
+		{"año mínimo", url.Values{"year_min": {"2023"}}, []int{1, 3, 5, 2, 6}},
+		{"potencia mínima", url.Values{"horsepower_min": {"200"}}, []int{5, 2, 6}},
+		{"rendimiento mínimo", url.Values{"fuel_economy_min": {"15"}}, []int{1, 4, 2}},
+		{"todas las características", url.Values{"features_all": {"Apple CarPlay", "Quemacocos"}}, []int{2, 6}},
+		{"alguna característica", url.Values{"features_any": {"Quemacocos", "Cámara de reversa"}}, []int{1, 3, 5, 2, 6}},
+		{"texto en la marca", url.Values{"q": {"mazda"}}, []int{4, 3}},
+		{"texto sin acentos en características", url.Values{"q": {"camara"}}, []int{1, 3, 5, 2}},
+		{"especificación", url.Values{"spec[ground_clearance_mm]": {">=200"}}, []int{5, 2}},
+		{"filtros combinados", url.Values{"type_id": {"2"}, "fuel_type_id": {"1"}, "features_any": {"Quemacocos"}}, []int{3}},
+		{"sin resultados", url.Values{"brand_id": {"3"}, "year_max": {"2022"}}, []int{}},
+	}
+
+	router := newTestRouter(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := tt.query
+			query.Set("sort_by", "price_asc")
+			status, body := search(t, router, query)
+			if status != http.StatusOK {
+				t.Fatalf("status = %d (%s)", status, body.Error)
+			}
+			if got := body.ids(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ids = %v, want %v", got, tt.want)
+			}
+			if body.Total == nil || *body.Total != len(tt.want) {
+				t.Errorf("total = %v, want %d", body.Total, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestSearchVehiclesTextTolerance(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"toyta", []int{1, 7, 2}},      // error de escritura en la marca
+		{"mustag", []int{6}},           // letra faltante en el modelo
+		{"cx5", []int{3}},              // modelo sin guion
+		{"QUEMACOCOS", []int{3, 2, 6}}, // mayúsculas
+		{"ford pickup", []int{5}},      // todos los términos deben coincidir
+		{"submarino", []int{}},         // sin coincidencias
+	}
+
+	router := newTestRouter(t)
+	for _, tt := range tests {
+		_, body := search(t, router, url.Values{"q": {tt.query}, "sort_by": {"price_asc"}})
+		if got := body.ids(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("q=%q: ids = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchVehiclesSort(t *testing.T) {
+	tests := map[string][]int{
+		"horsepower_desc":          {6, 2, 5, 3, 1, 7, 4},
+		"year_desc,price_asc":      {1, 2, 6, 3, 5, 4, 7},
+		"brand_asc,horsepower_asc": {5, 6, 4, 3, 7, 1, 2},
+	}
+	router := newTestRouter(t)
+	for sortBy, want := range tests {
+		_, body := search(t, router, url.Values{"sort_by": {sortBy}})
+		if got := body.ids(); !reflect.DeepEqual(got, want) {
+			t.Errorf("sort_by=%s: ids = %v, want %v", sortBy, got, want)
+		}
+	}
+}
+
+func TestSearchVehiclesPostBody(t *testing.T) {
+	router := newTestRouter(t)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"sin cuerpo usa el query", "", http.StatusOK},
+		{"filtros en el cuerpo", `{"brand_ids": [2]}`, http.StatusOK},
+		{"JSON malformado", `{"brand_ids": [2`, http.StatusBadRequest},
+		{"tipo incorrecto", `{"brand_ids": "Mazda"}`, http.StatusBadRequest},
+		{"especificación inválida", `{"specs": [{"name": "Altura!", "operator": ">=", "value": 1}]}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/vehicles/search?limit=50", bytes.NewBufferString(tt.body)))
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSearchVehiclesSpecSyntax(t *testing.T) {
+	// El operador puede ir en la llave o en el valor (codificado)
+	tests := map[string][]int{
+		"spec[ground_clearance_mm]>=200":      {5, 2},
+		"spec[ground_clearance_mm]=%3E%3D200": {5, 2},
+		"spec[ground_clearance_mm]<140":       {1},
+		"spec[ground_clearance_mm]=193":       {3},
+	}
+	router := newTestRouter(t)
+	for rawQuery, want := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/vehicles/search?sort_by=price_asc&"+rawQuery, nil))
+		var body searchResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", rawQuery, w.Code, w.Body.String())
+		}
+		if got := body.ids(); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: ids = %v, want %v", rawQuery, got, want)
+		}
+	}
+}
+
+func TestSearchVehiclesInvalidFilters(t *testing.T) {
+	router := newTestRouter(t)
+
+	status, body := search(t, router, url.Values{"price_min": {"barato"}, "year_max": {"2024.5"}})
+	if status != http.StatusBadRequest || body.Details["price_min"] == "" || body.Details["year_max"] == "" {
+		t.Errorf("rango malformado: %d %+v", status, body)
+	}
+
+	for _, query := range []url.Values{
+		{"sort_by": {"color_asc"}},
+		{"count": {"aproximado"}},
+		{"spec[ground_clearance_mm]": {"~200"}},
+		{"cursor": {"no-es-un-cursor"}},
+	} {
+		if status, body := search(t, router, query); status != http.StatusBadRequest || body.Error == "" {
+			t.Errorf("%v: status = %d, want 400 con error", query, status)
+		}
+	}
+}
+
+func TestSearchVehiclesFacets(t *testing.T) {
+	router := newTestRouter(t)
+
+	_, body := search(t, router, url.Values{"type_id": {"2"}})
+	if body.Facets != nil {
+		t.Error("las facetas sólo se calculan con facets=true")
+	}
+
+	_, body = search(t, router, url.Values{"type_id": {"2"}, "facets": {"true"}})
+	if body.Facets == nil {
+		t.Fatal("falta facets en la respuesta")
+	}
+	counts := func(facets []models.FacetCount) map[string]int {
+		m := make(map[string]int)
+		for _, f := range facets {
+			m[f.Name] = f.Count
+		}
+		return m
+	}
+
+	// Cada dimensión se cuenta sin su propio filtro para mostrar las alternativas
+	if got, want := counts(body.Facets.VehicleTypes), map[string]int{"Sedan": 2, "SUV": 3, "Pickup": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tipos = %v, want %v", got, want)
+	}
+	if got := counts(body.Facets.Brands); got["Toyota"] != 1 || got["Mazda"] != 1 || got["Ford"] != 1 {
+		t.Errorf("marcas = %v, want una de cada una entre los SUV", got)
+	}
+	if got := counts(body.Facets.FuelTypes); got["Gasolina"] != 1 || got["Híbrido"] != 1 || got["Eléctrico"] != 1 {
+		t.Errorf("combustibles = %v", got)
+	}
+
+	var inPrice int
+	for _, b := range body.Facets.Price {
+		inPrice += b.Count
+	}
+	if inPrice != 3 {
+		t.Errorf("el histograma de precio cuenta %d SUV, want 3", inPrice)
+	}
+}
+
+func compareVehicles(t *testing.T, router *gin.Engine, ids []int) (int, map[string]json.RawMessage) {
+	t.Helper()
+	payload, _ := json.Marshal(map[string][]int{"vehicleIds": ids})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/vehicles/compare", bytes.NewReader(payload)))
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("respuesta no es JSON (%d): %s", w.Code, w.Body.String())
+	}
+	return w.Code, body
+}
+
+func TestSearchVehiclesIncludesFeatures(t *testing.T) {
+	router := newTestRouter(t)
+	_, body := search(t, router, url.Values{"brand_id": {"1"}, "sort_by": {"price_asc"}})
+
+	// Las características se devuelven ordenadas, como array_agg(... ORDER BY feature)
+	want := map[int][]string{
+		1: {"Apple CarPlay", "Cámara de reversa"},
+		2: {"Apple CarPlay", "Cámara de reversa", "Quemacocos"},
+		7: {},
+	}
+	if len(body.Vehicles) != len(want) {
+		t.Fatalf("vehículos = %v, want %d", body.ids(), len(want))
+	}
+	for _, v := range body.Vehicles {
+		// Un vehículo sin características devuelve una lista vacía, no null
+		if v.Features == nil || !reflect.DeepEqual(v.Features, want[v.ID]) {
+			t.Errorf("características de %d = %#v, want %#v", v.ID, v.Features, want[v.ID])
+		}
+	}
+}
+
+func TestGetFeatures(t *testing.T) {
+	all := []models.FeatureCount{{Feature: "Apple CarPlay", Count: 4}, {Feature: "Cámara de reversa", Count: 4}, {Feature: "Quemacocos", Count: 3}}
+	tests := []struct {
+		name  string
+		query url.Values
+		want  []models.FeatureCount
+	}{
+		{"sin filtros", url.Values{}, all},
+		{"por marca", url.Values{"brand_id": {"2"}}, []models.FeatureCount{
+			{Feature: "Apple CarPlay", Count: 1}, {Feature: "Cámara de reversa", Count: 1}, {Feature: "Quemacocos", Count: 1},
+		}},
+		{"features_all restringe", url.Values{"features_all": {"Quemacocos"}}, []models.FeatureCount{
+			{Feature: "Quemacocos", Count: 3}, {Feature: "Apple CarPlay", Count: 2}, {Feature: "Cámara de reversa", Count: 2},
+		}},
+		// features_any no se aplica para que la selección múltiple siga mostrando las demás opciones
+		{"features_any se ignora", url.Values{"features_any": {"Quemacocos"}}, all},
+	}
+
+	router := newTestRouter(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/features?"+tt.query.Encode(), nil))
+			var got []models.FeatureCount
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("respuesta no es JSON (%d): %s", w.Code, w.Body.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("conteos = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareVehicles(t *testing.T) {
+	router := newTestRouter(t)
+
+	status, body := compareVehicles(t, router, []int{3, 1, 3})
+	if status != http.StatusOK {
+		t.Fatalf("status = %d", status)
+	}
+	var vehicles []models.Vehicle
+	json.Unmarshal(body["vehicles"], &vehicles)
+	if len(vehicles) != 2 || vehicles[0].ID != 3 || vehicles[1].ID != 1 {
+		t.Errorf("vehículos = %v, want [3 1] sin duplicados y en el orden pedido", vehicles)
+	}
+	if _, ok := body["comparison"]; !ok {
+		t.Error("falta comparison")
+	}
+
+	status, body = compareVehicles(t, router, []int{1, 99, 2, 98})
+	if status != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", status)
+	}
+	var missing []int
+	json.Unmarshal(body["missing_ids"], &missing)
+	if !reflect.DeepEqual(missing, []int{99, 98}) {
+		t.Errorf("missing_ids = %v, want [99 98]", missing)
+	}
+
+	if status, _ := compareVehicles(t, router, []int{1, 1}); status != http.StatusBadRequest {
+		t.Errorf("un solo vehículo: status = %d, want 400", status)
+	}
+}
+
+// TestSearchVehiclesCursorWalk recorre todas las páginas con next_cursor y regresa con
+// prev_cursor; ambos recorridos deben coincidir con la lista completa sin repetir ni omitir
+func TestSearchVehiclesCursorWalk(t *testing.T) {
+	router := newTestRouter(t)
+	for _, sortBy := range []string{"price_asc", "year_desc", "brand_asc,year_asc"} {
+		t.Run(sortBy, func(t *testing.T) {
+			base := url.Values{"sort_by": {sortBy}, "features_any": {"Apple CarPlay", "Cámara de reversa"}}
+			_, full := search(t, router, base)
+			want := full.ids()
+			if len(want) != 6 {
+				t.Fatalf("la búsqueda completa devolvió %v", want)
+			}
+
+			page := func(cursor string) searchResponse {
+				query := url.Values{"limit": {"2"}}
+				for k, v := range base {
+					query[k] = v
+				}
+				if cursor != "" {
+					query.Set("cursor", cursor)
+				}
+				status, body := search(t, router, query)
+				if status != http.StatusOK {
+					t.Fatalf("status = %d (%s)", status, body.Error)
+				}
+				if body.Total == nil || *body.Total != len(want) {
+					t.Errorf("total = %v, want %d en todas las páginas", body.Total, len(want))
+				}
+				return body
+			}
+
+			// Hacia adelante
+			var forward []int
+			var pages []searchResponse
+			body := page("")
+			if body.PrevCursor != nil {
+				t.Errorf("la primera página tiene prev_cursor %q", *body.PrevCursor)
+			}
+			for {
+				pages = append(pages, body)
+				forward = append(forward, body.ids()...)
+				if body.NextCursor == nil {
+					break
+				}
+				body = page(*body.NextCursor)
+			}
+			if !reflect.DeepEqual(forward, want) {
+				t.Fatalf("hacia adelante = %v, want %v", forward, want)
+			}
+			if len(pages) != 3 {
+				t.Fatalf("%d páginas, want 3", len(pages))
+			}
+
+			// Hacia atrás desde la última página
+			last := pages[len(pages)-1]
+			backward := last.ids()
+			for body = last; body.PrevCursor != nil; {
+				body = page(*body.PrevCursor)
+				backward = append(body.ids(), backward...)
+				if body.NextCursor == nil {
+					t.Error("una página alcanzada hacia atrás debe tener next_cursor")
+				}
+			}
+			if !reflect.DeepEqual(backward, want) {
+				t.Errorf("hacia atrás = %v, want %v", backward, want)
+			}
+			if !reflect.DeepEqual(body.ids(), pages[0].ids()) {
+				t.Errorf("la primera página hacia atrás = %v, want %v", body.ids(), pages[0].ids())
+			}
+
+			// El cursor no sirve con otros filtros
+			query := url.Values{"sort_by": {sortBy}, "cursor": {*pages[0].NextCursor}}
+			if status, _ := search(t, router, query); status != http.StatusBadRequest {
+				t.Errorf("cursor con otros filtros: status = %d, want 400", status)
+			}
+		})
+	}
+}
+
+// failingExportStore entrega la primera fila de la exportación y luego falla
+type failingExportStore struct {
+	*memory.Store
+}
+
+func (s failingExportStore) ExportVehicles(ctx context.Context, filter models.SearchFilter, fn func(models.Vehicle) error) error {
+	return s.Store.ExportVehicles(ctx, filter, func(v models.Vehicle) error {
+		if err := fn(v); err != nil {
+			return err
+		}
+		return errors.New("conexión perdida")
+	})
+}
+
+// TestExportVehiclesMidStreamError revisa que un error después de enviar los encabezados
+// corte la conexión en lugar de terminar una descarga truncada como si estuviera completa
+func TestExportVehiclesMidStreamError(t *testing.T) {
+	store := newTestStore(t)
+	h := NewVehicleHandler(failingExportStore{store}, store, nil, nil)
+	router := gin.New()
+	router.GET("/api/vehicles/export", h.ExportVehicles)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/vehicles/export?format=jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (los encabezados se envían con la primera fila)", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("leer el cuerpo: err = %v, want io.ErrUnexpectedEOF (%d bytes leídos)", err, len(body))
+	}
+}
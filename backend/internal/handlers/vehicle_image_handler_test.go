@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+func newImageTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	store := newTestStore(t)
+
+	public := NewVehicleHandler(store, store, nil, nil)
+	admin := NewAdminVehicleHandler(store)
+	router := gin.New()
+	router.GET("/api/vehicles/:id/images", public.GetVehicleImages)
+	router.POST("/api/admin/vehicles/:id/images", admin.AddVehicleImage)
+	router.PUT("/api/admin/vehicles/:id/images/order", admin.ReorderVehicleImages)
+	router.DELETE("/api/admin/vehicles/:id/images/:image_id", admin.DeleteVehicleImage)
+	return router
+}
+
+// galleryURLs lista las URLs de la galería en el orden en que se devuelven
+func galleryURLs(t *testing.T, router *gin.Engine, path string) []string {
+	t.Helper()
+	w := sendJSON(router, http.MethodGet, path, "")
+	var images []models.VehicleImage
+	if err := json.Unmarshal(w.Body.Bytes(), &images); err != nil {
+		t.Fatalf("respuesta no es JSON (%d): %s", w.Code, w.Body.String())
+	}
+	urls := []string{}
+	for _, img := range images {
+		urls = append(urls, img.ImageURL)
+	}
+	return urls
+}
+
+func TestVehicleImageGallery(t *testing.T) {
+	router := newImageTestRouter(t)
+
+	for _, body := range []string{
+		`{"image_url": "frente.jpg"}`,
+		`{"image_url": "tablero.jpg", "image_type": "interior"}`,
+		`{"image_url": "portada.jpg", "display_order": 0}`,
+	} {
+		if w := sendJSON(router, http.MethodPost, "/api/admin/vehicles/1/images", body); w.Code != http.StatusCreated {
+			t.Fatalf("alta %s: %d %s", body, w.Code, w.Body.String())
+		}
+	}
+	for _, tt := range []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{"sin URL", "/api/admin/vehicles/1/images", `{"image_type": "interior"}`, http.StatusBadRequest},
+		{"tipo inválido", "/api/admin/vehicles/1/images", `{"image_url": "x.jpg", "image_type": "techo"}`, http.StatusBadRequest},
+		{"orden negativo", "/api/admin/vehicles/1/images", `{"image_url": "x.jpg", "display_order": -1}`, http.StatusBadRequest},
+		{"vehículo inexistente", "/api/admin/vehicles/99/images", `{"image_url": "x.jpg"}`, http.StatusNotFound},
+	} {
+		if w := sendJSON(router, http.MethodPost, tt.path, tt.body); w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.status)
+		}
+	}
+
+	// Sin display_order se agrega al final; con display_order 0 empata con la primera y el
+	// empate lo resuelve el ID
+	want := []string{"frente.jpg", "portada.jpg", "tablero.jpg"}
+	if got := galleryURLs(t, router, "/api/vehicles/1/images"); !reflect.DeepEqual(got, want) {
+		t.Errorf("galería = %v, want %v", got, want)
+	}
+	if got := galleryURLs(t, router, "/api/vehicles/1/images?type=interior"); !reflect.DeepEqual(got, []string{"tablero.jpg"}) {
+		t.Errorf("galería interior = %v", got)
+	}
+	if w := sendJSON(router, http.MethodGet, "/api/vehicles/1/images?type=techo", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("tipo inválido: status = %d, want 400", w.Code)
+	}
+
+	if w := sendJSON(router, http.MethodPut, "/api/admin/vehicles/1/images/order", `{"image_ids": [3, 1]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("orden incompleto: status = %d, want 400", w.Code)
+	}
+	if w := sendJSON(router, http.MethodPut, "/api/admin/vehicles/1/images/order", `{"image_ids": [2, 3, 1]}`); w.Code != http.StatusOK {
+		t.Fatalf("reordenar: %d %s", w.Code, w.Body.String())
+	}
+	if got := galleryURLs(t, router, "/api/vehicles/1/images"); !reflect.DeepEqual(got, []string{"tablero.jpg", "portada.jpg", "frente.jpg"}) {
+		t.Errorf("galería reordenada = %v", got)
+	}
+
+	if w := sendJSON(router, http.MethodDelete, "/api/admin/vehicles/2/images/1", ""); w.Code != http.StatusNotFound {
+		t.Errorf("borrar imagen de otro vehículo: status = %d, want 404", w.Code)
+	}
+	if w := sendJSON(router, http.MethodDelete, "/api/admin/vehicles/1/images/1", ""); w.Code != http.StatusNoContent {
+		t.Errorf("borrar imagen: status = %d, want 204", w.Code)
+	}
+	if got := galleryURLs(t, router, "/api/vehicles/1/images"); !reflect.DeepEqual(got, []string{"tablero.jpg", "portada.jpg"}) {
+		t.Errorf("galería tras borrar = %v", got)
+	}
+}
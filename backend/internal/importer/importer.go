@@ -0,0 +1,267 @@
+// Package importer carga vehículos en lote desde CSV o JSON. Resuelve los catálogos por
+// nombre, crea las marcas que no existan y hace upsert por (marca, modelo, año).
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/vehiculos/backend/internal/database"
+	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/textnorm"
+)
+
+// ErrInvalidFile indica que el archivo no se pudo interpretar en su totalidad
+var ErrInvalidFile = errors.New("archivo de importación inválido")
+
+// Importer aplica registros de importación sobre el catálogo
+type Importer struct {
+	catalog database.CatalogStore
+	store   database.ImportStore
+}
+
+func New(catalog database.CatalogStore, store database.ImportStore) *Importer {
+	return &Importer{catalog: catalog, store: store}
+}
+
+// Import interpreta el archivo en el formato dado y lo importa. Las filas ilegibles se suman
+// a los errores del resultado junto con las que fallan la validación.
+func (imp *Importer) Import(ctx context.Context, format string, r io.Reader, dryRun bool) (*models.ImportResult, error) {
+	records, parseErrors, err := Parse(format, r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+
+	result, err := imp.Run(ctx, records, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Total += len(parseErrors)
+	result.Failed += len(parseErrors)
+	result.Errors = append(result.Errors, parseErrors...)
+	sort.SliceStable(result.Errors, func(i, j int) bool { return result.Errors[i].Row < result.Errors[j].Row })
+
+	return result, nil
+}
+
+// catalogIDs indexa un catálogo por nombre normalizado
+type catalogIDs map[string]int
+
+func (c catalogIDs) resolve(name string) (int, bool) {
+	id, ok := c[normalizeName(name)]
+	return id, ok
+}
+
+// Run valida e importa los registros. Las filas inválidas se reportan en el resultado sin
+// detener la importación; err sólo se devuelve ante fallas del almacenamiento. Con dryRun
+// no se escribe nada y los conteos indican lo que se haría.
+func (imp *Importer) Run(ctx context.Context, records []models.ImportRecord, dryRun bool) (*models.ImportResult, error) {
+	brands, types, fuelTypes, transmissions, err := imp.loadCatalogs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ImportResult{
+		DryRun:        dryRun,
+		Total:         len(records),
+		BrandsCreated: []string{},
+		Errors:        []models.ImportRowError{},
+	}
+
+	// seen registra las llaves ya procesadas para que un duplicado en el mismo archivo cuente
+	// como actualización también en dry_run
+	seen := make(map[string]bool)
+	newBrands := make(map[string]bool)
+
+	// Las escrituras no invalidan la caché fila por fila: se invalida una vez al terminar,
+	// también si la importación se interrumpe después de haber escrito algo
+	var written []int
+	wrote := false
+	defer func() {
+		if wrote {
+			imp.store.InvalidateImport(ctx, written)
+		}
+	}()
+
+	for _, rec := range records {
+		input, errs := buildInput(rec, types, fuelTypes, transmissions)
+
+		brandID, brandExists := brands.resolve(rec.Brand)
+		if strings.TrimSpace(rec.Brand) == "" {
+			errs["brand"] = "es obligatorio"
+		}
+		if len(errs) > 0 {
+			result.Failed++
+			result.Errors = append(result.Errors, models.ImportRowError{Row: rec.Row, Errors: errs})
+			continue
+		}
+
+		key := fmt.Sprintf("%s|%s|%d", normalizeName(rec.Brand), strings.ToLower(input.Model), input.Year)
+
+		if dryRun {
+			if !brandExists {
+				if name := normalizeName(rec.Brand); !newBrands[name] {
+					newBrands[name] = true
+					result.BrandsCreated = append(result.BrandsCreated, strings.TrimSpace(rec.Brand))
+				}
+			}
+
+			exists := seen[key]
+			if !exists && brandExists {
+				_, err := imp.store.FindVehicleID(ctx, brandID, input.Model, input.Year)
+				switch {
+				case err == nil:
+					exists = true
+				case !errors.Is(err, database.ErrVehicleNotFound):
+					return nil, fmt.Errorf("fila %d: %w", rec.Row, err)
+				}
+			}
+			seen[key] = true
+
+			if exists {
+				result.Updated++
+			} else {
+				result.Created++
+			}
+			continue
+		}
+
+		if !brandExists {
+			brand, err := imp.store.CreateBrand(ctx, strings.TrimSpace(rec.Brand))
+			if err != nil {
+				return nil, fmt.Errorf("fila %d: error al crear marca: %w", rec.Row, err)
+			}
+			wrote = true
+			brands[normalizeName(brand.Name)] = brand.ID
+			result.BrandsCreated = append(result.BrandsCreated, brand.Name)
+			brandID = brand.ID
+		}
+		input.BrandID = brandID
+
+		id, created, err := imp.store.UpsertVehicle(ctx, input)
+		if err != nil {
+			var validationErrs models.ValidationErrors
+			if errors.As(err, &validationErrs) {
+				result.Failed++
+				result.Errors = append(result.Errors, models.ImportRowError{Row: rec.Row, Errors: validationErrs})
+				continue
+			}
+			return nil, fmt.Errorf("fila %d: %w", rec.Row, err)
+		}
+		written = append(written, id)
+		wrote = true
+
+		if created {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+	}
+
+	return result, nil
+}
+
+func (imp *Importer) loadCatalogs(ctx context.Context) (brands, types, fuelTypes, transmissions catalogIDs, err error) {
+	brandList, err := imp.catalog.GetBrands(ctx)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	typeList, err := imp.catalog.GetVehicleTypes(ctx)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	fuelList, err := imp.catalog.GetFuelTypes(ctx)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	transmissionList, err := imp.catalog.GetTransmissions(ctx)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	brands = catalogIDs{}
+	for _, b := range brandList {
+		brands[normalizeName(b.Name)] = b.ID
+	}
+	types = catalogIDs{}
+	for _, t := range typeList {
+		types[normalizeName(t.Name)] = t.ID
+	}
+	fuelTypes = catalogIDs{}
+	for _, f := range fuelList {
+		fuelTypes[normalizeName(f.Name)] = f.ID
+	}
+	transmissions = catalogIDs{}
+	for _, t := range transmissionList {
+		transmissions[normalizeName(t.Name)] = t.ID
+	}
+
+	return brands, types, fuelTypes, transmissions, nil
+}
+
+// buildInput convierte el registro en VehicleInput y lo valida. Los errores de catálogo se
+// reportan con el nombre de la columna (type, fuel_type, transmission) en lugar del ID.
+func buildInput(rec models.ImportRecord, types, fuelTypes, transmissions catalogIDs) (models.VehicleInput, models.ValidationErrors) {
+	errs := models.ValidationErrors{}
+
+	resolve := func(field, name string, ids catalogIDs) int {
+		if strings.TrimSpace(name) == "" {
+			errs[field] = "es obligatorio"
+			return 0
+		}
+		id, ok := ids.resolve(name)
+		if !ok {
+			errs[field] = fmt.Sprintf("no existe: %q", name)
+		}
+		return id
+	}
+
+	input := models.VehicleInput{
+		// La marca se resuelve después (puede crearse); cualquier ID válido sirve para validar
+		BrandID:        1,
+		Model:          rec.Model,
+		Year:           rec.Year,
+		TypeID:         resolve("type", rec.Type, types),
+		Price:          rec.Price,
+		Currency:       rec.Currency,
+		FuelTypeID:     resolve("fuel_type", rec.FuelType, fuelTypes),
+		TransmissionID: resolve("transmission", rec.Transmission, transmissions),
+		Doors:          rec.Doors,
+		Seats:          rec.Seats,
+		EngineSize:     rec.EngineSize,
+		Horsepower:     rec.Horsepower,
+		Torque:         rec.Torque,
+		FuelEconomy:    rec.FuelEconomy,
+		TankCapacity:   rec.TankCapacity,
+		CargoSpace:     rec.CargoSpace,
+		ImageURL:       strings.TrimSpace(rec.ImageURL),
+		Description:    strings.TrimSpace(rec.Description),
+		Features:       rec.Features,
+		SafetyRating:   rec.SafetyRating,
+	}
+	input.Normalize()
+
+	var validationErrs models.ValidationErrors
+	if err := input.Validate(); errors.As(err, &validationErrs) {
+		for field, msg := range validationErrs {
+			switch field {
+			case "type_id", "fuel_type_id", "transmission_id":
+				// Ya reportado por nombre
+			default:
+				errs[field] = msg
+			}
+		}
+	}
+
+	return input, errs
+}
+
+// normalizeName compara nombres de catálogo sin distinguir mayúsculas, acentos ni espacios extremos
+func normalizeName(name string) string {
+	return textnorm.Fold(strings.TrimSpace(name))
+}
@@ -0,0 +1,71 @@
+package importer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/vehiculos/backend/internal/database/memory"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// countingStore registra las invalidaciones que pide el importador
+type countingStore struct {
+	*memory.Store
+	invalidations [][]int
+}
+
+func (s *countingStore) InvalidateImport(_ context.Context, vehicleIDs []int) {
+	s.invalidations = append(s.invalidations, vehicleIDs)
+}
+
+func TestRunInvalidatesOnce(t *testing.T) {
+	mem, err := memory.NewStore(memory.Seed{
+		Brands:        []models.Brand{{ID: 1, Name: "Nissan"}},
+		VehicleTypes:  []models.VehicleType{{ID: 1, Name: "Sedan"}},
+		FuelTypes:     []models.FuelType{{ID: 1, Name: "Gasolina"}},
+		Transmissions: []models.Transmission{{ID: 1, Name: "CVT"}},
+		Vehicles: []memory.SeedVehicle{{ID: 1, VehicleInput: models.VehicleInput{
+			BrandID: 1, Model: "Versa", Year: 2020, TypeID: 1, Price: 250000,
+			FuelTypeID: 1, TransmissionID: 1,
+		}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &countingStore{Store: mem}
+
+	record := func(row int, brand, model string) models.ImportRecord {
+		return models.ImportRecord{
+			Row: row, Brand: brand, Model: model, Year: 2020, Type: "Sedan", Price: 300000,
+			FuelType: "Gasolina", Transmission: "CVT",
+		}
+	}
+	records := []models.ImportRecord{
+		record(2, "Nissan", "Versa"),
+		record(3, "Kia", "Rio"),
+		record(4, "Nissan", "Sentra"),
+		{Row: 5, Brand: "Nissan", Model: "Kicks"},
+	}
+
+	if _, err := New(mem, store).Run(context.Background(), records, true); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.invalidations) != 0 {
+		t.Fatalf("dry_run invalidó la caché: %v", store.invalidations)
+	}
+
+	result, err := New(mem, store).Run(context.Background(), records, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Created != 2 || result.Updated != 1 || result.Failed != 1 {
+		t.Errorf("resultado = %+v, want 2 creados, 1 actualizado y 1 fallido", result)
+	}
+	if len(store.invalidations) != 1 {
+		t.Fatalf("%d invalidaciones, want una para todo el lote", len(store.invalidations))
+	}
+	if got, want := store.invalidations[0], []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("vehículos invalidados = %v, want %v", got, want)
+	}
+}
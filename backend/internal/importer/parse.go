@@ -0,0 +1,220 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/vehiculos/backend/internal/csvcell"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// Formatos de archivo soportados
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// requiredColumns son las columnas que todo CSV debe incluir en su encabezado
+var requiredColumns = []string{"brand", "model", "year", "type", "price", "fuel_type", "transmission"}
+
+// knownColumns son todas las columnas aceptadas; una columna desconocida suele ser un error de captura.
+// id se acepta y se ignora para poder reimportar un CSV exportado.
+var knownColumns = map[string]bool{
+	"id":    true,
+	"brand": true, "model": true, "year": true, "type": true, "price": true, "currency": true,
+	"fuel_type": true, "transmission": true, "doors": true, "seats": true, "engine_size": true,
+	"horsepower": true, "torque": true, "fuel_economy": true, "tank_capacity": true,
+	"cargo_space": true, "image_url": true, "description": true, "features": true,
+	"safety_rating": true,
+}
+
+// Parse lee los registros en el formato indicado. Los errores por fila se devuelven aparte;
+// err sólo indica que el archivo completo es ilegible.
+func Parse(format string, r io.Reader) ([]models.ImportRecord, []models.ImportRowError, error) {
+	switch format {
+	case FormatCSV:
+		return ParseCSV(r)
+	case FormatJSON:
+		return ParseJSON(r)
+	}
+	return nil, nil, fmt.Errorf("formato no soportado: %q (use csv o json)", format)
+}
+
+// FormatFromFilename deduce el formato por la extensión del archivo
+func FormatFromFilename(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".csv"):
+		return FormatCSV
+	case strings.HasSuffix(lower, ".json"):
+		return FormatJSON
+	}
+	return ""
+}
+
+// ParseCSV lee un CSV con encabezado; Row es el número de línea del archivo
+func ParseCSV(r io.Reader) ([]models.ImportRecord, []models.ImportRowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("el archivo está vacío")
+		}
+		return nil, nil, fmt.Errorf("error al leer encabezado: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !knownColumns[name] {
+			return nil, nil, fmt.Errorf("columna desconocida: %q", name)
+		}
+		columns[name] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("falta la columna obligatoria %q", name)
+		}
+	}
+
+	records := []models.ImportRecord{}
+	rowErrors := []models.ImportRowError{}
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount) {
+			rowErrors = append(rowErrors, models.ImportRowError{
+				Row:    parseErr.StartLine,
+				Errors: models.ValidationErrors{"row": "número de columnas incorrecto"},
+			})
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("error al leer CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		rec, errs := csvRecord(columns, fields)
+		rec.Row = line
+		if len(errs) > 0 {
+			rowErrors = append(rowErrors, models.ImportRowError{Row: line, Errors: errs})
+			continue
+		}
+		records = append(records, rec)
+	}
+
+	return records, rowErrors, nil
+}
+
+func csvRecord(columns map[string]int, fields []string) (models.ImportRecord, models.ValidationErrors) {
+	errs := models.ValidationErrors{}
+	get := func(name string) string {
+		if i, ok := columns[name]; ok {
+			return csvcell.Unescape(strings.TrimSpace(fields[i]))
+		}
+		return ""
+	}
+	getInt := func(name string) int {
+		value := get(name)
+		if value == "" {
+			return 0
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			errs[name] = "debe ser un número entero"
+		}
+		return n
+	}
+	getFloat := func(name string) float64 {
+		value := get(name)
+		if value == "" {
+			return 0
+		}
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			errs[name] = "debe ser numérico"
+		}
+		return f
+	}
+
+	rec := models.ImportRecord{
+		Brand:        get("brand"),
+		Model:        get("model"),
+		Year:         getInt("year"),
+		Type:         get("type"),
+		Price:        getFloat("price"),
+		Currency:     get("currency"),
+		FuelType:     get("fuel_type"),
+		Transmission: get("transmission"),
+		Doors:        getInt("doors"),
+		Seats:        getInt("seats"),
+		EngineSize:   getFloat("engine_size"),
+		Horsepower:   getInt("horsepower"),
+		Torque:       getInt("torque"),
+		FuelEconomy:  getFloat("fuel_economy"),
+		TankCapacity: getFloat("tank_capacity"),
+		CargoSpace:   getFloat("cargo_space"),
+		ImageURL:     get("image_url"),
+		Description:  get("description"),
+		SafetyRating: getFloat("safety_rating"),
+	}
+	if features := get("features"); features != "" {
+		rec.Features = strings.Split(features, csvcell.FeatureSeparator)
+	}
+
+	return rec, errs
+}
+
+// ParseJSON lee un arreglo de objetos; Row es la posición del objeto (desde 1)
+func ParseJSON(r io.Reader) ([]models.ImportRecord, []models.ImportRowError, error) {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error al leer JSON: %w", err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return nil, nil, errors.New("se esperaba un arreglo JSON de vehículos")
+	}
+
+	records := []models.ImportRecord{}
+	rowErrors := []models.ImportRowError{}
+	for row := 1; dec.More(); row++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, nil, fmt.Errorf("error al leer JSON en el elemento %d: %w", row, err)
+		}
+
+		// Cada objeto se decodifica por separado para que un tipo incorrecto sólo invalide su fila
+		var rec models.ImportRecord
+		objDec := json.NewDecoder(bytes.NewReader(raw))
+		objDec.DisallowUnknownFields()
+		if err := objDec.Decode(&rec); err != nil {
+			rowErrors = append(rowErrors, models.ImportRowError{
+				Row:    row,
+				Errors: models.ValidationErrors{"row": err.Error()},
+			})
+			continue
+		}
+		rec.Row = row
+		records = append(records, rec)
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, fmt.Errorf("error al leer JSON: %w", err)
+	}
+
+	return records, rowErrors, nil
+}
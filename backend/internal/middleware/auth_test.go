@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func newRouter(handler gin.HandlerFunc) *gin.Engine {
+	router := gin.New()
+	router.Use(handler)
+	router.GET("/admin", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name   string
+		apiKey string
+		header string
+		status int
+	}{
+		{"token correcto", "secreto", "Bearer secreto", http.StatusOK},
+		{"sin header", "secreto", "", http.StatusUnauthorized},
+		{"token incorrecto", "secreto", "Bearer otro", http.StatusUnauthorized},
+		{"prefijo del token", "secreto", "Bearer secre", http.StatusUnauthorized},
+		{"sin prefijo Bearer", "secreto", "secreto", http.StatusUnauthorized},
+		{"otro esquema", "secreto", "Basic secreto", http.StatusUnauthorized},
+		{"administración deshabilitada", "", "Bearer ", http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			newRouter(AdminAuth(tt.apiKey)).ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name        string
+		allowed     []string
+		method      string
+		origin      string
+		status      int
+		allowOrigin string
+	}{
+		{"origen permitido", []string{"http://localhost:5173"}, http.MethodGet, "http://localhost:5173", http.StatusOK, "http://localhost:5173"},
+		{"origen no permitido", []string{"http://localhost:5173"}, http.MethodGet, "http://evil.example", http.StatusOK, ""},
+		{"comodín", []string{"*"}, http.MethodGet, "http://otro.example", http.StatusOK, "*"},
+		{"preflight", []string{"http://localhost:5173"}, http.MethodOptions, "http://localhost:5173", http.StatusNoContent, "http://localhost:5173"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/admin", nil)
+			req.Header.Set("Origin", tt.origin)
+			w := httptest.NewRecorder()
+			newRouter(CORS(tt.allowed)).ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.allowOrigin)
+			}
+		})
+	}
+}
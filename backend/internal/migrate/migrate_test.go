@@ -0,0 +1,64 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/vehiculos/backend/migrations"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"010_add_index.up.sql":  {Data: []byte("CREATE INDEX ...")},
+		"002_initial.up.sql":    {Data: []byte("CREATE TABLE ...")},
+		"002_initial.down.sql":  {Data: []byte("DROP TABLE ...")},
+		"README.md":             {Data: []byte("no es una migración")},
+		"003_Mayusculas.up.sql": {Data: []byte("se ignora")},
+		"notas/004_x.up.sql":    {Data: []byte("los subdirectorios se ignoran")},
+	}
+	got, err := load(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Version != 2 || got[1].Version != 10 {
+		t.Fatalf("migraciones = %+v, want versiones 2 y 10 en orden", got)
+	}
+	if got[0].Name != "initial" || got[0].Down == "" || got[1].Down != "" {
+		t.Errorf("migraciones = %+v", got)
+	}
+}
+
+func TestLoadRejectsInconsistentFiles(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"sin script up": {
+			"001_initial.down.sql": {Data: []byte("DROP TABLE ...")},
+		},
+		"versión duplicada": {
+			"001_initial.up.sql": {Data: []byte("CREATE TABLE ...")},
+			"001_otra.up.sql":    {Data: []byte("CREATE TABLE ...")},
+		},
+	}
+	for name, fsys := range tests {
+		if _, err := load(fsys); err == nil {
+			t.Errorf("%s: load no devolvió error", name)
+		}
+	}
+}
+
+// TestEmbeddedMigrations verifica las migraciones del binario: versiones consecutivas desde 1
+// y todas reversibles
+func TestEmbeddedMigrations(t *testing.T) {
+	got, err := load(migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range got {
+		if m.Version != int64(i+1) {
+			t.Errorf("versión %d en la posición %d, want %d", m.Version, i, i+1)
+		}
+		if strings.TrimSpace(m.Down) == "" {
+			t.Errorf("%d_%s no tiene script down", m.Version, m.Name)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+// FacetCount es el número de vehículos que coinciden con una opción de filtro
+type FacetCount struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// HistogramBucket es un rango [Min, Max) con el número de vehículos que contiene
+type HistogramBucket struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+// SearchFacets agrupa los conteos por dimensión para el filtro actual
+type SearchFacets struct {
+	Brands        []FacetCount      `json:"brands"`
+	VehicleTypes  []FacetCount      `json:"vehicle_types"`
+	FuelTypes     []FacetCount      `json:"fuel_types"`
+	Transmissions []FacetCount      `json:"transmissions"`
+	Price         []HistogramBucket `json:"price"`
+	Year          []HistogramBucket `json:"year"`
+}
+
+// FeatureCount es una característica con el número de vehículos que la tienen
+type FeatureCount struct {
+	Feature string `json:"feature"`
+	Count   int    `json:"count"`
+}
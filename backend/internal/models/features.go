@@ -0,0 +1,27 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+// MaxFeatureFilters limita las características por filtro (features_all o features_any)
+const MaxFeatureFilters = 20
+
+// NormalizeFeatures deja las características de un filtro en forma canónica: sin espacios
+// sobrantes, en minúsculas, sin repetidos y ordenadas, para comparar sin distinguir
+// mayúsculas y que el mismo filtro produzca siempre la misma llave de caché
+func NormalizeFeatures(features []string) []string {
+	seen := make(map[string]bool, len(features))
+	var normalized []string
+	for _, f := range features {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" || seen[f] {
+			continue
+		}
+		seen[f] = true
+		normalized = append(normalized, f)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
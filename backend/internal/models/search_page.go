@@ -0,0 +1,27 @@
+package models
+
+// Modos de conteo del total de resultados
+const (
+	CountExact    = "exact"
+	CountEstimate = "estimate"
+	CountNone     = "none"
+)
+
+// ValidCountMode indica si mode es un modo de conteo soportado; vacío equivale a exact
+func ValidCountMode(mode string) bool {
+	switch mode {
+	case "", CountExact, CountEstimate, CountNone:
+		return true
+	}
+	return false
+}
+
+// SearchPage es una página de resultados. Total es nil con count=none y TotalEstimated
+// indica que proviene del planificador en lugar de un COUNT(*).
+type SearchPage struct {
+	Vehicles       []Vehicle `json:"vehicles"`
+	Total          *int      `json:"total"`
+	TotalEstimated bool      `json:"total_estimated,omitempty"`
+	NextCursor     string    `json:"next_cursor,omitempty"`
+	PrevCursor     string    `json:"prev_cursor,omitempty"`
+}
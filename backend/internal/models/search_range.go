@@ -0,0 +1,110 @@
+package models
+
+import "math"
+
+// RangeFilter es un atributo numérico de vehicles filtrable con <Name>_min y <Name>_max. Name
+// coincide con la columna. Los límites son enteros (IntMin/IntMax) o decimales
+// (FloatMin/FloatMax) según el campo del filtro; un límite en 0 significa que no se aplica.
+type RangeFilter struct {
+	Name     string
+	IntMin   func(f *SearchFilter) *int
+	IntMax   func(f *SearchFilter) *int
+	FloatMin func(f *SearchFilter) *float64
+	FloatMax func(f *SearchFilter) *float64
+	Value    func(v Vehicle) float64
+}
+
+func intRange(name string, min, max func(f *SearchFilter) *int, value func(v Vehicle) float64) RangeFilter {
+	return RangeFilter{Name: name, IntMin: min, IntMax: max, Value: value}
+}
+
+func floatRange(name string, min, max func(f *SearchFilter) *float64, value func(v Vehicle) float64) RangeFilter {
+	return RangeFilter{Name: name, FloatMin: min, FloatMax: max, Value: value}
+}
+
+// RangeFilters son todos los rangos numéricos de la búsqueda. price y year también son
+// dimensiones de facetas, por lo que se omiten al calcular sus propios histogramas.
+var RangeFilters = []RangeFilter{
+	floatRange("price",
+		func(f *SearchFilter) *float64 { return &f.PriceMin },
+		func(f *SearchFilter) *float64 { return &f.PriceMax },
+		func(v Vehicle) float64 { return v.Price }),
+	intRange("year",
+		func(f *SearchFilter) *int { return &f.YearMin },
+		func(f *SearchFilter) *int { return &f.YearMax },
+		func(v Vehicle) float64 { return float64(v.Year) }),
+	intRange("doors",
+		func(f *SearchFilter) *int { return &f.DoorsMin },
+		func(f *SearchFilter) *int { return &f.DoorsMax },
+		func(v Vehicle) float64 { return float64(v.Doors) }),
+	intRange("seats",
+		func(f *SearchFilter) *int { return &f.SeatsMin },
+		func(f *SearchFilter) *int { return &f.SeatsMax },
+		func(v Vehicle) float64 { return float64(v.Seats) }),
+	floatRange("fuel_economy",
+		func(f *SearchFilter) *float64 { return &f.FuelEconomyMin },
+		func(f *SearchFilter) *float64 { return &f.FuelEconomyMax },
+		func(v Vehicle) float64 { return v.FuelEconomy }),
+	intRange("horsepower",
+		func(f *SearchFilter) *int { return &f.HorsepowerMin },
+		func(f *SearchFilter) *int { return &f.HorsepowerMax },
+		func(v Vehicle) float64 { return float64(v.Horsepower) }),
+	intRange("torque",
+		func(f *SearchFilter) *int { return &f.TorqueMin },
+		func(f *SearchFilter) *int { return &f.TorqueMax },
+		func(v Vehicle) float64 { return float64(v.Torque) }),
+	floatRange("engine_size",
+		func(f *SearchFilter) *float64 { return &f.EngineSizeMin },
+		func(f *SearchFilter) *float64 { return &f.EngineSizeMax },
+		func(v Vehicle) float64 { return v.EngineSize }),
+	floatRange("tank_capacity",
+		func(f *SearchFilter) *float64 { return &f.TankCapacityMin },
+		func(f *SearchFilter) *float64 { return &f.TankCapacityMax },
+		func(v Vehicle) float64 { return v.TankCapacity }),
+	floatRange("cargo_space",
+		func(f *SearchFilter) *float64 { return &f.CargoSpaceMin },
+		func(f *SearchFilter) *float64 { return &f.CargoSpaceMax },
+		func(v Vehicle) float64 { return v.CargoSpace }),
+	floatRange("safety_rating",
+		func(f *SearchFilter) *float64 { return &f.SafetyRatingMin },
+		func(f *SearchFilter) *float64 { return &f.SafetyRatingMax },
+		func(v Vehicle) float64 { return v.SafetyRating }),
+}
+
+// Integer indica si los límites del rango son enteros
+func (r RangeFilter) Integer() bool {
+	return r.IntMin != nil
+}
+
+// Bounds devuelve los límites del rango en f; 0 indica que el límite no se aplica
+func (r RangeFilter) Bounds(f *SearchFilter) (lower, upper float64) {
+	if r.Integer() {
+		return float64(*r.IntMin(f)), float64(*r.IntMax(f))
+	}
+	return *r.FloatMin(f), *r.FloatMax(f)
+}
+
+// Matches indica si v cumple los límites del rango en f
+func (r RangeFilter) Matches(f *SearchFilter, v Vehicle) bool {
+	lower, upper := r.Bounds(f)
+	value := r.Value(v)
+	return (lower <= 0 || value >= lower) && (upper <= 0 || value <= upper)
+}
+
+// ValidateRanges verifica que los límites sean finitos y no negativos y que el mínimo no
+// supere al máximo; las claves de los errores son los nombres de los parámetros
+func (f *SearchFilter) ValidateRanges() ValidationErrors {
+	errs := ValidationErrors{}
+	for _, r := range RangeFilters {
+		lower, upper := r.Bounds(f)
+		switch {
+		case lower < 0 || math.IsNaN(lower) || math.IsInf(lower, 0):
+			errs[r.Name+"_min"] = "debe ser un número no negativo"
+		case upper < 0 || math.IsNaN(upper) || math.IsInf(upper, 0):
+			errs[r.Name+"_max"] = "debe ser un número no negativo"
+		case lower > 0 && upper > 0 && lower > upper:
+			errs[r.Name+"_min"] = "no puede ser mayor que " + r.Name + "_max"
+		}
+	}
+	return errs
+}
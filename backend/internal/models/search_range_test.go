@@ -0,0 +1,68 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRangeFiltersAreTyped(t *testing.T) {
+	for _, r := range RangeFilters {
+		intRange := r.IntMin != nil && r.IntMax != nil
+		floatRange := r.FloatMin != nil && r.FloatMax != nil
+		if intRange == floatRange {
+			t.Errorf("%s: debe tener sólo límites enteros o sólo decimales", r.Name)
+		}
+	}
+}
+
+func TestRangeFilterBoundsAndMatches(t *testing.T) {
+	byName := map[string]RangeFilter{}
+	for _, r := range RangeFilters {
+		byName[r.Name] = r
+	}
+
+	f := SearchFilter{PriceMin: 300000, PriceMax: 500000, SeatsMin: 7}
+	if lower, upper := byName["price"].Bounds(&f); lower != 300000 || upper != 500000 {
+		t.Errorf("price = %v-%v, want 300000-500000", lower, upper)
+	}
+	if lower, upper := byName["seats"].Bounds(&f); lower != 7 || upper != 0 {
+		t.Errorf("seats = %v-%v, want 7-0", lower, upper)
+	}
+
+	tests := []struct {
+		name    string
+		vehicle Vehicle
+		want    bool
+	}{
+		{"dentro del rango", Vehicle{Price: 400000, Seats: 7}, true},
+		{"precio mayor", Vehicle{Price: 600000, Seats: 7}, false},
+		{"pocos asientos", Vehicle{Price: 400000, Seats: 5}, false},
+	}
+	for _, tt := range tests {
+		matches := true
+		for _, r := range RangeFilters {
+			matches = matches && r.Matches(&f, tt.vehicle)
+		}
+		if matches != tt.want {
+			t.Errorf("%s: Matches = %v, want %v", tt.name, matches, tt.want)
+		}
+	}
+}
+
+func TestValidateRanges(t *testing.T) {
+	f := SearchFilter{PriceMin: 500000, PriceMax: 300000, YearMin: -1, EngineSizeMax: math.Inf(1)}
+	errs := f.ValidateRanges()
+	for _, param := range []string{"price_min", "year_min", "engine_size_max"} {
+		if errs[param] == "" {
+			t.Errorf("falta el error de %s: %v", param, errs)
+		}
+	}
+	if len(errs) != 3 {
+		t.Errorf("errores = %v, want 3", errs)
+	}
+
+	valid := SearchFilter{PriceMin: 300000, PriceMax: 500000, HorsepowerMin: 150}
+	if errs := valid.ValidateRanges(); len(errs) > 0 {
+		t.Errorf("rango válido rechazado: %v", errs)
+	}
+}
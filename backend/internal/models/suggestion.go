@@ -0,0 +1,22 @@
+package models
+
+// Tipos de sugerencia del autocompletado
+const (
+	SuggestionBrand   = "brand"
+	SuggestionModel   = "model"
+	SuggestionFeature = "feature"
+)
+
+// Suggestion es una opción del autocompletado: una marca, un modelo de una marca (con el rango
+// de años disponibles) o una característica, con el número de vehículos que la tienen
+type Suggestion struct {
+	Type    string `json:"type"`
+	Text    string `json:"text"` // Texto a mostrar, p. ej. "Toyota Corolla"
+	BrandID int    `json:"brand_id,omitempty"`
+	Brand   string `json:"brand,omitempty"`
+	Model   string `json:"model,omitempty"`
+	Feature string `json:"feature,omitempty"`
+	YearMin int    `json:"year_min,omitempty"`
+	YearMax int    `json:"year_max,omitempty"`
+	Count   int    `json:"count"`
+}
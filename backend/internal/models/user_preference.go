@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// PreferenceType es el tipo de preferencia que el asistente guarda por sesión
+type PreferenceType string
+
+const (
+	PreferenceBudget PreferenceType = "budget" // "350000" (máximo) o "200000-350000"
+	PreferenceUsage  PreferenceType = "usage"  // city, highway, work, family, mixed
+	PreferenceSeats  PreferenceType = "seats"  // Asientos mínimos
+	PreferenceFuel   PreferenceType = "fuel"   // Nombres o IDs de combustible separados por coma
+	PreferenceBrands PreferenceType = "brands" // Nombres o IDs de marca separados por coma
+)
+
+type UserPreference struct {
+	ID              int            `json:"id" db:"id"`
+	SessionID       string         `json:"session_id" db:"session_id"`
+	PreferenceType  PreferenceType `json:"preference_type" db:"preference_type"`
+	PreferenceValue string         `json:"preference_value" db:"preference_value"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+}
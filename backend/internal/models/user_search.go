@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+)
+
+type UserSearch struct {
+	ID                int          `json:"id" db:"id"`
+	SearchQuery       string       `json:"search_query" db:"search_query"`
+	Filters           SearchFilter `json:"filters" db:"filters"`
+	ResultsCount      int          `json:"results_count" db:"results_count"`
+	SelectedVehicleID *int         `json:"selected_vehicle_id,omitempty" db:"selected_vehicle_id"`
+	SessionID         string       `json:"session_id" db:"session_id"`
+	CreatedAt         time.Time    `json:"created_at" db:"created_at"`
+}
@@ -53,7 +53,10 @@ type Vehicle struct {
 	ImageURL       string    `json:"image_url" db:"image_url"`
 	Description    string    `json:"description" db:"description"`
 	Features       []string  `json:"features"` // Array de características
+	Images         []VehicleImage `json:"images,omitempty"` // Galería (sólo en el detalle)
+	Specs          map[string][]VehicleSpec `json:"specs,omitempty"` // Especificaciones por categoría (sólo en el detalle)
 	SafetyRating   float64   `json:"safety_rating" db:"safety_rating"` // 0-5
+	Relevance      float64   `json:"relevance,omitempty"` // Puntaje de la búsqueda de texto (sólo con q)
 	CreatedAt      time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -74,10 +77,31 @@ type SearchFilter struct {
 	YearMin        int       `json:"year_min"`
 	YearMax        int       `json:"year_max"`
 	DoorsMin       int       `json:"doors_min"`
+	DoorsMax       int       `json:"doors_max"`
 	SeatsMin       int       `json:"seats_min"`
+	SeatsMax       int       `json:"seats_max"`
 	FuelEconomyMin float64   `json:"fuel_economy_min"`
+	FuelEconomyMax float64   `json:"fuel_economy_max"`
+	HorsepowerMin  int       `json:"horsepower_min"`
+	HorsepowerMax  int       `json:"horsepower_max"`
+	TorqueMin      int       `json:"torque_min"`
+	TorqueMax      int       `json:"torque_max"`
+	EngineSizeMin  float64   `json:"engine_size_min"`
+	EngineSizeMax  float64   `json:"engine_size_max"`
+	TankCapacityMin float64  `json:"tank_capacity_min"`
+	TankCapacityMax float64  `json:"tank_capacity_max"`
+	CargoSpaceMin  float64   `json:"cargo_space_min"`
+	CargoSpaceMax  float64   `json:"cargo_space_max"`
+	SafetyRatingMin float64  `json:"safety_rating_min"`
+	SafetyRatingMax float64  `json:"safety_rating_max"`
+	Specs          []SpecFilter `json:"specs"` // Filtros sobre vehicle_specs numéricas
+	FeaturesAll    []string  `json:"features_all"` // El vehículo debe tener todas
+	FeaturesAny    []string  `json:"features_any"` // El vehículo debe tener al menos una
 	Query          string    `json:"query"` // Búsqueda de texto
+	QueryExpansions [][]string `json:"query_expansions,omitempty"` // Grupos de sinónimos; cada grupo debe coincidir con alguna de sus frases
 	SortBy         string    `json:"sort_by"` // price_asc, price_desc, year_desc, fuel_economy_desc
 	Page           int       `json:"page"`
 	Limit          int       `json:"limit"`
+	Cursor         string    `json:"cursor"` // next_cursor/prev_cursor de la página anterior; reemplaza a Page
+	Count          string    `json:"count"` // exact (por defecto), estimate o none
 }
\ No newline at end of file
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// Tipos de imagen de la galería
+const (
+	ImageTypeExterior = "exterior"
+	ImageTypeInterior = "interior"
+	ImageTypeEngine   = "engine"
+)
+
+// ValidImageType indica si t es un tipo de imagen reconocido
+func ValidImageType(t string) bool {
+	switch t {
+	case ImageTypeExterior, ImageTypeInterior, ImageTypeEngine:
+		return true
+	}
+	return false
+}
+
+type VehicleImage struct {
+	ID           int       `json:"id" db:"id"`
+	VehicleID    int       `json:"vehicle_id" db:"vehicle_id"`
+	ImageURL     string    `json:"image_url" db:"image_url"`
+	ImageType    string    `json:"image_type" db:"image_type"`
+	DisplayOrder int       `json:"display_order" db:"display_order"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,45 @@
+package models
+
+// ImportRecord es una fila de importación con los catálogos referenciados por nombre.
+// Los campos coinciden con las columnas del CSV y las llaves del JSON.
+type ImportRecord struct {
+	Row          int      `json:"-"`
+	Brand        string   `json:"brand"`
+	Model        string   `json:"model"`
+	Year         int      `json:"year"`
+	Type         string   `json:"type"`
+	Price        float64  `json:"price"`
+	Currency     string   `json:"currency"`
+	FuelType     string   `json:"fuel_type"`
+	Transmission string   `json:"transmission"`
+	Doors        int      `json:"doors"`
+	Seats        int      `json:"seats"`
+	EngineSize   float64  `json:"engine_size"`
+	Horsepower   int      `json:"horsepower"`
+	Torque       int      `json:"torque"`
+	FuelEconomy  float64  `json:"fuel_economy"`
+	TankCapacity float64  `json:"tank_capacity"`
+	CargoSpace   float64  `json:"cargo_space"`
+	ImageURL     string   `json:"image_url"`
+	Description  string   `json:"description"`
+	Features     []string `json:"features"`
+	SafetyRating float64  `json:"safety_rating"`
+}
+
+// ImportRowError describe por qué se rechazó una fila; Row es la línea del CSV o la
+// posición (desde 1) en el arreglo JSON
+type ImportRowError struct {
+	Row    int              `json:"row"`
+	Errors ValidationErrors `json:"errors"`
+}
+
+// ImportResult resume una importación; en modo dry_run los conteos indican lo que se haría
+type ImportResult struct {
+	DryRun        bool             `json:"dry_run"`
+	Total         int              `json:"total"`
+	Created       int              `json:"created"`
+	Updated       int              `json:"updated"`
+	Failed        int              `json:"failed"`
+	BrandsCreated []string         `json:"brands_created"`
+	Errors        []ImportRowError `json:"errors"`
+}
@@ -0,0 +1,202 @@
+package models
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationErrors agrupa los errores de validación por campo
+type ValidationErrors map[string]string
+
+func (e ValidationErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	msgs := make([]string, len(fields))
+	for i, field := range fields {
+		msgs[i] = field + ": " + e[field]
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// VehicleInput son los datos editables de un vehículo (alta y reemplazo completo)
+type VehicleInput struct {
+	BrandID        int      `json:"brand_id"`
+	Model          string   `json:"model"`
+	Year           int      `json:"year"`
+	TypeID         int      `json:"type_id"`
+	Price          float64  `json:"price"`
+	Currency       string   `json:"currency"`
+	FuelTypeID     int      `json:"fuel_type_id"`
+	TransmissionID int      `json:"transmission_id"`
+	Doors          int      `json:"doors"`
+	Seats          int      `json:"seats"`
+	EngineSize     float64  `json:"engine_size"`
+	Horsepower     int      `json:"horsepower"`
+	Torque         int      `json:"torque"`
+	FuelEconomy    float64  `json:"fuel_economy"`
+	TankCapacity   float64  `json:"tank_capacity"`
+	CargoSpace     float64  `json:"cargo_space"`
+	ImageURL       string   `json:"image_url"`
+	Description    string   `json:"description"`
+	Features       []string `json:"features"`
+	SafetyRating   float64  `json:"safety_rating"`
+}
+
+// Normalize limpia espacios, aplica la moneda por defecto y elimina características duplicadas
+func (in *VehicleInput) Normalize() {
+	in.Model = strings.TrimSpace(in.Model)
+	in.Currency = strings.ToUpper(strings.TrimSpace(in.Currency))
+	if in.Currency == "" {
+		in.Currency = "MXN"
+	}
+
+	seen := make(map[string]bool, len(in.Features))
+	features := make([]string, 0, len(in.Features))
+	for _, f := range in.Features {
+		f = strings.TrimSpace(f)
+		if f != "" && !seen[f] {
+			seen[f] = true
+			features = append(features, f)
+		}
+	}
+	in.Features = features
+}
+
+// Validate replica las restricciones CHECK de la tabla vehicles. Las longitudes se cuentan en
+// caracteres, como VARCHAR.
+func (in VehicleInput) Validate() error {
+	errs := ValidationErrors{}
+
+	if in.BrandID < 1 {
+		errs["brand_id"] = "es obligatorio"
+	}
+	if in.Model == "" {
+		errs["model"] = "es obligatorio"
+	} else if utf8.RuneCountInString(in.Model) > 200 {
+		errs["model"] = "máximo 200 caracteres"
+	}
+	if in.Year < 1900 || in.Year > 2030 {
+		errs["year"] = "debe estar entre 1900 y 2030"
+	}
+	if in.TypeID < 1 {
+		errs["type_id"] = "es obligatorio"
+	}
+	if in.Price < 0 {
+		errs["price"] = "no puede ser negativo"
+	}
+	if in.Currency != "MXN" && in.Currency != "USD" {
+		errs["currency"] = "debe ser MXN o USD"
+	}
+	if in.FuelTypeID < 1 {
+		errs["fuel_type_id"] = "es obligatorio"
+	}
+	if in.TransmissionID < 1 {
+		errs["transmission_id"] = "es obligatorio"
+	}
+	if in.Doors < 0 || in.Doors > 10 {
+		errs["doors"] = "debe estar entre 0 y 10"
+	}
+	// seats admite NULL (vehículos sin dato); 0 significa que no se conoce
+	if in.Seats < 0 || in.Seats > 50 {
+		errs["seats"] = "debe estar entre 1 y 50, o 0 si no se conoce"
+	}
+	if in.EngineSize < 0 || in.EngineSize >= 100 {
+		errs["engine_size"] = "debe estar entre 0 y 99.9"
+	}
+	if in.Horsepower < 0 {
+		errs["horsepower"] = "no puede ser negativo"
+	}
+	if in.Torque < 0 {
+		errs["torque"] = "no puede ser negativo"
+	}
+	if in.FuelEconomy < 0 || in.FuelEconomy >= 1000 {
+		errs["fuel_economy"] = "debe estar entre 0 y 999.9"
+	}
+	if in.TankCapacity < 0 || in.TankCapacity >= 10000 {
+		errs["tank_capacity"] = "debe estar entre 0 y 9999.9"
+	}
+	if in.CargoSpace < 0 || in.CargoSpace >= 1000000 {
+		errs["cargo_space"] = "debe estar entre 0 y 999999.9"
+	}
+	if in.SafetyRating < 0 || in.SafetyRating > 5 {
+		errs["safety_rating"] = "debe estar entre 0 y 5"
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// VehiclePatch es una actualización parcial: sólo se aplican los campos presentes
+type VehiclePatch struct {
+	BrandID        *int      `json:"brand_id"`
+	Model          *string   `json:"model"`
+	Year           *int      `json:"year"`
+	TypeID         *int      `json:"type_id"`
+	Price          *float64  `json:"price"`
+	Currency       *string   `json:"currency"`
+	FuelTypeID     *int      `json:"fuel_type_id"`
+	TransmissionID *int      `json:"transmission_id"`
+	Doors          *int      `json:"doors"`
+	Seats          *int      `json:"seats"`
+	EngineSize     *float64  `json:"engine_size"`
+	Horsepower     *int      `json:"horsepower"`
+	Torque         *int      `json:"torque"`
+	FuelEconomy    *float64  `json:"fuel_economy"`
+	TankCapacity   *float64  `json:"tank_capacity"`
+	CargoSpace     *float64  `json:"cargo_space"`
+	ImageURL       *string   `json:"image_url"`
+	Description    *string   `json:"description"`
+	Features       *[]string `json:"features"`
+	SafetyRating   *float64  `json:"safety_rating"`
+}
+
+// ApplyTo copia los campos presentes del parche sobre in
+func (p VehiclePatch) ApplyTo(in *VehicleInput) {
+	setInt(&in.BrandID, p.BrandID)
+	setString(&in.Model, p.Model)
+	setInt(&in.Year, p.Year)
+	setInt(&in.TypeID, p.TypeID)
+	setFloat(&in.Price, p.Price)
+	setString(&in.Currency, p.Currency)
+	setInt(&in.FuelTypeID, p.FuelTypeID)
+	setInt(&in.TransmissionID, p.TransmissionID)
+	setInt(&in.Doors, p.Doors)
+	setInt(&in.Seats, p.Seats)
+	setFloat(&in.EngineSize, p.EngineSize)
+	setInt(&in.Horsepower, p.Horsepower)
+	setInt(&in.Torque, p.Torque)
+	setFloat(&in.FuelEconomy, p.FuelEconomy)
+	setFloat(&in.TankCapacity, p.TankCapacity)
+	setFloat(&in.CargoSpace, p.CargoSpace)
+	setString(&in.ImageURL, p.ImageURL)
+	setString(&in.Description, p.Description)
+	setFloat(&in.SafetyRating, p.SafetyRating)
+	if p.Features != nil {
+		in.Features = *p.Features
+	}
+}
+
+func setInt(dst *int, src *int) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+func setFloat(dst *float64, src *float64) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+func setString(dst *string, src *string) {
+	if src != nil {
+		*dst = *src
+	}
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func validInput() VehicleInput {
+	return VehicleInput{
+		BrandID: 1, Model: "Corolla", Year: 2024, TypeID: 1, Price: 390000, Currency: "MXN",
+		FuelTypeID: 1, TransmissionID: 1, Seats: 5,
+	}
+}
+
+func TestVehicleInputValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(in *VehicleInput)
+		field  string // vacío si debe ser válido
+	}{
+		{"válido", func(in *VehicleInput) {}, ""},
+		// Las filas anteriores al esquema actual no tienen asientos
+		{"sin dato de asientos", func(in *VehicleInput) { in.Seats = 0 }, ""},
+		{"asientos negativos", func(in *VehicleInput) { in.Seats = -1 }, "seats"},
+		{"demasiados asientos", func(in *VehicleInput) { in.Seats = 51 }, "seats"},
+		// 200 caracteres con acento ocupan más de 200 bytes
+		{"modelo de 200 caracteres", func(in *VehicleInput) { in.Model = strings.Repeat("é", 200) }, ""},
+		{"modelo de 201 caracteres", func(in *VehicleInput) { in.Model = strings.Repeat("é", 201) }, "model"},
+		{"moneda", func(in *VehicleInput) { in.Currency = "EUR" }, "currency"},
+		{"calificación", func(in *VehicleInput) { in.SafetyRating = 5.5 }, "safety_rating"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := validInput()
+			tt.modify(&in)
+			err := in.Validate()
+			if tt.field == "" {
+				if err != nil {
+					t.Errorf("Validate = %v, want nil", err)
+				}
+				return
+			}
+			errs, ok := err.(ValidationErrors)
+			if !ok || errs[tt.field] == "" {
+				t.Errorf("Validate = %v, want error en %q", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestVehiclePatchApplyTo(t *testing.T) {
+	in := validInput()
+	in.Seats = 0
+	price := 400000.0
+	features := []string{"GPS"}
+	VehiclePatch{Price: &price, Features: &features}.ApplyTo(&in)
+
+	if in.Price != price || in.Model != "Corolla" || len(in.Features) != 1 {
+		t.Errorf("ApplyTo = %+v", in)
+	}
+	// Un parche que no toca los asientos no falla en un vehículo sin ese dato
+	if err := in.Validate(); err != nil {
+		t.Errorf("Validate tras el parche = %v", err)
+	}
+}
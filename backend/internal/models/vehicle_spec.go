@@ -0,0 +1,53 @@
+package models
+
+import (
+	"regexp"
+)
+
+// DefaultSpecCategory agrupa las especificaciones que no tienen categoría
+const DefaultSpecCategory = "general"
+
+var specNamePattern = regexp.MustCompile(`^[a-z0-9_]{1,100}$`)
+
+// ValidSpecName indica si name es un nombre de especificación válido (snake_case)
+func ValidSpecName(name string) bool {
+	return specNamePattern.MatchString(name)
+}
+
+type VehicleSpec struct {
+	ID        int    `json:"id" db:"id"`
+	VehicleID int    `json:"vehicle_id" db:"vehicle_id"`
+	Name      string `json:"name" db:"spec_name"`
+	Value     string `json:"value" db:"spec_value"`
+	Unit      string `json:"unit,omitempty" db:"spec_unit"`
+	Category  string `json:"category" db:"spec_category"` // performance, dimensions, safety, etc.
+}
+
+// SpecFilter filtra por el valor numérico de una especificación, p. ej. ground_clearance_mm >= 200
+type SpecFilter struct {
+	Name     string  `json:"name"`
+	Operator string  `json:"operator"` // =, >, >=, <, <=
+	Value    float64 `json:"value"`
+}
+
+// ValidSpecOperator indica si op es un operador de comparación soportado
+func ValidSpecOperator(op string) bool {
+	switch op {
+	case "=", ">", ">=", "<", "<=":
+		return true
+	}
+	return false
+}
+
+// GroupSpecsByCategory agrupa las especificaciones por spec_category
+func GroupSpecsByCategory(specs []VehicleSpec) map[string][]VehicleSpec {
+	groups := make(map[string][]VehicleSpec)
+	for _, s := range specs {
+		category := s.Category
+		if category == "" {
+			category = DefaultSpecCategory
+		}
+		groups[category] = append(groups[category], s)
+	}
+	return groups
+}
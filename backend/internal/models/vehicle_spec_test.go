@@ -0,0 +1,32 @@
+package models
+
+import "testing"
+
+func TestValidSpecName(t *testing.T) {
+	valid := []string{"ground_clearance_mm", "0_100_kmh_s", "x"}
+	invalid := []string{"", "Ground_Clearance", "altura libre", "peso-kg", string(make([]byte, 101))}
+	for _, name := range valid {
+		if !ValidSpecName(name) {
+			t.Errorf("ValidSpecName(%q) = false, want true", name)
+		}
+	}
+	for _, name := range invalid {
+		if ValidSpecName(name) {
+			t.Errorf("ValidSpecName(%q) = true, want false", name)
+		}
+	}
+}
+
+func TestGroupSpecsByCategory(t *testing.T) {
+	groups := GroupSpecsByCategory([]VehicleSpec{
+		{Name: "ground_clearance_mm", Category: "dimensions"},
+		{Name: "warranty_years"},
+		{Name: "wheelbase_mm", Category: "dimensions"},
+	})
+	if len(groups) != 2 || len(groups["dimensions"]) != 2 || len(groups[DefaultSpecCategory]) != 1 {
+		t.Errorf("grupos = %+v", groups)
+	}
+	if groups["dimensions"][0].Name != "ground_clearance_mm" {
+		t.Errorf("el grupo debe conservar el orden recibido: %+v", groups["dimensions"])
+	}
+}
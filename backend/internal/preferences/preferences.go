@@ -0,0 +1,203 @@
+package preferences
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/textnorm"
+)
+
+// Usos reconocidos para la preferencia usage
+const (
+	UsageCity    = "city"
+	UsageHighway = "highway"
+	UsageWork    = "work"
+	UsageFamily  = "family"
+	UsageMixed   = "mixed"
+)
+
+// Catalog contiene los catálogos necesarios para traducir nombres a IDs
+type Catalog struct {
+	Brands       []models.Brand
+	VehicleTypes []models.VehicleType
+	FuelTypes    []models.FuelType
+}
+
+// Validate comprueba que el valor tenga el formato esperado para su tipo; las marcas y los
+// combustibles deben existir en el catálogo, por nombre o por ID
+func Validate(prefType models.PreferenceType, value string, catalog Catalog) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fmt.Errorf("el valor de la preferencia es obligatorio")
+	}
+
+	switch prefType {
+	case models.PreferenceBudget:
+		if _, _, err := ParseBudget(value); err != nil {
+			return err
+		}
+	case models.PreferenceUsage:
+		switch strings.ToLower(value) {
+		case UsageCity, UsageHighway, UsageWork, UsageFamily, UsageMixed:
+		default:
+			return fmt.Errorf("uso no reconocido: %s", value)
+		}
+	case models.PreferenceSeats:
+		seats, err := strconv.Atoi(value)
+		if err != nil || seats < 1 || seats > 50 {
+			return fmt.Errorf("el número de asientos debe estar entre 1 y 50")
+		}
+	case models.PreferenceFuel, models.PreferenceBrands:
+		items := splitList(value)
+		if len(items) == 0 {
+			return fmt.Errorf("se requiere al menos un valor")
+		}
+		for _, item := range items {
+			if prefType == models.PreferenceFuel {
+				if _, ok := resolveFuelType(item, catalog.FuelTypes); !ok {
+					return fmt.Errorf("combustible no reconocido: %s", item)
+				}
+			} else if _, ok := resolveBrand(item, catalog.Brands); !ok {
+				return fmt.Errorf("marca no reconocida: %s", item)
+			}
+		}
+	default:
+		return fmt.Errorf("tipo de preferencia no reconocido: %s", prefType)
+	}
+
+	return nil
+}
+
+// ParseBudget interpreta "max" o "min-max" y devuelve el rango de precio
+func ParseBudget(value string) (float64, float64, error) {
+	parts := strings.SplitN(strings.ReplaceAll(value, " ", ""), "-", 2)
+	if len(parts) == 1 {
+		priceMax, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil || priceMax <= 0 {
+			return 0, 0, fmt.Errorf("presupuesto inválido: %s", value)
+		}
+		return 0, priceMax, nil
+	}
+
+	priceMin, errMin := strconv.ParseFloat(parts[0], 64)
+	priceMax, errMax := strconv.ParseFloat(parts[1], 64)
+	if errMin != nil || errMax != nil || priceMin < 0 || priceMax <= 0 || priceMin > priceMax {
+		return 0, 0, fmt.Errorf("presupuesto inválido: %s", value)
+	}
+	return priceMin, priceMax, nil
+}
+
+// DeriveSearchFilter construye un SearchFilter a partir de las preferencias de la sesión.
+// Si un tipo de preferencia se guardó varias veces gana la más reciente.
+func DeriveSearchFilter(prefs []models.UserPreference, catalog Catalog) models.SearchFilter {
+	latest := make(map[models.PreferenceType]models.UserPreference)
+	for _, p := range prefs {
+		if current, ok := latest[p.PreferenceType]; !ok || !p.CreatedAt.Before(current.CreatedAt) {
+			latest[p.PreferenceType] = p
+		}
+	}
+
+	var filter models.SearchFilter
+
+	if p, ok := latest[models.PreferenceBudget]; ok {
+		if priceMin, priceMax, err := ParseBudget(p.PreferenceValue); err == nil {
+			filter.PriceMin, filter.PriceMax = priceMin, priceMax
+		}
+	}
+
+	if p, ok := latest[models.PreferenceSeats]; ok {
+		if seats, err := strconv.Atoi(strings.TrimSpace(p.PreferenceValue)); err == nil {
+			filter.SeatsMin = seats
+		}
+	}
+
+	if p, ok := latest[models.PreferenceFuel]; ok {
+		for _, item := range splitList(p.PreferenceValue) {
+			if id, ok := resolveFuelType(item, catalog.FuelTypes); ok {
+				filter.FuelTypeID = append(filter.FuelTypeID, id)
+			}
+		}
+	}
+
+	if p, ok := latest[models.PreferenceBrands]; ok {
+		for _, item := range splitList(p.PreferenceValue) {
+			if id, ok := resolveBrand(item, catalog.Brands); ok {
+				filter.BrandID = append(filter.BrandID, id)
+			}
+		}
+	}
+
+	if p, ok := latest[models.PreferenceUsage]; ok {
+		applyUsage(&filter, strings.ToLower(strings.TrimSpace(p.PreferenceValue)), catalog)
+	}
+
+	return filter
+}
+
+// applyUsage traduce el uso declarado a restricciones razonables del catálogo. La ciudad y
+// la carretera sólo ordenan por rendimiento: los eléctricos no registran fuel_economy y un
+// mínimo los excluiría justo cuando más convienen.
+func applyUsage(filter *models.SearchFilter, usage string, catalog Catalog) {
+	switch usage {
+	case UsageCity, UsageHighway:
+		filter.SortBy = "fuel_economy_desc"
+	case UsageWork:
+		filter.TypeID = append(filter.TypeID, resolveTypes(catalog.VehicleTypes, "Pickup", "Van")...)
+	case UsageFamily:
+		if filter.SeatsMin < 5 {
+			filter.SeatsMin = 5
+		}
+		filter.TypeID = append(filter.TypeID, resolveTypes(catalog.VehicleTypes, "SUV", "Minivan", "Crossover", "Wagon")...)
+	}
+}
+
+func resolveTypes(types []models.VehicleType, names ...string) []int {
+	var ids []int
+	for _, name := range names {
+		for _, t := range types {
+			if sameName(t.Name, name) {
+				ids = append(ids, t.ID)
+			}
+		}
+	}
+	return ids
+}
+
+func resolveBrand(value string, brands []models.Brand) (int, bool) {
+	id, err := strconv.Atoi(value)
+	isID := err == nil
+	for _, b := range brands {
+		if (isID && b.ID == id) || sameName(b.Name, value) {
+			return b.ID, true
+		}
+	}
+	return 0, false
+}
+
+func resolveFuelType(value string, fuelTypes []models.FuelType) (int, bool) {
+	id, err := strconv.Atoi(value)
+	isID := err == nil
+	for _, f := range fuelTypes {
+		if (isID && f.ID == id) || sameName(f.Name, value) {
+			return f.ID, true
+		}
+	}
+	return 0, false
+}
+
+// sameName compara nombres de catálogo sin distinguir mayúsculas ni acentos
+func sameName(a, b string) bool {
+	return textnorm.Fold(a) == textnorm.Fold(b)
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,114 @@
+package preferences
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+var testCatalog = Catalog{
+	Brands:       []models.Brand{{ID: 1, Name: "Toyota"}, {ID: 2, Name: "Mazda"}},
+	VehicleTypes: []models.VehicleType{{ID: 1, Name: "Sedan"}, {ID: 2, Name: "SUV"}, {ID: 3, Name: "Pickup"}, {ID: 4, Name: "Minivan"}},
+	FuelTypes:    []models.FuelType{{ID: 1, Name: "Gasolina"}, {ID: 3, Name: "Eléctrico"}},
+}
+
+func pref(prefType models.PreferenceType, value string, minute int) models.UserPreference {
+	return models.UserPreference{
+		PreferenceType:  prefType,
+		PreferenceValue: value,
+		CreatedAt:       time.Date(2024, 1, 1, 12, minute, 0, 0, time.UTC),
+	}
+}
+
+func TestDeriveSearchFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		prefs []models.UserPreference
+		want  models.SearchFilter
+	}{
+		{"sin preferencias", nil, models.SearchFilter{}},
+		{
+			"presupuesto máximo",
+			[]models.UserPreference{pref(models.PreferenceBudget, "350000", 0)},
+			models.SearchFilter{PriceMax: 350000},
+		},
+		{
+			"gana el presupuesto más reciente",
+			[]models.UserPreference{
+				pref(models.PreferenceBudget, "200000-300000", 5),
+				pref(models.PreferenceBudget, "400000", 1),
+			},
+			models.SearchFilter{PriceMin: 200000, PriceMax: 300000},
+		},
+		{
+			"marcas y combustibles por nombre o ID",
+			[]models.UserPreference{
+				pref(models.PreferenceBrands, "toyota, 2, Ford", 0),
+				pref(models.PreferenceFuel, "electrico", 0),
+			},
+			models.SearchFilter{BrandID: []int{1, 2}, FuelTypeID: []int{3}},
+		},
+		{
+			// Un mínimo de rendimiento dejaría fuera a los eléctricos, que no lo registran
+			"ciudad sólo ordena por rendimiento",
+			[]models.UserPreference{pref(models.PreferenceUsage, "City", 0)},
+			models.SearchFilter{SortBy: "fuel_economy_desc"},
+		},
+		{
+			"trabajo limita a pickups",
+			[]models.UserPreference{pref(models.PreferenceUsage, "work", 0)},
+			models.SearchFilter{TypeID: []int{3}},
+		},
+		{
+			"familia respeta más asientos",
+			[]models.UserPreference{
+				pref(models.PreferenceSeats, "7", 0),
+				pref(models.PreferenceUsage, "family", 0),
+			},
+			models.SearchFilter{SeatsMin: 7, TypeID: []int{2, 4}},
+		},
+		{
+			"familia exige cinco asientos",
+			[]models.UserPreference{pref(models.PreferenceUsage, "family", 0)},
+			models.SearchFilter{SeatsMin: 5, TypeID: []int{2, 4}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DeriveSearchFilter(tt.prefs, testCatalog)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DeriveSearchFilter = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		prefType models.PreferenceType
+		value    string
+		valid    bool
+	}{
+		{models.PreferenceBudget, "350000", true},
+		{models.PreferenceBudget, "400000-300000", false},
+		{models.PreferenceUsage, "Highway", true},
+		{models.PreferenceUsage, "offroad", false},
+		{models.PreferenceSeats, "0", false},
+		{models.PreferenceBrands, " , ", false},
+		{models.PreferenceBrands, "mazda, 1", true},
+		{models.PreferenceBrands, "Toyota, Ford", false},
+		{models.PreferenceFuel, "ELECTRICO", true},
+		{models.PreferenceFuel, "diésel", false},
+		{"color", "rojo", false},
+	}
+
+	for _, tt := range tests {
+		err := Validate(tt.prefType, tt.value, testCatalog)
+		if (err == nil) != tt.valid {
+			t.Errorf("Validate(%s, %q) = %v, valid = %v", tt.prefType, tt.value, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Package recommend ordena los vehículos del catálogo según el perfil de necesidades del
+// usuario (presupuesto, pasajeros, uso y prioridades) con un puntaje multicriterio que se
+// devuelve desglosado para que cada recomendación sea explicable.
+package recommend
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/preferences"
+)
+
+// Límites del número de recomendaciones por consulta
+const (
+	DefaultLimit = 10
+	MaxLimit     = 50
+)
+
+// MaxWeight es el peso máximo que se puede dar a un criterio en priorities
+const MaxWeight = 10
+
+// Criterios del puntaje
+const (
+	CriterionPrice       = "price"
+	CriterionFuelEconomy = "fuel_economy"
+	CriterionSafety      = "safety"
+	CriterionSpace       = "space"
+	CriterionPerformance = "performance"
+)
+
+// defaultWeights son los pesos sin uso ni prioridades declarados
+var defaultWeights = map[string]float64{
+	CriterionPrice:       3,
+	CriterionFuelEconomy: 2,
+	CriterionSafety:      2,
+	CriterionSpace:       1,
+	CriterionPerformance: 1,
+}
+
+// usageWeights es lo que cada uso declarado suma a los pesos por defecto
+var usageWeights = map[string]map[string]float64{
+	preferences.UsageCity:    {CriterionFuelEconomy: 2, CriterionPrice: 1},
+	preferences.UsageHighway: {CriterionFuelEconomy: 1, CriterionPerformance: 1, CriterionSafety: 1},
+	preferences.UsageWork:    {CriterionSpace: 2, CriterionPerformance: 2},
+	preferences.UsageFamily:  {CriterionSafety: 2, CriterionSpace: 2},
+	preferences.UsageMixed:   {},
+}
+
+// Budget es el rango de precio aceptable; Max 0 significa sin límite
+type Budget struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// Profile describe las necesidades del usuario. El presupuesto y los pasajeros descartan
+// vehículos; el uso y las prioridades sólo cambian el peso de cada criterio.
+type Profile struct {
+	Budget     Budget             `json:"budget"`
+	Passengers int                `json:"passengers"`
+	Usage      []string           `json:"usage"`      // city, highway, work, family, mixed
+	Priorities map[string]float64 `json:"priorities"` // Peso de 0 a MaxWeight por criterio; reemplaza al derivado del uso
+}
+
+// Normalize deja los usos en minúsculas, sin repetir, y las prioridades con llaves en minúsculas
+func (p *Profile) Normalize() {
+	seen := make(map[string]bool, len(p.Usage))
+	usage := []string{}
+	for _, u := range p.Usage {
+		u = strings.ToLower(strings.TrimSpace(u))
+		if u != "" && !seen[u] {
+			seen[u] = true
+			usage = append(usage, u)
+		}
+	}
+	p.Usage = usage
+
+	if len(p.Priorities) > 0 {
+		priorities := make(map[string]float64, len(p.Priorities))
+		for name, weight := range p.Priorities {
+			priorities[strings.ToLower(strings.TrimSpace(name))] = weight
+		}
+		p.Priorities = priorities
+	}
+}
+
+// Validate comprueba el perfil y reporta los errores por campo
+func (p Profile) Validate() error {
+	errs := models.ValidationErrors{}
+
+	if p.Budget.Min < 0 {
+		errs["budget.min"] = "no puede ser negativo"
+	}
+	if p.Budget.Max < 0 {
+		errs["budget.max"] = "no puede ser negativo"
+	} else if p.Budget.Max > 0 && p.Budget.Min > p.Budget.Max {
+		errs["budget"] = "min no puede ser mayor que max"
+	}
+	if p.Passengers < 0 || p.Passengers > 50 {
+		errs["passengers"] = "debe estar entre 0 y 50"
+	}
+	for _, u := range p.Usage {
+		if _, ok := usageWeights[u]; !ok {
+			errs["usage"] = fmt.Sprintf("uso no reconocido: %s (use city, highway, work, family o mixed)", u)
+			break
+		}
+	}
+	for _, name := range sortedKeys(p.Priorities) {
+		if _, ok := defaultWeights[name]; !ok {
+			errs["priorities."+name] = "criterio no reconocido"
+		} else if w := p.Priorities[name]; w < 0 || w > MaxWeight {
+			errs["priorities."+name] = fmt.Sprintf("debe estar entre 0 y %d", MaxWeight)
+		}
+	}
+	if len(errs) == 0 && p.totalWeight() == 0 {
+		errs["priorities"] = "al menos un criterio debe tener peso mayor que 0"
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Weights es la proporción (0-1) de cada criterio en el puntaje: los pesos por defecto, más lo
+// que suma cada uso, reemplazados por las prioridades explícitas
+func (p Profile) Weights() map[string]float64 {
+	raw := p.rawWeights()
+	total := p.totalWeight()
+
+	weights := make(map[string]float64, len(raw))
+	for name, w := range raw {
+		if total > 0 {
+			weights[name] = w / total
+		}
+	}
+	return weights
+}
+
+func (p Profile) rawWeights() map[string]float64 {
+	weights := make(map[string]float64, len(defaultWeights))
+	for name, w := range defaultWeights {
+		weights[name] = w
+	}
+	for _, u := range p.Usage {
+		for name, extra := range usageWeights[u] {
+			weights[name] += extra
+		}
+	}
+	for name, w := range p.Priorities {
+		if _, ok := weights[name]; ok {
+			weights[name] = w
+		}
+	}
+	return weights
+}
+
+func (p Profile) totalWeight() float64 {
+	var total float64
+	for _, w := range p.rawWeights() {
+		total += w
+	}
+	return total
+}
+
+// fits indica si el vehículo cumple el presupuesto y el número de pasajeros. Un vehículo sin
+// dato de asientos no se descarta por ellos.
+func (p Profile) fits(v models.Vehicle) bool {
+	if v.Price < p.Budget.Min || (p.Budget.Max > 0 && v.Price > p.Budget.Max) {
+		return false
+	}
+	return v.Seats == 0 || v.Seats >= p.Passengers
+}
+
+func sortedKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,237 @@
+package recommend
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/scoring"
+)
+
+// CriterionScore es el aporte de un criterio al puntaje de un vehículo. Score es el valor del
+// vehículo en el criterio (0-1), Weight la proporción del criterio en el perfil y
+// Contribution su producto; la suma de los aportes es el puntaje total.
+type CriterionScore struct {
+	Criterion    string  `json:"criterion"`
+	Weight       float64 `json:"weight"`
+	Score        float64 `json:"score"`
+	Contribution float64 `json:"contribution"`
+	Detail       string  `json:"detail"`
+}
+
+// Recommendation es un vehículo recomendado con su puntaje (0-1) y el desglose por criterio,
+// del que más aporta al que menos
+type Recommendation struct {
+	Vehicle   models.Vehicle   `json:"vehicle"`
+	Score     float64          `json:"score"`
+	Breakdown []CriterionScore `json:"breakdown"`
+}
+
+// criterion calcula el valor de un vehículo en un criterio y el texto que lo explica
+type criterion struct {
+	name   string
+	score  func(v models.Vehicle, p Profile, r scoring.Ranges) float64
+	detail func(v models.Vehicle, p Profile) string
+}
+
+// neutralScore es el valor de un criterio sin dato: ni premia ni castiga al vehículo. Salvo el
+// precio, las columnas son opcionales y un 0 significa que no hay dato.
+const neutralScore = 0.5
+
+var criteria = []criterion{
+	{
+		// Dentro del presupuesto el más barato vale 1 y el del tope 0.5; sin tope se compara
+		// con el rango de precios del catálogo
+		name: CriterionPrice,
+		score: func(v models.Vehicle, p Profile, r scoring.Ranges) float64 {
+			if p.Budget.Max > 0 {
+				if p.Budget.Max == p.Budget.Min {
+					return 1
+				}
+				return 1 - 0.5*(v.Price-p.Budget.Min)/(p.Budget.Max-p.Budget.Min)
+			}
+			return 1 - r.Price.Normalize(v.Price)
+		},
+		detail: func(v models.Vehicle, p Profile) string {
+			if p.Budget.Max > 0 {
+				return fmt.Sprintf("%s, %d%% del presupuesto", formatPrice(v.Price), int(math.Round(v.Price/p.Budget.Max*100)))
+			}
+			return formatPrice(v.Price)
+		},
+	},
+	{
+		// Un eléctrico no consume combustible; sin dato de rendimiento se asigna un valor neutro
+		name: CriterionFuelEconomy,
+		score: func(v models.Vehicle, _ Profile, r scoring.Ranges) float64 {
+			switch {
+			case v.FuelEconomy > 0:
+				return r.FuelEconomy.Normalize(v.FuelEconomy)
+			case isElectric(v):
+				return 1
+			}
+			return neutralScore
+		},
+		detail: func(v models.Vehicle, _ Profile) string {
+			switch {
+			case v.FuelEconomy > 0:
+				return fmt.Sprintf("%.1f km/l", v.FuelEconomy)
+			case isElectric(v):
+				return "Eléctrico, sin consumo de combustible"
+			}
+			return "Sin dato de rendimiento"
+		},
+	},
+	{
+		name: CriterionSafety,
+		score: func(v models.Vehicle, _ Profile, _ scoring.Ranges) float64 {
+			if v.SafetyRating <= 0 {
+				return neutralScore
+			}
+			return math.Min(v.SafetyRating/5, 1)
+		},
+		detail: func(v models.Vehicle, _ Profile) string {
+			if v.SafetyRating <= 0 {
+				return "Sin dato de seguridad"
+			}
+			return fmt.Sprintf("Seguridad %.1f/5", v.SafetyRating)
+		},
+	},
+	{
+		// Promedia los asientos y la carga conocidos
+		name: CriterionSpace,
+		score: func(v models.Vehicle, _ Profile, r scoring.Ranges) float64 {
+			var sum float64
+			var known int
+			if v.Seats > 0 {
+				sum += r.Seats.Normalize(float64(v.Seats))
+				known++
+			}
+			if v.CargoSpace > 0 {
+				sum += r.CargoSpace.Normalize(v.CargoSpace)
+				known++
+			}
+			if known == 0 {
+				return neutralScore
+			}
+			return sum / float64(known)
+		},
+		detail: func(v models.Vehicle, _ Profile) string {
+			if v.Seats <= 0 && v.CargoSpace <= 0 {
+				return "Sin dato de espacio"
+			}
+			return formatKnown(float64(v.Seats), "%.0f asientos", "asientos sin dato") + ", " +
+				formatKnown(v.CargoSpace, "%.0f L de carga", "carga sin dato")
+		},
+	},
+	{
+		// Promedia la potencia y el torque conocidos
+		name: CriterionPerformance,
+		score: func(v models.Vehicle, _ Profile, r scoring.Ranges) float64 {
+			var sum float64
+			var known int
+			if v.Horsepower > 0 {
+				sum += r.Horsepower.Normalize(float64(v.Horsepower))
+				known++
+			}
+			if v.Torque > 0 {
+				sum += r.Torque.Normalize(float64(v.Torque))
+				known++
+			}
+			if known == 0 {
+				return neutralScore
+			}
+			return sum / float64(known)
+		},
+		detail: func(v models.Vehicle, _ Profile) string {
+			if v.Horsepower <= 0 && v.Torque <= 0 {
+				return "Sin dato de desempeño"
+			}
+			return formatKnown(float64(v.Horsepower), "%.0f hp", "potencia sin dato") + ", " +
+				formatKnown(float64(v.Torque), "%.0f Nm", "torque sin dato")
+		},
+	},
+}
+
+// Recommend puntúa los vehículos del catálogo que cumplen el presupuesto y los pasajeros del
+// perfil y devuelve los limit mejores. Los criterios numéricos se normalizan con los rangos
+// de todo el catálogo, de modo que el puntaje de un vehículo no depende de los demás
+// candidatos. También devuelve cuántos vehículos cumplieron el perfil.
+func Recommend(profile Profile, catalog *scoring.Catalog, limit int) ([]Recommendation, int) {
+	weights := profile.Weights()
+
+	recommendations := []Recommendation{}
+	for _, v := range catalog.Vehicles {
+		if profile.fits(v) {
+			recommendations = append(recommendations, score(v, profile, weights, catalog.Ranges))
+		}
+	}
+	candidates := len(recommendations)
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		a, b := recommendations[i], recommendations[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Vehicle.Price != b.Vehicle.Price {
+			return a.Vehicle.Price < b.Vehicle.Price
+		}
+		return a.Vehicle.ID < b.Vehicle.ID
+	})
+
+	if limit > 0 && len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations, candidates
+}
+
+func score(v models.Vehicle, p Profile, weights map[string]float64, r scoring.Ranges) Recommendation {
+	rec := Recommendation{Vehicle: v, Breakdown: make([]CriterionScore, 0, len(criteria))}
+
+	var total float64
+	for _, c := range criteria {
+		value := c.score(v, p, r)
+		contribution := weights[c.name] * value
+		total += contribution
+		rec.Breakdown = append(rec.Breakdown, CriterionScore{
+			Criterion:    c.name,
+			Weight:       scoring.Round(weights[c.name]),
+			Score:        scoring.Round(value),
+			Contribution: scoring.Round(contribution),
+			Detail:       c.detail(v, p),
+		})
+	}
+	rec.Score = scoring.Round(total)
+
+	sort.SliceStable(rec.Breakdown, func(i, j int) bool {
+		return rec.Breakdown[i].Contribution > rec.Breakdown[j].Contribution
+	})
+	return rec
+}
+
+// formatKnown da formato a x o devuelve missing si no hay dato
+func formatKnown(x float64, format, missing string) string {
+	if x <= 0 {
+		return missing
+	}
+	return fmt.Sprintf(format, x)
+}
+
+func isElectric(v models.Vehicle) bool {
+	return v.FuelType != nil && strings.EqualFold(v.FuelType.Name, "Eléctrico")
+}
+
+// formatPrice da formato de moneda con separador de miles, p. ej. $389,900
+func formatPrice(price float64) string {
+	digits := strconv.FormatInt(int64(math.Round(price)), 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return "$" + b.String()
+}
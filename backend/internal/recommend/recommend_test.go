@@ -0,0 +1,244 @@
+package recommend
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/scoring"
+)
+
+// testCatalog tiene un compacto económico, una camioneta grande y potente y un eléctrico
+func testCatalog() *scoring.Catalog {
+	return scoring.NewCatalog([]models.Vehicle{
+		{ID: 1, Model: "Compacto", Price: 300000, FuelEconomy: 20, SafetyRating: 3, CargoSpace: 300,
+			Horsepower: 120, Torque: 150, Seats: 5},
+		{ID: 2, Model: "Camioneta", Price: 700000, FuelEconomy: 10, SafetyRating: 5, CargoSpace: 600,
+			Horsepower: 300, Torque: 400, Seats: 7},
+		{ID: 3, Model: "Eléctrico", Price: 500000, FuelType: &models.FuelType{Name: "Eléctrico"}, SafetyRating: 4,
+			CargoSpace: 450, Horsepower: 200, Torque: 300, Seats: 5},
+	})
+}
+
+func recommendationIDs(recs []Recommendation) []int {
+	ids := []int{}
+	for _, r := range recs {
+		ids = append(ids, r.Vehicle.ID)
+	}
+	return ids
+}
+
+func TestWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile Profile
+		want    map[string]float64
+	}{
+		{
+			name:    "por defecto",
+			profile: Profile{},
+			want:    map[string]float64{"price": 3.0 / 9, "fuel_economy": 2.0 / 9, "safety": 2.0 / 9, "space": 1.0 / 9, "performance": 1.0 / 9},
+		},
+		{
+			name:    "uso familiar suma seguridad y espacio",
+			profile: Profile{Usage: []string{"family"}},
+			want:    map[string]float64{"price": 3.0 / 13, "fuel_economy": 2.0 / 13, "safety": 4.0 / 13, "space": 3.0 / 13, "performance": 1.0 / 13},
+		},
+		{
+			name:    "las prioridades reemplazan al uso",
+			profile: Profile{Usage: []string{"family"}, Priorities: map[string]float64{"safety": 0, "performance": 10}},
+			want:    map[string]float64{"price": 3.0 / 18, "fuel_economy": 2.0 / 18, "safety": 0, "space": 3.0 / 18, "performance": 10.0 / 18},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.profile.Weights()
+			if len(got) != len(tt.want) {
+				t.Fatalf("Weights = %v, want %v", got, tt.want)
+			}
+			for name, want := range tt.want {
+				if math.Abs(got[name]-want) > 1e-9 {
+					t.Errorf("peso de %s = %v, want %v", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRecommendFollowsPriorities(t *testing.T) {
+	only := func(criterion string) Profile {
+		priorities := map[string]float64{"price": 0, "fuel_economy": 0, "safety": 0, "space": 0, "performance": 0}
+		priorities[criterion] = 10
+		return Profile{Priorities: priorities}
+	}
+	tests := []struct {
+		name    string
+		profile Profile
+		want    []int
+	}{
+		{"sólo precio", only(CriterionPrice), []int{1, 3, 2}},
+		{"sólo desempeño", only(CriterionPerformance), []int{2, 3, 1}},
+		{"sólo espacio", only(CriterionSpace), []int{2, 3, 1}},
+		// El eléctrico vale lo mismo que el más eficiente; el empate lo gana el más barato
+		{"sólo rendimiento", only(CriterionFuelEconomy), []int{1, 3, 2}},
+		{"sólo seguridad", only(CriterionSafety), []int{2, 3, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recs, candidates := Recommend(tt.profile, testCatalog(), 10)
+			if got := recommendationIDs(recs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("orden = %v, want %v", got, tt.want)
+			}
+			if candidates != 3 {
+				t.Errorf("candidates = %d, want 3", candidates)
+			}
+		})
+	}
+}
+
+func TestRecommendBreakdown(t *testing.T) {
+	recs, _ := Recommend(Profile{}, testCatalog(), 10)
+	var compact Recommendation
+	for _, r := range recs {
+		if r.Vehicle.ID == 1 {
+			compact = r
+		}
+	}
+
+	want := []CriterionScore{
+		{Criterion: "price", Weight: 0.333, Score: 1, Contribution: 0.333, Detail: "$300,000"},
+		{Criterion: "fuel_economy", Weight: 0.222, Score: 1, Contribution: 0.222, Detail: "20.0 km/l"},
+		{Criterion: "safety", Weight: 0.222, Score: 0.6, Contribution: 0.133, Detail: "Seguridad 3.0/5"},
+		{Criterion: "space", Weight: 0.111, Score: 0, Contribution: 0, Detail: "5 asientos, 300 L de carga"},
+		{Criterion: "performance", Weight: 0.111, Score: 0, Contribution: 0, Detail: "120 hp, 150 Nm"},
+	}
+	if !reflect.DeepEqual(compact.Breakdown, want) {
+		t.Errorf("desglose =\n  %+v\nwant\n  %+v", compact.Breakdown, want)
+	}
+	if compact.Score != scoring.Round(6.2/9) {
+		t.Errorf("puntaje = %v, want %v", compact.Score, scoring.Round(6.2/9))
+	}
+
+	// En todos los vehículos los aportes suman el puntaje, salvo el redondeo
+	for _, r := range recs {
+		var sum float64
+		for _, c := range r.Breakdown {
+			sum += c.Contribution
+		}
+		if math.Abs(sum-r.Score) > 0.003 {
+			t.Errorf("vehículo %d: aportes suman %v, puntaje %v", r.Vehicle.ID, sum, r.Score)
+		}
+	}
+}
+
+func TestRecommendElectricFuelEconomy(t *testing.T) {
+	recs, _ := Recommend(Profile{}, testCatalog(), 10)
+	for _, r := range recs {
+		if r.Vehicle.ID != 3 {
+			continue
+		}
+		for _, c := range r.Breakdown {
+			if c.Criterion == CriterionFuelEconomy && (c.Score != 1 || c.Detail != "Eléctrico, sin consumo de combustible") {
+				t.Errorf("rendimiento del eléctrico = %+v", c)
+			}
+		}
+	}
+}
+
+func TestRecommendBudgetAndPassengers(t *testing.T) {
+	recs, candidates := Recommend(Profile{Budget: Budget{Max: 550000}}, testCatalog(), 10)
+	// Dentro del presupuesto la ventaja de precio se reduce a la mitad y gana el eléctrico
+	if got := recommendationIDs(recs); candidates != 2 || !reflect.DeepEqual(got, []int{3, 1}) {
+		t.Fatalf("con presupuesto: %v (%d candidatos), want [3 1]", got, candidates)
+	}
+	var price CriterionScore
+	for _, c := range recs[1].Breakdown {
+		if c.Criterion == CriterionPrice {
+			price = c
+		}
+	}
+	if price.Criterion != CriterionPrice || price.Score != scoring.Round(1-0.5*300000.0/550000) || price.Detail != "$300,000, 55% del presupuesto" {
+		t.Errorf("precio dentro del presupuesto = %+v", price)
+	}
+
+	recs, candidates = Recommend(Profile{Passengers: 6}, testCatalog(), 10)
+	if got := recommendationIDs(recs); candidates != 1 || !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("con 6 pasajeros: %v, want [2]", got)
+	}
+
+	if recs, candidates := Recommend(Profile{}, testCatalog(), 1); len(recs) != 1 || candidates != 3 {
+		t.Errorf("limit 1: %d recomendaciones de %d candidatos", len(recs), candidates)
+	}
+}
+
+func TestRecommendMissingData(t *testing.T) {
+	catalog := testCatalog()
+	catalog.Vehicles = append(catalog.Vehicles, models.Vehicle{ID: 4, Model: "Sin datos", Price: 400000})
+	catalog.Ranges = scoring.RangesOf(catalog.Vehicles)
+
+	recs, candidates := Recommend(Profile{Passengers: 4}, catalog, 10)
+	if candidates != 4 {
+		t.Fatalf("candidates = %d, want 4: sin dato de asientos no se descarta", candidates)
+	}
+
+	var bare Recommendation
+	for _, r := range recs {
+		if r.Vehicle.ID == 4 {
+			bare = r
+		}
+	}
+	want := map[string]CriterionScore{
+		CriterionFuelEconomy: {Score: neutralScore, Detail: "Sin dato de rendimiento"},
+		CriterionSafety:      {Score: neutralScore, Detail: "Sin dato de seguridad"},
+		CriterionSpace:       {Score: neutralScore, Detail: "Sin dato de espacio"},
+		CriterionPerformance: {Score: neutralScore, Detail: "Sin dato de desempeño"},
+	}
+	for _, c := range bare.Breakdown {
+		if w, ok := want[c.Criterion]; ok && (c.Score != w.Score || c.Detail != w.Detail) {
+			t.Errorf("%s = %+v, want puntaje %v y detalle %q", c.Criterion, c, w.Score, w.Detail)
+		}
+	}
+
+	partial := models.Vehicle{Seats: 5, Horsepower: 300}
+	for _, c := range criteria {
+		switch c.name {
+		case CriterionSpace:
+			if got := c.detail(partial, Profile{}); got != "5 asientos, carga sin dato" {
+				t.Errorf("detalle de espacio = %q", got)
+			}
+			if got := c.score(models.Vehicle{Seats: 7}, Profile{}, catalog.Ranges); got != 1 {
+				t.Errorf("espacio sólo con asientos = %v, want 1", got)
+			}
+		case CriterionPerformance:
+			if got := c.score(partial, Profile{}, catalog.Ranges); got != 1 {
+				t.Errorf("desempeño sólo con potencia = %v, want 1", got)
+			}
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		profile Profile
+		field   string
+	}{
+		"presupuesto invertido": {Profile{Budget: Budget{Min: 500000, Max: 300000}}, "budget"},
+		"uso desconocido":       {Profile{Usage: []string{"carreras"}}, "usage"},
+		"criterio desconocido":  {Profile{Priorities: map[string]float64{"color": 1}}, "priorities.color"},
+		"peso fuera de rango":   {Profile{Priorities: map[string]float64{"price": 11}}, "priorities.price"},
+		"todos los pesos en 0": {Profile{Priorities: map[string]float64{
+			"price": 0, "fuel_economy": 0, "safety": 0, "space": 0, "performance": 0,
+		}}, "priorities"},
+	}
+	for name, tt := range tests {
+		err := tt.profile.Validate()
+		errs, ok := err.(models.ValidationErrors)
+		if !ok || errs[tt.field] == "" {
+			t.Errorf("%s: Validate = %v, want error en %q", name, err, tt.field)
+		}
+	}
+	if err := (Profile{Usage: []string{"city"}, Passengers: 4}).Validate(); err != nil {
+		t.Errorf("perfil válido rechazado: %v", err)
+	}
+}
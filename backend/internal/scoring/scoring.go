@@ -0,0 +1,100 @@
+// Package scoring guarda el catálogo junto con los rangos de sus atributos numéricos, con los
+// que los vehículos similares y las recomendaciones normalizan sus puntajes. Los rangos se
+// calculan sobre todo el catálogo para que el puntaje de un vehículo no dependa de los demás
+// candidatos de una consulta.
+package scoring
+
+import (
+	"math"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// Range es el mínimo y el máximo de un atributo; Set indica si se agregó algún valor
+type Range struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+	Set bool    `json:"set"`
+}
+
+// Add amplía el rango para incluir x
+func (r *Range) Add(x float64) {
+	if !r.Set || x < r.Min {
+		r.Min = x
+	}
+	if !r.Set || x > r.Max {
+		r.Max = x
+	}
+	r.Set = true
+}
+
+// Normalize lleva x a 0-1 dentro del rango; si todos los valores son iguales devuelve 1
+func (r Range) Normalize(x float64) float64 {
+	if r.Max == r.Min {
+		return 1
+	}
+	return clamp((x - r.Min) / (r.Max - r.Min))
+}
+
+// Similarity es 1 menos la distancia entre a y b normalizada por el rango, acotada a 0-1; si
+// todos los valores son iguales devuelve 1
+func (r Range) Similarity(a, b float64) float64 {
+	if r.Max == r.Min {
+		return 1
+	}
+	return clamp(1 - math.Abs(a-b)/(r.Max-r.Min))
+}
+
+// Ranges son los rangos de los atributos numéricos del catálogo. Salvo el precio, las columnas
+// son opcionales y un 0 significa que no hay dato (o, en el rendimiento, que es eléctrico);
+// esos vehículos se omiten para que no estiren el rango hasta 0.
+type Ranges struct {
+	Price       Range `json:"price"`
+	Horsepower  Range `json:"horsepower"`
+	Torque      Range `json:"torque"`
+	Seats       Range `json:"seats"`
+	FuelEconomy Range `json:"fuel_economy"`
+	CargoSpace  Range `json:"cargo_space"`
+}
+
+// RangesOf calcula los rangos de los vehículos
+func RangesOf(vehicles []models.Vehicle) Ranges {
+	var r Ranges
+	for _, v := range vehicles {
+		r.Price.Add(v.Price)
+		r.Horsepower.addKnown(float64(v.Horsepower))
+		r.Torque.addKnown(float64(v.Torque))
+		r.Seats.addKnown(float64(v.Seats))
+		r.FuelEconomy.addKnown(v.FuelEconomy)
+		r.CargoSpace.addKnown(v.CargoSpace)
+	}
+	return r
+}
+
+// addKnown amplía el rango sólo si x es un dato (mayor que 0)
+func (r *Range) addKnown(x float64) {
+	if x > 0 {
+		r.Add(x)
+	}
+}
+
+// Catalog son todos los vehículos con sus rangos; los stores lo cachean hasta que cambia
+// algún vehículo
+type Catalog struct {
+	Vehicles []models.Vehicle `json:"vehicles"`
+	Ranges   Ranges           `json:"ranges"`
+}
+
+// NewCatalog arma el catálogo y calcula sus rangos
+func NewCatalog(vehicles []models.Vehicle) *Catalog {
+	return &Catalog{Vehicles: vehicles, Ranges: RangesOf(vehicles)}
+}
+
+// Round redondea un puntaje a tres decimales para responderlo
+func Round(x float64) float64 {
+	return math.Round(x*1000) / 1000
+}
+
+func clamp(x float64) float64 {
+	return math.Min(math.Max(x, 0), 1)
+}
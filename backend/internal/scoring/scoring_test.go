@@ -0,0 +1,64 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+func TestRange(t *testing.T) {
+	var r Range
+	for _, x := range []float64{20, 10, 30} {
+		r.Add(x)
+	}
+	if r.Min != 10 || r.Max != 30 || !r.Set {
+		t.Fatalf("rango = %+v, want 10-30", r)
+	}
+
+	normalize := map[float64]float64{10: 0, 20: 0.5, 30: 1, 0: 0, 40: 1}
+	for x, want := range normalize {
+		if got := r.Normalize(x); got != want {
+			t.Errorf("Normalize(%v) = %v, want %v", x, got, want)
+		}
+	}
+
+	similarity := []struct{ a, b, want float64 }{
+		{20, 20, 1},
+		{10, 30, 0},
+		{15, 20, 0.75},
+		{0, 30, 0}, // fuera del rango no baja de 0
+	}
+	for _, tt := range similarity {
+		if got := r.Similarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("Similarity(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	flat := Range{Min: 5, Max: 5, Set: true}
+	if flat.Normalize(5) != 1 || flat.Similarity(5, 7) != 1 {
+		t.Error("un rango sin amplitud debe dar 1")
+	}
+}
+
+func TestRangesOfSkipsMissingValues(t *testing.T) {
+	r := RangesOf([]models.Vehicle{
+		{Price: 300000, Seats: 5, FuelEconomy: 15, Horsepower: 150, Torque: 200, CargoSpace: 400},
+		{Price: 900000, Seats: 7, FuelEconomy: 0}, // eléctrico sin más datos
+		{Price: 500000, Seats: 0, FuelEconomy: 20, Horsepower: 250, Torque: 300, CargoSpace: 500},
+	})
+	if r.Price.Min != 300000 || r.Price.Max != 900000 {
+		t.Errorf("precio = %+v", r.Price)
+	}
+	if r.Seats.Min != 5 || r.Seats.Max != 7 {
+		t.Errorf("asientos = %+v", r.Seats)
+	}
+	if r.FuelEconomy.Min != 15 || r.FuelEconomy.Max != 20 {
+		t.Errorf("rendimiento = %+v, want 15-20 sin el eléctrico", r.FuelEconomy)
+	}
+	if r.Horsepower.Min != 150 || r.Torque.Min != 200 || r.CargoSpace.Min != 400 {
+		t.Errorf("potencia = %+v, torque = %+v, cajuela = %+v, no deben bajar a 0", r.Horsepower, r.Torque, r.CargoSpace)
+	}
+	if empty := RangesOf([]models.Vehicle{{Price: 1}}); empty.Seats.Set {
+		t.Errorf("asientos sin datos = %+v, want sin valores", empty.Seats)
+	}
+}
@@ -0,0 +1,70 @@
+package similar
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// ErrInvalidConstraint indica una restricción desconocida en ?constraints=
+var ErrInvalidConstraint = errors.New("restricción de similares inválida")
+
+// Constraint limita los candidatos respecto al vehículo de referencia
+type Constraint string
+
+const (
+	Cheaper      Constraint = "cheaper"        // Precio menor
+	SameType     Constraint = "same_type"      // Mismo tipo de vehículo
+	SameBrand    Constraint = "same_brand"     // Misma marca
+	OtherBrand   Constraint = "other_brand"    // Otra marca
+	SameFuelType Constraint = "same_fuel_type" // Mismo combustible
+)
+
+// constraints indica, para cada restricción, si el candidato la cumple respecto a target
+var constraints = map[Constraint]func(target, candidate models.Vehicle) bool{
+	Cheaper:      func(t, c models.Vehicle) bool { return c.Price < t.Price },
+	SameType:     func(t, c models.Vehicle) bool { return c.TypeID == t.TypeID },
+	SameBrand:    func(t, c models.Vehicle) bool { return c.BrandID == t.BrandID },
+	OtherBrand:   func(t, c models.Vehicle) bool { return c.BrandID != t.BrandID },
+	SameFuelType: func(t, c models.Vehicle) bool { return c.FuelTypeID == t.FuelTypeID },
+}
+
+// ParseConstraints interpreta los valores de ?constraints=, separados por comas o repetidos.
+// same_brand y other_brand se excluyen entre sí.
+func ParseConstraints(values []string) ([]Constraint, error) {
+	parsed := []Constraint{}
+	seen := make(map[Constraint]bool)
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			c := Constraint(strings.TrimSpace(name))
+			if c == "" || seen[c] {
+				continue
+			}
+			if _, ok := constraints[c]; !ok {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidConstraint, c)
+			}
+			seen[c] = true
+			parsed = append(parsed, c)
+		}
+	}
+	if seen[SameBrand] && seen[OtherBrand] {
+		return nil, fmt.Errorf("%w: same_brand y other_brand se excluyen entre sí", ErrInvalidConstraint)
+	}
+	return parsed, nil
+}
+
+// Allows indica si candidate es un candidato válido para target: otro vehículo que cumple
+// todas las restricciones
+func Allows(target, candidate models.Vehicle, cs []Constraint) bool {
+	if candidate.ID == target.ID {
+		return false
+	}
+	for _, c := range cs {
+		if !constraints[c](target, candidate) {
+			return false
+		}
+	}
+	return true
+}
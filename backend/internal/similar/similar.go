@@ -0,0 +1,262 @@
+// Package similar ordena los vehículos del catálogo por su parecido con uno dado, para
+// sugerir alternativas en la página de detalle.
+package similar
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/scoring"
+)
+
+// Límites del número de vehículos similares por consulta
+const (
+	DefaultLimit = 6
+	MaxLimit     = 20
+)
+
+// ReasonThreshold es la similitud mínima de un atributo para mencionarlo como razón; para
+// las características basta featureReasonThreshold porque el índice de Jaccard es más estricto
+const (
+	ReasonThreshold        = 0.75
+	featureReasonThreshold = 0.3
+)
+
+// maxSharedFeatures es cuántas características compartidas se nombran en la razón
+const maxSharedFeatures = 3
+
+// Reason explica cuánto se parece el vehículo en un atributo (1 = igual)
+type Reason struct {
+	Attribute  string  `json:"attribute"`
+	Similarity float64 `json:"similarity"`
+	Message    string  `json:"message"`
+}
+
+// Match es un vehículo similar con su puntaje (0-1) y las razones del parecido, de la de más
+// peso a la de menos
+type Match struct {
+	Vehicle models.Vehicle `json:"vehicle"`
+	Score   float64        `json:"score"`
+	Reasons []Reason       `json:"reasons"`
+}
+
+// dimension compara un atributo de dos vehículos. similarity recibe los rangos del catálogo
+// para normalizar la distancia; applies, si existe, indica si el atributo se puede comparar.
+// Un 0 en una columna opcional es falta de dato: la potencia y los asientos sólo se comparan
+// si ambos vehículos los tienen.
+type dimension struct {
+	name       string
+	weight     float64
+	threshold  float64
+	applies    func(target, candidate models.Vehicle) bool
+	similarity func(target, candidate models.Vehicle, r scoring.Ranges) float64
+	message    func(target, candidate models.Vehicle) string
+}
+
+var dimensions = []dimension{
+	{
+		name: "price", weight: 0.30, threshold: ReasonThreshold, similarity: priceSimilarity,
+		message: func(t, c models.Vehicle) string {
+			diff := percentDiff(c.Price, t.Price)
+			switch {
+			case c.Price == t.Price:
+				return "Mismo precio"
+			case diff < 0:
+				return fmt.Sprintf("Precio similar (%d%% más barato)", -diff)
+			case diff > 0:
+				return fmt.Sprintf("Precio similar (%d%% más caro)", diff)
+			}
+			return "Precio similar"
+		},
+	},
+	{
+		name: "type", weight: 0.20, threshold: ReasonThreshold,
+		similarity: func(t, c models.Vehicle, _ scoring.Ranges) float64 {
+			if t.TypeID == c.TypeID {
+				return 1
+			}
+			return 0
+		},
+		message: func(t, _ models.Vehicle) string {
+			if t.Type != nil {
+				return fmt.Sprintf("Mismo tipo (%s)", t.Type.Name)
+			}
+			return "Mismo tipo"
+		},
+	},
+	{
+		name: "horsepower", weight: 0.15, threshold: ReasonThreshold,
+		applies: func(t, c models.Vehicle) bool {
+			return t.Horsepower > 0 && c.Horsepower > 0
+		},
+		similarity: func(t, c models.Vehicle, r scoring.Ranges) float64 {
+			return r.Horsepower.Similarity(float64(t.Horsepower), float64(c.Horsepower))
+		},
+		message: func(t, c models.Vehicle) string {
+			if t.Horsepower == c.Horsepower {
+				return fmt.Sprintf("Misma potencia (%d hp)", t.Horsepower)
+			}
+			return fmt.Sprintf("Potencia similar (%d vs %d hp)", c.Horsepower, t.Horsepower)
+		},
+	},
+	{
+		name: "features", weight: 0.15, threshold: featureReasonThreshold, similarity: featureSimilarity,
+		message: func(t, c models.Vehicle) string {
+			shared := sharedFeatures(t, c)
+			names := shared
+			if len(names) > maxSharedFeatures {
+				names = append(append([]string(nil), names[:maxSharedFeatures]...), "…")
+			}
+			return fmt.Sprintf("Comparte %d de %d características: %s", len(shared), len(t.Features), strings.Join(names, ", "))
+		},
+	},
+	{
+		name: "seats", weight: 0.10, threshold: ReasonThreshold,
+		applies: func(t, c models.Vehicle) bool {
+			return t.Seats > 0 && c.Seats > 0
+		},
+		similarity: func(t, c models.Vehicle, r scoring.Ranges) float64 {
+			return r.Seats.Similarity(float64(t.Seats), float64(c.Seats))
+		},
+		message: func(t, c models.Vehicle) string {
+			if t.Seats == c.Seats {
+				return fmt.Sprintf("Mismo número de asientos (%d)", t.Seats)
+			}
+			return fmt.Sprintf("Asientos similares (%d vs %d)", c.Seats, t.Seats)
+		},
+	},
+	{
+		// Dos vehículos sin rendimiento (eléctricos o sin dato) no se comparan en este atributo
+		name: "fuel_economy", weight: 0.10, threshold: ReasonThreshold,
+		applies: func(t, c models.Vehicle) bool {
+			return t.FuelEconomy > 0 || c.FuelEconomy > 0
+		},
+		similarity: func(t, c models.Vehicle, r scoring.Ranges) float64 {
+			return r.FuelEconomy.Similarity(t.FuelEconomy, c.FuelEconomy)
+		},
+		message: func(t, c models.Vehicle) string {
+			return fmt.Sprintf("Rendimiento similar (%.1f vs %.1f km/l)", c.FuelEconomy, t.FuelEconomy)
+		},
+	},
+}
+
+// Rank puntúa contra target los vehículos del catálogo que cumplen las restricciones y
+// devuelve los limit más parecidos. Los atributos numéricos se normalizan con los rangos de
+// todo el catálogo, para que el puntaje no dependa de las restricciones; el precio se compara
+// en proporción y las características con el índice de Jaccard.
+func Rank(target models.Vehicle, catalog *scoring.Catalog, cs []Constraint, limit int) []Match {
+	matches := []Match{}
+	for _, c := range catalog.Vehicles {
+		if Allows(target, c, cs) {
+			matches = append(matches, score(target, c, catalog.Ranges))
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Vehicle.ID < matches[j].Vehicle.ID
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// score pondera las dimensiones que aplican; el puntaje se divide entre la suma de sus pesos
+// para que omitir una dimensión no castigue al candidato
+func score(target, candidate models.Vehicle, ranges scoring.Ranges) Match {
+	type weighted struct {
+		reason       Reason
+		contribution float64
+	}
+
+	var total, weights float64
+	var reasons []weighted
+	for _, d := range dimensions {
+		if d.applies != nil && !d.applies(target, candidate) {
+			continue
+		}
+		s := d.similarity(target, candidate, ranges)
+		total += d.weight * s
+		weights += d.weight
+		if s >= d.threshold {
+			reasons = append(reasons, weighted{
+				reason:       Reason{Attribute: d.name, Similarity: scoring.Round(s), Message: d.message(target, candidate)},
+				contribution: d.weight * s,
+			})
+		}
+	}
+
+	sort.SliceStable(reasons, func(i, j int) bool {
+		return reasons[i].contribution > reasons[j].contribution
+	})
+	match := Match{Vehicle: candidate, Score: scoring.Round(total / weights), Reasons: make([]Reason, len(reasons))}
+	for i, r := range reasons {
+		match.Reasons[i] = r.reason
+	}
+	return match
+}
+
+// priceSimilarity compara en proporción al mayor de los dos precios: una diferencia de
+// $100,000 pesa más entre compactos que entre vehículos de lujo
+func priceSimilarity(t, c models.Vehicle, _ scoring.Ranges) float64 {
+	higher := math.Max(t.Price, c.Price)
+	if higher <= 0 {
+		return 1
+	}
+	return 1 - math.Abs(t.Price-c.Price)/higher
+}
+
+// featureSimilarity es el índice de Jaccard de las características, sin distinguir mayúsculas.
+// Dos vehículos sin características no se consideran parecidos por ello.
+func featureSimilarity(t, c models.Vehicle, _ scoring.Ranges) float64 {
+	a, b := featureSet(t), featureSet(c)
+	union := len(a)
+	shared := 0
+	for f := range b {
+		if a[f] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// sharedFeatures lista, con el texto de target, las características que también tiene candidate
+func sharedFeatures(target, candidate models.Vehicle) []string {
+	other := featureSet(candidate)
+	var shared []string
+	for _, f := range target.Features {
+		if other[strings.ToLower(f)] {
+			shared = append(shared, f)
+		}
+	}
+	sort.Strings(shared)
+	return shared
+}
+
+func featureSet(v models.Vehicle) map[string]bool {
+	set := make(map[string]bool, len(v.Features))
+	for _, f := range v.Features {
+		set[strings.ToLower(f)] = true
+	}
+	return set
+}
+
+// percentDiff es la diferencia porcentual redondeada de value respecto a base
+func percentDiff(value, base float64) int {
+	if base == 0 {
+		return 0
+	}
+	return int(math.Round((value - base) / base * 100))
+}
@@ -0,0 +1,183 @@
+package similar
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/scoring"
+)
+
+func TestFeatureSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want float64
+	}{
+		{"iguales", []string{"GPS", "Quemacocos"}, []string{"GPS", "Quemacocos"}, 1},
+		{"sin distinguir mayúsculas", []string{"Apple CarPlay"}, []string{"apple carplay"}, 1},
+		{"una de tres", []string{"GPS", "Quemacocos"}, []string{"GPS", "Cámara"}, 1.0 / 3},
+		{"sin compartir", []string{"GPS"}, []string{"Cámara"}, 0},
+		{"uno sin características", []string{"GPS"}, nil, 0},
+		{"ninguno con características", nil, nil, 0},
+	}
+	for _, tt := range tests {
+		got := featureSimilarity(models.Vehicle{Features: tt.a}, models.Vehicle{Features: tt.b}, scoring.Ranges{})
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: Jaccard = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSharedFeatures(t *testing.T) {
+	target := models.Vehicle{Features: []string{"Quemacocos", "GPS", "Cámara"}}
+	candidate := models.Vehicle{Features: []string{"gps", "quemacocos", "Asientos de piel"}}
+	if got, want := sharedFeatures(target, candidate), []string{"GPS", "Quemacocos"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sharedFeatures = %q, want %q", got, want)
+	}
+}
+
+// testCatalog tiene un SUV de referencia y candidatos de distintos tipos y precios
+func testCatalog() (models.Vehicle, *scoring.Catalog) {
+	suv := &models.VehicleType{ID: 2, Name: "SUV"}
+	target := models.Vehicle{ID: 1, BrandID: 1, TypeID: 2, Type: suv, FuelTypeID: 1, Price: 500000,
+		Horsepower: 180, Seats: 5, FuelEconomy: 14, Features: []string{"GPS", "Quemacocos", "Cámara"}}
+	vehicles := []models.Vehicle{
+		target,
+		{ID: 2, BrandID: 2, TypeID: 2, FuelTypeID: 1, Price: 520000, Horsepower: 185, Seats: 5, FuelEconomy: 14.5,
+			Features: []string{"GPS", "Quemacocos", "Cámara"}},
+		{ID: 3, BrandID: 1, TypeID: 2, FuelTypeID: 1, Price: 420000, Horsepower: 150, Seats: 5, FuelEconomy: 16,
+			Features: []string{"GPS"}},
+		{ID: 4, BrandID: 3, TypeID: 1, FuelTypeID: 1, Price: 350000, Horsepower: 150, Seats: 5, FuelEconomy: 17,
+			Features: []string{"GPS", "Cámara"}},
+		{ID: 5, BrandID: 3, TypeID: 3, FuelTypeID: 1, Price: 900000, Horsepower: 400, Seats: 7, FuelEconomy: 8},
+	}
+	return target, scoring.NewCatalog(vehicles)
+}
+
+func matchIDs(matches []Match) []int {
+	ids := []int{}
+	for _, m := range matches {
+		ids = append(ids, m.Vehicle.ID)
+	}
+	return ids
+}
+
+func TestRankConstraints(t *testing.T) {
+	target, catalog := testCatalog()
+	tests := []struct {
+		name        string
+		constraints []Constraint
+		want        []int
+	}{
+		{"sin restricciones excluye al propio vehículo", nil, []int{2, 3, 4, 5}},
+		{"más baratos", []Constraint{Cheaper}, []int{3, 4}},
+		{"mismo tipo", []Constraint{SameType}, []int{2, 3}},
+		{"más baratos del mismo tipo", []Constraint{Cheaper, SameType}, []int{3}},
+		{"otra marca del mismo tipo", []Constraint{SameType, OtherBrand}, []int{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchIDs(Rank(target, catalog, tt.constraints, 10)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rank = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := Rank(target, catalog, nil, 2); len(got) != 2 {
+		t.Errorf("Rank con limit 2 devolvió %d vehículos", len(got))
+	}
+}
+
+func TestRankReasons(t *testing.T) {
+	target, catalog := testCatalog()
+	best := Rank(target, catalog, nil, 1)[0]
+	if best.Vehicle.ID != 2 {
+		t.Fatalf("el más parecido es %d, want 2", best.Vehicle.ID)
+	}
+	if best.Score <= 0.9 || best.Score > 1 {
+		t.Errorf("puntaje = %v, want cercano a 1", best.Score)
+	}
+
+	reasons := make(map[string]Reason)
+	for i, r := range best.Reasons {
+		reasons[r.Attribute] = r
+		if i > 0 && dimensionWeight(r.Attribute) > dimensionWeight(best.Reasons[i-1].Attribute) {
+			t.Errorf("razones fuera de orden: %v", best.Reasons)
+		}
+	}
+	if r := reasons["features"]; r.Similarity != 1 || r.Message != "Comparte 3 de 3 características: Cámara, GPS, Quemacocos" {
+		t.Errorf("razón de características = %+v", r)
+	}
+	if r := reasons["type"]; r.Message != "Mismo tipo (SUV)" {
+		t.Errorf("razón de tipo = %+v", r)
+	}
+	if r := reasons["price"]; r.Message != "Precio similar (4% más caro)" {
+		t.Errorf("razón de precio = %+v", r)
+	}
+}
+
+func TestRankSkipsFuelEconomyWithoutData(t *testing.T) {
+	ev := func(id int, price float64) models.Vehicle {
+		return models.Vehicle{ID: id, TypeID: 2, Price: price, Horsepower: 200, Seats: 5}
+	}
+	catalog := scoring.NewCatalog([]models.Vehicle{
+		ev(1, 800000), ev(2, 800000),
+		{ID: 3, TypeID: 1, Price: 300000, Horsepower: 120, Seats: 5, FuelEconomy: 18},
+	})
+
+	match := Rank(catalog.Vehicles[0], catalog, []Constraint{SameType}, 1)[0]
+	for _, r := range match.Reasons {
+		if r.Attribute == "fuel_economy" {
+			t.Errorf("dos eléctricos no deben compararse por rendimiento: %+v", r)
+		}
+	}
+	// Precio, tipo, potencia y asientos idénticos; sin características no hay parecido en ellas
+	if want := scoring.Round(0.75 / 0.9); match.Score != want {
+		t.Errorf("puntaje = %v, want %v (sin el peso del rendimiento)", match.Score, want)
+	}
+}
+
+func TestRankSkipsUnknownHorsepowerAndSeats(t *testing.T) {
+	bare := func(id int) models.Vehicle {
+		return models.Vehicle{ID: id, TypeID: 2, Price: 500000}
+	}
+	catalog := scoring.NewCatalog([]models.Vehicle{
+		bare(1), bare(2),
+		{ID: 3, TypeID: 1, Price: 300000, Horsepower: 120, Seats: 5},
+	})
+
+	match := Rank(catalog.Vehicles[0], catalog, []Constraint{SameType}, 1)[0]
+	for _, r := range match.Reasons {
+		if r.Attribute == "horsepower" || r.Attribute == "seats" {
+			t.Errorf("sin dato no debe haber razón: %+v", r)
+		}
+	}
+	// Sólo cuentan precio, tipo y características
+	if want := scoring.Round(0.5 / 0.65); match.Score != want {
+		t.Errorf("puntaje = %v, want %v", match.Score, want)
+	}
+}
+
+func TestParseConstraints(t *testing.T) {
+	got, err := ParseConstraints([]string{"cheaper, same_type", "cheaper"})
+	if err != nil || !reflect.DeepEqual(got, []Constraint{Cheaper, SameType}) {
+		t.Errorf("ParseConstraints = %v, %v", got, err)
+	}
+	for _, values := range [][]string{{"mas_barato"}, {"same_brand,other_brand"}} {
+		if _, err := ParseConstraints(values); !errors.Is(err, ErrInvalidConstraint) {
+			t.Errorf("ParseConstraints(%q) = %v, want ErrInvalidConstraint", values, err)
+		}
+	}
+}
+
+func dimensionWeight(name string) float64 {
+	for _, d := range dimensions {
+		if d.name == name {
+			return d.weight
+		}
+	}
+	return 0
+}
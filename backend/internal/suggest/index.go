@@ -0,0 +1,165 @@
+// Package suggest implementa el autocompletado de la caja de búsqueda con un trie en memoria
+// de marcas, modelos y características que se reconstruye cuando cambia el catálogo.
+package suggest
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/textnorm"
+)
+
+// MaxLimit es el máximo de sugerencias por consulta; cada nodo del trie guarda ya ordenadas
+// sus mejores MaxLimit coincidencias, así que una consulta sólo recorre len(q) nodos
+const MaxLimit = 20
+
+// Index es un trie inmutable sobre los textos normalizados de las sugerencias
+type Index struct {
+	version     string
+	suggestions []models.Suggestion
+	root        *node
+}
+
+type node struct {
+	children map[byte]*node
+	hits     []hit
+}
+
+// hit apunta a una sugerencia; leading indica que la coincidencia empieza al inicio del texto
+type hit struct {
+	id      int
+	leading bool
+}
+
+// NewIndex construye el índice. Cada sugerencia se indexa desde el inicio de cada una de sus
+// palabras, para que "corolla" encuentre "Toyota Corolla", y también sin separadores, para
+// que "cx5" encuentre "Mazda CX-5".
+func NewIndex(version string, suggestions []models.Suggestion) *Index {
+	idx := &Index{version: version, suggestions: suggestions, root: &node{}}
+
+	for id, s := range suggestions {
+		words := textnorm.Tokenize(s.Text)
+		for i := range words {
+			h := hit{id: id, leading: i == 0}
+			idx.insert(strings.Join(words[i:], " "), h)
+			if len(words)-i > 1 {
+				idx.insert(strings.Join(words[i:], ""), h)
+			}
+		}
+	}
+
+	idx.root.finish(idx)
+	return idx
+}
+
+// Version es la versión del catálogo con la que se construyó el índice
+func (idx *Index) Version() string {
+	return idx.version
+}
+
+// Len es el número de sugerencias indexadas
+func (idx *Index) Len() int {
+	return len(idx.suggestions)
+}
+
+// Lookup devuelve hasta limit sugerencias que empiezan con el texto de q en alguna de sus
+// palabras, primero las que coinciden desde el inicio y luego las de más vehículos
+func (idx *Index) Lookup(q string, limit int) []models.Suggestion {
+	words := textnorm.Tokenize(q)
+	if len(words) == 0 {
+		return []models.Suggestion{}
+	}
+	if limit <= 0 || limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	var hits []hit
+	for _, key := range []string{strings.Join(words, " "), strings.Join(words, "")} {
+		if n := idx.find(key); n != nil {
+			hits = append(hits, n.hits...)
+		}
+	}
+	hits = idx.rank(hits)
+
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	results := make([]models.Suggestion, len(hits))
+	for i, h := range hits {
+		results[i] = idx.suggestions[h.id]
+	}
+	return results
+}
+
+func (idx *Index) insert(key string, h hit) {
+	n := idx.root
+	for i := 0; i < len(key); i++ {
+		if n.children == nil {
+			n.children = make(map[byte]*node)
+		}
+		child, ok := n.children[key[i]]
+		if !ok {
+			child = &node{}
+			n.children[key[i]] = child
+		}
+		child.hits = append(child.hits, h)
+		n = child
+	}
+}
+
+func (idx *Index) find(key string) *node {
+	n := idx.root
+	for i := 0; i < len(key) && n != nil; i++ {
+		n = n.children[key[i]]
+	}
+	return n
+}
+
+// finish ordena y recorta las coincidencias de cada nodo del subárbol
+func (n *node) finish(idx *Index) {
+	n.hits = idx.rank(n.hits)
+	if len(n.hits) > MaxLimit {
+		n.hits = append([]hit(nil), n.hits[:MaxLimit]...)
+	}
+	for _, child := range n.children {
+		child.finish(idx)
+	}
+}
+
+// rank deja una coincidencia por sugerencia, conservando la del inicio si la hay, y las
+// ordena: desde el inicio, más vehículos, marca antes que modelo antes que característica
+func (idx *Index) rank(hits []hit) []hit {
+	best := make(map[int]int, len(hits))
+	ranked := make([]hit, 0, len(hits))
+	for _, h := range hits {
+		if i, ok := best[h.id]; ok {
+			ranked[i].leading = ranked[i].leading || h.leading
+			continue
+		}
+		best[h.id] = len(ranked)
+		ranked = append(ranked, h)
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.leading != b.leading {
+			return a.leading
+		}
+		sa, sb := idx.suggestions[a.id], idx.suggestions[b.id]
+		if sa.Count != sb.Count {
+			return sa.Count > sb.Count
+		}
+		if typeRank[sa.Type] != typeRank[sb.Type] {
+			return typeRank[sa.Type] < typeRank[sb.Type]
+		}
+		return sa.Text < sb.Text
+	})
+	return ranked
+}
+
+var typeRank = map[string]int{
+	models.SuggestionBrand:   0,
+	models.SuggestionModel:   1,
+	models.SuggestionFeature: 2,
+}
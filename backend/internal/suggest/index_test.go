@@ -0,0 +1,62 @@
+package suggest
+
+import (
+	"testing"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+func testIndex() *Index {
+	return NewIndex("v1", []models.Suggestion{
+		{Type: models.SuggestionBrand, Text: "Toyota", Count: 2},
+		{Type: models.SuggestionModel, Text: "Toyota Corolla", Count: 1},
+		{Type: models.SuggestionModel, Text: "Mazda CX-5", Count: 1},
+		{Type: models.SuggestionFeature, Text: "Cámara de reversa", Count: 3},
+		{Type: models.SuggestionFeature, Text: "Puertas corredizas", Count: 1},
+	})
+}
+
+func texts(suggestions []models.Suggestion) []string {
+	out := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		out[i] = s.Text
+	}
+	return out
+}
+
+func TestLookup(t *testing.T) {
+	idx := testIndex()
+	tests := []struct {
+		q    string
+		want []string
+	}{
+		// Las coincidencias desde el inicio van antes que las de una palabra interior
+		{"cor", []string{"Toyota Corolla", "Puertas corredizas"}},
+		{"to", []string{"Toyota", "Toyota Corolla"}},
+		{"toyota cor", []string{"Toyota Corolla"}},
+		{"cx5", []string{"Mazda CX-5"}},
+		{"CX-5", []string{"Mazda CX-5"}},
+		{"camara", []string{"Cámara de reversa"}},
+		{"", []string{}},
+		{"zzz", []string{}},
+	}
+	for _, tt := range tests {
+		got := texts(idx.Lookup(tt.q, 10))
+		if len(got) != len(tt.want) {
+			t.Errorf("Lookup(%q) = %q, want %q", tt.q, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Lookup(%q) = %q, want %q", tt.q, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestLookupLimit(t *testing.T) {
+	if got := testIndex().Lookup("t", 1); len(got) != 1 || got[0].Text != "Toyota" {
+		t.Errorf("Lookup(t, 1) = %q, want [Toyota]", texts(got))
+	}
+}
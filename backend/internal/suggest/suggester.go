@@ -0,0 +1,91 @@
+package suggest
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vehiculos/backend/internal/database"
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// refreshTimeout limita la revisión de versión y la reconstrucción en segundo plano
+const refreshTimeout = 10 * time.Second
+
+// Suggester responde el autocompletado desde el índice vigente. Cada interval compara en
+// segundo plano la versión del catálogo y, si cambió, reconstruye el índice y lo reemplaza de
+// forma atómica; mientras tanto las consultas siguen usando el anterior.
+type Suggester struct {
+	store    database.SuggestionStore
+	interval time.Duration
+
+	index      atomic.Pointer[Index]
+	checkedAt  atomic.Int64 // UnixNano de la última revisión de versión
+	refreshing atomic.Bool
+	refresh    sync.Mutex
+}
+
+// NewSuggester crea un Suggester sin índice; se construye con Refresh o con la primera consulta
+func NewSuggester(store database.SuggestionStore, interval time.Duration) *Suggester {
+	return &Suggester{store: store, interval: interval}
+}
+
+// Refresh reconstruye el índice si la versión del catálogo cambió desde la última
+// construcción. Devuelve el índice vigente después de la revisión.
+func (s *Suggester) Refresh(ctx context.Context) (*Index, error) {
+	s.refresh.Lock()
+	defer s.refresh.Unlock()
+
+	s.checkedAt.Store(time.Now().UnixNano())
+	version, err := s.store.CatalogVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current := s.index.Load()
+	if current != nil && current.Version() == version {
+		return current, nil
+	}
+
+	suggestions, err := s.store.ListSuggestions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	idx := NewIndex(version, suggestions)
+	s.index.Store(idx)
+	return idx, nil
+}
+
+// Suggest devuelve hasta limit sugerencias para q. Sólo la primera consulta espera a que se
+// construya el índice; las demás nunca esperan a una reconstrucción.
+func (s *Suggester) Suggest(ctx context.Context, q string, limit int) ([]models.Suggestion, error) {
+	idx := s.index.Load()
+	if idx == nil {
+		var err error
+		if idx, err = s.Refresh(ctx); err != nil {
+			return nil, err
+		}
+	} else if time.Since(time.Unix(0, s.checkedAt.Load())) >= s.interval {
+		s.refreshInBackground()
+	}
+
+	return idx.Lookup(q, limit), nil
+}
+
+// refreshInBackground lanza una sola revisión de versión a la vez
+func (s *Suggester) refreshInBackground() {
+	if !s.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer s.refreshing.Store(false)
+
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		defer cancel()
+		if _, err := s.Refresh(ctx); err != nil {
+			log.Printf("Error al reconstruir el índice de autocompletado: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,116 @@
+// Package synonyms reescribe la búsqueda libre con un diccionario de sinónimos del dominio
+// automotriz: "troca" se convierte en el tipo Pickup, "estándar" en la transmisión Manual y
+// "quemacocos" se expande a sus variantes de texto. El diccionario se puede recargar sin
+// reiniciar el servidor.
+package synonyms
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/vehiculos/backend/internal/textnorm"
+)
+
+// Rule asocia uno o más términos (de una o varias palabras) con restricciones del filtro por
+// nombre de catálogo o con frases alternativas para la búsqueda de texto
+type Rule struct {
+	Terms         []string `json:"terms"`
+	Brands        []string `json:"brands,omitempty"`
+	VehicleTypes  []string `json:"vehicle_types,omitempty"`
+	FuelTypes     []string `json:"fuel_types,omitempty"`
+	Transmissions []string `json:"transmissions,omitempty"`
+	Expand        []string `json:"expand,omitempty"`
+}
+
+// structured indica si la regla se traduce en filtros en lugar de texto
+func (r Rule) structured() bool {
+	return len(r.Brands)+len(r.VehicleTypes)+len(r.FuelTypes)+len(r.Transmissions) > 0
+}
+
+// Dictionary es un diccionario validado; es inmutable y se reemplaza completo al recargar
+type Dictionary struct {
+	rules   []Rule
+	entries []entry
+}
+
+// entry es un término normalizado que apunta a su regla
+type entry struct {
+	words []string
+	rule  int
+}
+
+type dictionaryFile struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Load lee el diccionario de un archivo JSON
+func Load(path string) (*Dictionary, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse lee y valida un diccionario JSON con la forma {"rules": [...]}. Cada regla necesita
+// al menos un término y una sola clase de efecto (filtros o expansión), y un término no
+// puede aparecer en dos reglas.
+func Parse(r io.Reader) (*Dictionary, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var file dictionaryFile
+	if err := dec.Decode(&file); err != nil {
+		return nil, fmt.Errorf("diccionario de sinónimos inválido: %v", err)
+	}
+
+	d := &Dictionary{rules: file.Rules}
+	seen := make(map[string]int)
+	for i, rule := range file.Rules {
+		switch {
+		case len(rule.Terms) == 0:
+			return nil, fmt.Errorf("regla %d: se requiere al menos un término", i+1)
+		case !rule.structured() && len(rule.Expand) == 0:
+			return nil, fmt.Errorf("regla %d: se requieren filtros o expand", i+1)
+		case rule.structured() && len(rule.Expand) > 0:
+			return nil, fmt.Errorf("regla %d: use filtros o expand, no ambos", i+1)
+		}
+
+		for _, term := range rule.Terms {
+			words := normalizeWords(term)
+			if len(words) == 0 {
+				return nil, fmt.Errorf("regla %d: término vacío", i+1)
+			}
+			key := strings.Join(words, " ")
+			if prev, ok := seen[key]; ok {
+				return nil, fmt.Errorf("regla %d: el término %q ya está en la regla %d", i+1, term, prev+1)
+			}
+			seen[key] = i
+			d.entries = append(d.entries, entry{words: words, rule: i})
+		}
+	}
+	return d, nil
+}
+
+// Len es el número de reglas
+func (d *Dictionary) Len() int {
+	if d == nil {
+		return 0
+	}
+	return len(d.rules)
+}
+
+// normalizeWords separa el texto en palabras normalizadas, omitiendo las que quedan vacías
+func normalizeWords(text string) []string {
+	var words []string
+	for _, w := range strings.Fields(text) {
+		if w = textnorm.Word(w); w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
@@ -0,0 +1,310 @@
+package synonyms
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/vehiculos/backend/internal/database"
+	"github.com/vehiculos/backend/internal/models"
+	"github.com/vehiculos/backend/internal/textnorm"
+)
+
+// Rewrite describe un término de la búsqueda que el diccionario reemplazó
+type Rewrite struct {
+	Term          string   `json:"term"`
+	Brands        []string `json:"brands,omitempty"`
+	VehicleTypes  []string `json:"vehicle_types,omitempty"`
+	FuelTypes     []string `json:"fuel_types,omitempty"`
+	Transmissions []string `json:"transmissions,omitempty"`
+	Expand        []string `json:"expand,omitempty"`
+}
+
+// Rewriter aplica el diccionario vigente a los filtros de búsqueda. El diccionario se cambia
+// de forma atómica, así que las búsquedas en curso no se bloquean durante una recarga.
+type Rewriter struct {
+	path    string
+	catalog database.CatalogStore
+	dict    atomic.Pointer[Dictionary]
+	reload  sync.Mutex
+}
+
+// NewRewriter crea un reescritor sin reglas; Reload carga el diccionario de path
+func NewRewriter(path string, catalog database.CatalogStore) *Rewriter {
+	return &Rewriter{path: path, catalog: catalog}
+}
+
+// Reload vuelve a leer el diccionario y lo valida contra el catálogo. Si falla, se conserva
+// el diccionario anterior. Devuelve el número de reglas cargadas.
+func (r *Rewriter) Reload(ctx context.Context) (int, error) {
+	r.reload.Lock()
+	defer r.reload.Unlock()
+
+	d, err := Load(r.path)
+	if err != nil {
+		return 0, err
+	}
+
+	res := newResolver(ctx, r.catalog)
+	var unknown []string
+	for _, rule := range d.rules {
+		missing, err := res.missing(rule)
+		if err != nil {
+			return 0, err
+		}
+		unknown = append(unknown, missing...)
+	}
+	if len(unknown) > 0 {
+		return 0, fmt.Errorf("el diccionario de sinónimos usa nombres que no existen en el catálogo: %s", strings.Join(unknown, ", "))
+	}
+
+	r.dict.Store(d)
+	return d.Len(), nil
+}
+
+// Rules es el número de reglas del diccionario vigente
+func (r *Rewriter) Rules() int {
+	return r.dict.Load().Len()
+}
+
+// Rewrite reemplaza en filter.Query los términos del diccionario por filtros de catálogo o por
+// grupos de QueryExpansions, y deja en Query el resto del texto. Una regla de filtros no se
+// aplica si el usuario ya eligió esa dimensión, para no cambiar su selección; en ese caso el
+// término se busca como texto.
+func (r *Rewriter) Rewrite(ctx context.Context, filter *models.SearchFilter) ([]Rewrite, error) {
+	d := r.dict.Load()
+	if d.Len() == 0 || strings.TrimSpace(filter.Query) == "" {
+		return nil, nil
+	}
+
+	explicit := map[string]bool{
+		"brands":        len(filter.BrandID) > 0,
+		"vehicle_types": len(filter.TypeID) > 0,
+		"fuel_types":    len(filter.FuelTypeID) > 0,
+		"transmissions": len(filter.TransmissionID) > 0,
+	}
+
+	fields := strings.Fields(filter.Query)
+	words := make([]string, len(fields))
+	for i, f := range fields {
+		words[i] = textnorm.Word(f)
+	}
+
+	res := newResolver(ctx, r.catalog)
+	var residual []string
+	var rewrites []Rewrite
+
+	for i := 0; i < len(fields); {
+		e, ok := d.match(words, i)
+		if !ok {
+			residual = append(residual, fields[i])
+			i++
+			continue
+		}
+		n := len(e.words)
+		term := strings.Join(fields[i:i+n], " ")
+		rule := d.rules[e.rule]
+
+		applied := true
+		if rule.structured() {
+			var err error
+			if applied, err = res.apply(rule, filter, explicit); err != nil {
+				return nil, err
+			}
+		} else {
+			filter.QueryExpansions = append(filter.QueryExpansions, append([]string{term}, rule.Expand...))
+		}
+
+		if applied {
+			rewrites = append(rewrites, Rewrite{
+				Term:          term,
+				Brands:        rule.Brands,
+				VehicleTypes:  rule.VehicleTypes,
+				FuelTypes:     rule.FuelTypes,
+				Transmissions: rule.Transmissions,
+				Expand:        rule.Expand,
+			})
+		} else {
+			residual = append(residual, fields[i:i+n]...)
+		}
+		i += n
+	}
+
+	filter.Query = strings.Join(residual, " ")
+	return rewrites, nil
+}
+
+// match busca el término más largo del diccionario que empieza en words[i]
+func (d *Dictionary) match(words []string, i int) (entry, bool) {
+	var best entry
+	found := false
+	for _, e := range d.entries {
+		if len(e.words) > len(words)-i || (found && len(e.words) <= len(best.words)) {
+			continue
+		}
+		matched := true
+		for j, w := range e.words {
+			if words[i+j] != w {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			best, found = e, true
+		}
+	}
+	return best, found
+}
+
+// resolver traduce nombres de catálogo a IDs; cada catálogo se consulta a lo más una vez
+type resolver struct {
+	ctx     context.Context
+	catalog database.CatalogStore
+	indexes map[string]map[string]int
+}
+
+func newResolver(ctx context.Context, catalog database.CatalogStore) *resolver {
+	return &resolver{ctx: ctx, catalog: catalog, indexes: make(map[string]map[string]int)}
+}
+
+// index devuelve el catálogo kind indexado por nombre normalizado
+func (res *resolver) index(kind string) (map[string]int, error) {
+	if idx, ok := res.indexes[kind]; ok {
+		return idx, nil
+	}
+
+	idx := make(map[string]int)
+	add := func(id int, name string) { idx[strings.Join(normalizeWords(name), " ")] = id }
+	switch kind {
+	case "brands":
+		items, err := res.catalog.GetBrands(res.ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range items {
+			add(b.ID, b.Name)
+		}
+	case "vehicle_types":
+		items, err := res.catalog.GetVehicleTypes(res.ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range items {
+			add(t.ID, t.Name)
+		}
+	case "fuel_types":
+		items, err := res.catalog.GetFuelTypes(res.ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range items {
+			add(f.ID, f.Name)
+		}
+	case "transmissions":
+		items, err := res.catalog.GetTransmissions(res.ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range items {
+			add(t.ID, t.Name)
+		}
+	}
+
+	res.indexes[kind] = idx
+	return idx, nil
+}
+
+// dimensions lista las dimensiones de una regla con el slice del filtro que les corresponde
+func dimensions(rule Rule, filter *models.SearchFilter) []struct {
+	kind  string
+	names []string
+	ids   *[]int
+} {
+	return []struct {
+		kind  string
+		names []string
+		ids   *[]int
+	}{
+		{"brands", rule.Brands, &filter.BrandID},
+		{"vehicle_types", rule.VehicleTypes, &filter.TypeID},
+		{"fuel_types", rule.FuelTypes, &filter.FuelTypeID},
+		{"transmissions", rule.Transmissions, &filter.TransmissionID},
+	}
+}
+
+// apply agrega los IDs de la regla al filtro. No aplica nada si alguna de sus dimensiones
+// ya la eligió el usuario o si ningún nombre existe en el catálogo.
+func (res *resolver) apply(rule Rule, filter *models.SearchFilter, explicit map[string]bool) (bool, error) {
+	dims := dimensions(rule, filter)
+	for _, dim := range dims {
+		if len(dim.names) > 0 && explicit[dim.kind] {
+			return false, nil
+		}
+	}
+
+	resolved := make([][]int, len(dims))
+	total := 0
+	for i, dim := range dims {
+		if len(dim.names) == 0 {
+			continue
+		}
+		idx, err := res.index(dim.kind)
+		if err != nil {
+			return false, err
+		}
+		for _, name := range dim.names {
+			if id, ok := idx[strings.Join(normalizeWords(name), " ")]; ok {
+				resolved[i] = append(resolved[i], id)
+				total++
+			}
+		}
+	}
+	if total == 0 {
+		return false, nil
+	}
+
+	for i, dim := range dims {
+		*dim.ids = mergeIDs(*dim.ids, resolved[i])
+	}
+	return true, nil
+}
+
+// missing lista los nombres de la regla que no existen en el catálogo
+func (res *resolver) missing(rule Rule) ([]string, error) {
+	var missing []string
+	for _, dim := range dimensions(rule, &models.SearchFilter{}) {
+		if len(dim.names) == 0 {
+			continue
+		}
+		idx, err := res.index(dim.kind)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range dim.names {
+			if _, ok := idx[strings.Join(normalizeWords(name), " ")]; !ok {
+				missing = append(missing, fmt.Sprintf("%s %q", dim.kind, name))
+			}
+		}
+	}
+	return missing, nil
+}
+
+// mergeIDs une dos listas de IDs sin repetidos y ordenadas
+func mergeIDs(a, b []int) []int {
+	if len(b) == 0 {
+		return a
+	}
+	seen := make(map[int]bool, len(a)+len(b))
+	var merged []int
+	for _, id := range append(append([]int(nil), a...), b...) {
+		if !seen[id] {
+			seen[id] = true
+			merged = append(merged, id)
+		}
+	}
+	sort.Ints(merged)
+	return merged
+}
@@ -0,0 +1,206 @@
+package synonyms
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vehiculos/backend/internal/models"
+)
+
+// fakeCatalog implementa database.CatalogStore con catálogos fijos
+type fakeCatalog struct{}
+
+func (fakeCatalog) GetBrands(context.Context) ([]models.Brand, error) {
+	return []models.Brand{{ID: 1, Name: "Toyota"}, {ID: 2, Name: "Volkswagen"}}, nil
+}
+
+func (fakeCatalog) GetVehicleTypes(context.Context) ([]models.VehicleType, error) {
+	return []models.VehicleType{{ID: 1, Name: "Sedan"}, {ID: 2, Name: "SUV"}, {ID: 3, Name: "Pickup"}}, nil
+}
+
+func (fakeCatalog) GetFuelTypes(context.Context) ([]models.FuelType, error) {
+	return []models.FuelType{{ID: 1, Name: "Gasolina"}, {ID: 3, Name: "Híbrido"}, {ID: 4, Name: "Híbrido Enchufable"}, {ID: 5, Name: "Eléctrico"}}, nil
+}
+
+func (fakeCatalog) GetTransmissions(context.Context) ([]models.Transmission, error) {
+	return []models.Transmission{{ID: 1, Name: "Manual"}, {ID: 2, Name: "Automática"}}, nil
+}
+
+const testDictionary = `{
+  "rules": [
+    { "terms": ["camioneta"], "vehicle_types": ["SUV", "Pickup"] },
+    { "terms": ["troca", "pick-up"], "vehicle_types": ["Pickup"] },
+    { "terms": ["electrico", "electrica"], "fuel_types": ["Eléctrico"] },
+    { "terms": ["hibrido"], "fuel_types": ["Híbrido", "Híbrido Enchufable"] },
+    { "terms": ["hibrido enchufable"], "fuel_types": ["Híbrido Enchufable"] },
+    { "terms": ["estandar"], "transmissions": ["Manual"] },
+    { "terms": ["vw"], "brands": ["Volkswagen"] },
+    { "terms": ["quemacocos"], "expand": ["techo solar", "sunroof"] }
+  ]
+}`
+
+func writeDictionary(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "synonyms.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newTestRewriter(t *testing.T) *Rewriter {
+	t.Helper()
+	r := NewRewriter(writeDictionary(t, testDictionary), fakeCatalog{})
+	if _, err := r.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	return r
+}
+
+func TestRewrite(t *testing.T) {
+	tests := []struct {
+		name       string
+		filter     models.SearchFilter
+		want       models.SearchFilter
+		rewriteLen int
+	}{
+		{
+			name:       "término a tipo",
+			filter:     models.SearchFilter{Query: "troca"},
+			want:       models.SearchFilter{TypeID: []int{3}},
+			rewriteLen: 1,
+		},
+		{
+			name:       "varios términos y texto residual",
+			filter:     models.SearchFilter{Query: "camioneta eléctrica Toyota"},
+			want:       models.SearchFilter{Query: "Toyota", TypeID: []int{2, 3}, FuelTypeID: []int{5}},
+			rewriteLen: 2,
+		},
+		{
+			name:       "gana el término más largo",
+			filter:     models.SearchFilter{Query: "RAV4 híbrido enchufable"},
+			want:       models.SearchFilter{Query: "RAV4", FuelTypeID: []int{4}},
+			rewriteLen: 1,
+		},
+		{
+			name:       "sin acentos, mayúsculas ni puntuación",
+			filter:     models.SearchFilter{Query: "ESTÁNDAR, VW"},
+			want:       models.SearchFilter{BrandID: []int{2}, TransmissionID: []int{1}},
+			rewriteLen: 2,
+		},
+		{
+			name:       "guion interior del término",
+			filter:     models.SearchFilter{Query: "pick-up"},
+			want:       models.SearchFilter{TypeID: []int{3}},
+			rewriteLen: 1,
+		},
+		{
+			name:       "dimensión elegida por el usuario se respeta",
+			filter:     models.SearchFilter{Query: "troca", TypeID: []int{1}},
+			want:       models.SearchFilter{Query: "troca", TypeID: []int{1}},
+			rewriteLen: 0,
+		},
+		{
+			name:       "otra dimensión elegida no impide la regla",
+			filter:     models.SearchFilter{Query: "troca", FuelTypeID: []int{1}},
+			want:       models.SearchFilter{TypeID: []int{3}, FuelTypeID: []int{1}},
+			rewriteLen: 1,
+		},
+		{
+			name:       "IDs de la regla se unen a los existentes",
+			filter:     models.SearchFilter{Query: "vw", BrandID: nil, TypeID: []int{2}},
+			want:       models.SearchFilter{BrandID: []int{2}, TypeID: []int{2}},
+			rewriteLen: 1,
+		},
+		{
+			name:   "expansión de texto",
+			filter: models.SearchFilter{Query: "CX-5 quemacocos"},
+			want: models.SearchFilter{
+				Query:           "CX-5",
+				QueryExpansions: [][]string{{"quemacocos", "techo solar", "sunroof"}},
+			},
+			rewriteLen: 1,
+		},
+		{
+			name:       "sin términos del diccionario",
+			filter:     models.SearchFilter{Query: "corolla 2024"},
+			want:       models.SearchFilter{Query: "corolla 2024"},
+			rewriteLen: 0,
+		},
+	}
+
+	r := newTestRewriter(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter
+			rewrites, err := r.Rewrite(context.Background(), &filter)
+			if err != nil {
+				t.Fatalf("Rewrite: %v", err)
+			}
+			if !reflect.DeepEqual(filter, tt.want) {
+				t.Errorf("filtro = %+v, want %+v", filter, tt.want)
+			}
+			if len(rewrites) != tt.rewriteLen {
+				t.Errorf("rewrites = %+v, want %d", rewrites, tt.rewriteLen)
+			}
+		})
+	}
+}
+
+func TestRewriteReportsTerms(t *testing.T) {
+	filter := models.SearchFilter{Query: "Camioneta quemacocos"}
+	rewrites, err := newTestRewriter(t).Rewrite(context.Background(), &filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Rewrite{
+		{Term: "Camioneta", VehicleTypes: []string{"SUV", "Pickup"}},
+		{Term: "quemacocos", Expand: []string{"techo solar", "sunroof"}},
+	}
+	if !reflect.DeepEqual(rewrites, want) {
+		t.Errorf("rewrites = %+v, want %+v", rewrites, want)
+	}
+}
+
+func TestReloadKeepsPreviousDictionaryOnError(t *testing.T) {
+	path := writeDictionary(t, testDictionary)
+	r := NewRewriter(path, fakeCatalog{})
+	if _, err := r.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	bad := strings.Replace(testDictionary, `["Pickup"]`, `["Tanque"]`, 1)
+	if err := os.WriteFile(path, []byte(bad), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Reload(context.Background()); err == nil || !strings.Contains(err.Error(), "Tanque") {
+		t.Fatalf("Reload con nombre desconocido: err = %v", err)
+	}
+	if got := r.Rules(); got != 8 {
+		t.Errorf("Rules() = %d tras recarga fallida, want 8", got)
+	}
+
+	filter := models.SearchFilter{Query: "troca"}
+	if _, err := r.Rewrite(context.Background(), &filter); err != nil || !reflect.DeepEqual(filter.TypeID, []int{3}) {
+		t.Errorf("el diccionario anterior no sigue vigente: %+v, %v", filter, err)
+	}
+}
+
+func TestParseRejectsInvalidRules(t *testing.T) {
+	tests := map[string]string{
+		"sin términos":      `{"rules":[{"terms":[],"brands":["Toyota"]}]}`,
+		"sin efecto":        `{"rules":[{"terms":["x"]}]}`,
+		"filtros y expand":  `{"rules":[{"terms":["x"],"brands":["Toyota"],"expand":["y"]}]}`,
+		"término repetido":  `{"rules":[{"terms":["Troca"],"brands":["Toyota"]},{"terms":["troca"],"expand":["y"]}]}`,
+		"campo desconocido": `{"rules":[{"terms":["x"],"marcas":["Toyota"]}]}`,
+	}
+	for name, content := range tests {
+		if _, err := Parse(strings.NewReader(content)); err == nil {
+			t.Errorf("%s: Parse aceptó un diccionario inválido", name)
+		}
+	}
+}
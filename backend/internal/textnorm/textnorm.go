@@ -0,0 +1,38 @@
+// Package textnorm normaliza texto en español para compararlo sin distinguir mayúsculas ni
+// acentos. La búsqueda en memoria, el autocompletado, los sinónimos y la importación lo usan
+// para plegar el texto de la misma forma.
+package textnorm
+
+import (
+	"strings"
+	"unicode"
+)
+
+var accentFolder = strings.NewReplacer(
+	"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n",
+)
+
+// Fold deja el texto en minúsculas y sin acentos
+func Fold(text string) string {
+	return accentFolder.Replace(strings.ToLower(text))
+}
+
+// Tokenize separa el texto plegado en palabras de letras y dígitos; cualquier otro carácter
+// es separador, así que "CX-5" produce "cx" y "5"
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(Fold(text), func(r rune) bool {
+		return !isWordRune(r)
+	})
+}
+
+// Word pliega una palabra y le quita la puntuación de los extremos, conservando la interior
+// ("Pick-Up," produce "pick-up")
+func Word(word string) string {
+	return strings.TrimFunc(Fold(word), func(r rune) bool {
+		return !isWordRune(r)
+	})
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,51 @@
+package textnorm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	tests := map[string]string{
+		"Híbrido":      "hibrido",
+		"CAMIÓN":       "camion",
+		"Pingüino Año": "pinguino ano",
+		"sin acentos":  "sin acentos",
+	}
+	for in, want := range tests {
+		if got := Fold(in); got != want {
+			t.Errorf("Fold(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Mazda CX-5", []string{"mazda", "cx", "5"}},
+		{"  Cámara   de reversa ", []string{"camara", "de", "reversa"}},
+		{"Mercedes-Benz, Clase C", []string{"mercedes", "benz", "clase", "c"}},
+		{"--", []string{}},
+	}
+	for _, tt := range tests {
+		if got := Tokenize(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWord(t *testing.T) {
+	tests := map[string]string{
+		"Pick-Up,":    "pick-up",
+		"¿Eléctrico?": "electrico",
+		"4x4":         "4x4",
+		"...":         "",
+	}
+	for in, want := range tests {
+		if got := Word(in); got != want {
+			t.Errorf("Word(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
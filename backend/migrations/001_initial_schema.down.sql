@@ -0,0 +1,13 @@
+-- Revierte el esquema inicial; elimina todos los datos del catálogo y de analytics
+DROP TABLE IF EXISTS user_preferences;
+DROP TABLE IF EXISTS user_searches;
+DROP TABLE IF EXISTS vehicle_specs;
+DROP TABLE IF EXISTS vehicle_images;
+DROP TABLE IF EXISTS vehicle_features;
+DROP TABLE IF EXISTS vehicles;
+DROP TABLE IF EXISTS transmissions;
+DROP TABLE IF EXISTS fuel_types;
+DROP TABLE IF EXISTS vehicle_types;
+DROP TABLE IF EXISTS brands;
+
+DROP FUNCTION IF EXISTS update_updated_at_column();
@@ -1,5 +1,6 @@
 -- Crear base de datos si no existe
 -- CREATE DATABASE vehiculos_db;
+-- Este script es idempotente: se puede aplicar sobre un esquema creado a mano con psql.
 
 -- Tabla de marcas
 CREATE TABLE IF NOT EXISTS brands (
@@ -105,21 +106,21 @@ CREATE TABLE IF NOT EXISTS user_preferences (
 );
 
 -- Índices para optimizar búsquedas
-CREATE INDEX idx_vehicles_brand_id ON vehicles(brand_id);
-CREATE INDEX idx_vehicles_type_id ON vehicles(type_id);
-CREATE INDEX idx_vehicles_fuel_type_id ON vehicles(fuel_type_id);
-CREATE INDEX idx_vehicles_transmission_id ON vehicles(transmission_id);
-CREATE INDEX idx_vehicles_year ON vehicles(year);
-CREATE INDEX idx_vehicles_price ON vehicles(price);
-CREATE INDEX idx_vehicles_model ON vehicles(model);
-CREATE INDEX idx_vehicle_features_vehicle_id ON vehicle_features(vehicle_id);
-CREATE INDEX idx_vehicle_images_vehicle_id ON vehicle_images(vehicle_id);
-CREATE INDEX idx_vehicle_specs_vehicle_id ON vehicle_specs(vehicle_id);
-CREATE INDEX idx_user_searches_session_id ON user_searches(session_id);
-CREATE INDEX idx_user_searches_created_at ON user_searches(created_at);
+CREATE INDEX IF NOT EXISTS idx_vehicles_brand_id ON vehicles(brand_id);
+CREATE INDEX IF NOT EXISTS idx_vehicles_type_id ON vehicles(type_id);
+CREATE INDEX IF NOT EXISTS idx_vehicles_fuel_type_id ON vehicles(fuel_type_id);
+CREATE INDEX IF NOT EXISTS idx_vehicles_transmission_id ON vehicles(transmission_id);
+CREATE INDEX IF NOT EXISTS idx_vehicles_year ON vehicles(year);
+CREATE INDEX IF NOT EXISTS idx_vehicles_price ON vehicles(price);
+CREATE INDEX IF NOT EXISTS idx_vehicles_model ON vehicles(model);
+CREATE INDEX IF NOT EXISTS idx_vehicle_features_vehicle_id ON vehicle_features(vehicle_id);
+CREATE INDEX IF NOT EXISTS idx_vehicle_images_vehicle_id ON vehicle_images(vehicle_id);
+CREATE INDEX IF NOT EXISTS idx_vehicle_specs_vehicle_id ON vehicle_specs(vehicle_id);
+CREATE INDEX IF NOT EXISTS idx_user_searches_session_id ON user_searches(session_id);
+CREATE INDEX IF NOT EXISTS idx_user_searches_created_at ON user_searches(created_at);
 
 -- Índice de texto completo para búsquedas
-CREATE INDEX idx_vehicles_search ON vehicles USING gin(
+CREATE INDEX IF NOT EXISTS idx_vehicles_search ON vehicles USING gin(
     to_tsvector('spanish', 
         coalesce(model, '') || ' ' || 
         coalesce(description, '')
@@ -136,9 +137,11 @@ END;
 $$ LANGUAGE plpgsql;
 
 -- Triggers para actualizar updated_at
+DROP TRIGGER IF EXISTS update_brands_updated_at ON brands;
 CREATE TRIGGER update_brands_updated_at BEFORE UPDATE ON brands
     FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
+DROP TRIGGER IF EXISTS update_vehicles_updated_at ON vehicles;
 CREATE TRIGGER update_vehicles_updated_at BEFORE UPDATE ON vehicles
     FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
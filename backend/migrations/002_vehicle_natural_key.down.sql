@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_vehicles_natural_key;
@@ -0,0 +1,4 @@
+-- Un vehículo se identifica por marca, modelo (sin distinguir mayúsculas) y año;
+-- la importación masiva usa este índice para hacer upsert
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vehicles_natural_key
+    ON vehicles (brand_id, lower(model), year);
@@ -0,0 +1,4 @@
+-- Los filtros features_all/features_any y el listado de características comparan sin
+-- distinguir mayúsculas
+CREATE INDEX IF NOT EXISTS idx_vehicle_features_lower
+    ON vehicle_features (lower(feature), vehicle_id);
@@ -0,0 +1,22 @@
+-- Vuelve a la búsqueda de texto sobre modelo y descripción; las extensiones se conservan
+-- porque otras bases de datos del servidor pueden usarlas
+CREATE INDEX IF NOT EXISTS idx_vehicles_search ON vehicles USING gin(
+    to_tsvector('spanish', 
+        coalesce(model, '') || ' ' || 
+        coalesce(description, '')
+    )
+);
+
+DROP TRIGGER IF EXISTS touch_vehicle_types_search_text ON vehicle_types;
+DROP TRIGGER IF EXISTS touch_brands_search_text ON brands;
+DROP TRIGGER IF EXISTS touch_vehicle_features_search_text ON vehicle_features;
+DROP TRIGGER IF EXISTS refresh_vehicles_search_text ON vehicles;
+DROP FUNCTION IF EXISTS touch_vehicle_search_text();
+DROP FUNCTION IF EXISTS refresh_vehicle_search_text();
+DROP FUNCTION IF EXISTS vehicle_search_text(INTEGER, INTEGER, INTEGER, TEXT, TEXT);
+
+DROP INDEX IF EXISTS idx_vehicles_search_text_trgm;
+DROP INDEX IF EXISTS idx_vehicles_search_text_fts;
+ALTER TABLE vehicles DROP COLUMN IF EXISTS search_text;
+
+DROP FUNCTION IF EXISTS immutable_unaccent(TEXT);
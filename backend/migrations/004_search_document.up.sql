@@ -0,0 +1,80 @@
+-- Búsqueda sin acentos y tolerante a errores de escritura. vehicles.search_text reúne marca,
+-- modelo (también sin guiones ni espacios, para que "cx5" encuentre "CX-5"), tipo,
+-- características y descripción en minúsculas y sin acentos; se indexa para texto completo
+-- y para similitud de trigramas.
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- unaccent es STABLE porque depende del diccionario; fijarlo permite usarlo en índices
+CREATE OR REPLACE FUNCTION immutable_unaccent(value TEXT)
+RETURNS TEXT AS $$
+    SELECT public.unaccent('public.unaccent', value);
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+ALTER TABLE vehicles ADD COLUMN IF NOT EXISTS search_text TEXT NOT NULL DEFAULT '';
+
+CREATE OR REPLACE FUNCTION vehicle_search_text(p_vehicle_id INTEGER, p_brand_id INTEGER, p_type_id INTEGER, p_model TEXT, p_description TEXT)
+RETURNS TEXT AS $$
+    SELECT lower(immutable_unaccent(concat_ws(' ',
+        (SELECT name FROM brands WHERE id = p_brand_id),
+        p_model,
+        regexp_replace(p_model, '[^[:alnum:]]+', '', 'g'),
+        (SELECT name FROM vehicle_types WHERE id = p_type_id),
+        (SELECT string_agg(feature, ' ' ORDER BY feature) FROM vehicle_features WHERE vehicle_id = p_vehicle_id),
+        p_description
+    )));
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION refresh_vehicle_search_text()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_text = vehicle_search_text(NEW.id, NEW.brand_id, NEW.type_id, NEW.model, NEW.description);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Cualquier actualización del vehículo recalcula su documento
+DROP TRIGGER IF EXISTS refresh_vehicles_search_text ON vehicles;
+CREATE TRIGGER refresh_vehicles_search_text BEFORE INSERT OR UPDATE ON vehicles
+    FOR EACH ROW EXECUTE FUNCTION refresh_vehicle_search_text();
+
+-- Los cambios en características y en los nombres de marcas y tipos tocan los vehículos
+-- afectados para que el trigger anterior recalcule su documento
+CREATE OR REPLACE FUNCTION touch_vehicle_search_text()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'vehicle_features' THEN
+        UPDATE vehicles SET search_text = ''
+        WHERE id = CASE WHEN TG_OP = 'DELETE' THEN OLD.vehicle_id ELSE NEW.vehicle_id END;
+        IF TG_OP = 'UPDATE' AND OLD.vehicle_id <> NEW.vehicle_id THEN
+            UPDATE vehicles SET search_text = '' WHERE id = OLD.vehicle_id;
+        END IF;
+    ELSIF TG_TABLE_NAME = 'brands' THEN
+        UPDATE vehicles SET search_text = '' WHERE brand_id = NEW.id;
+    ELSE
+        UPDATE vehicles SET search_text = '' WHERE type_id = NEW.id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS touch_vehicle_features_search_text ON vehicle_features;
+CREATE TRIGGER touch_vehicle_features_search_text AFTER INSERT OR UPDATE OR DELETE ON vehicle_features
+    FOR EACH ROW EXECUTE FUNCTION touch_vehicle_search_text();
+
+DROP TRIGGER IF EXISTS touch_brands_search_text ON brands;
+CREATE TRIGGER touch_brands_search_text AFTER UPDATE OF name ON brands
+    FOR EACH ROW EXECUTE FUNCTION touch_vehicle_search_text();
+
+DROP TRIGGER IF EXISTS touch_vehicle_types_search_text ON vehicle_types;
+CREATE TRIGGER touch_vehicle_types_search_text AFTER UPDATE OF name ON vehicle_types
+    FOR EACH ROW EXECUTE FUNCTION touch_vehicle_search_text();
+
+-- Calcular el documento de los vehículos existentes
+UPDATE vehicles SET search_text = '';
+
+CREATE INDEX IF NOT EXISTS idx_vehicles_search_text_fts ON vehicles USING gin(to_tsvector('spanish', search_text));
+CREATE INDEX IF NOT EXISTS idx_vehicles_search_text_trgm ON vehicles USING gin(search_text gin_trgm_ops);
+
+-- El índice anterior de texto completo queda sin uso
+DROP INDEX IF EXISTS idx_vehicles_search;
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS bump_transmissions_catalog_version ON transmissions;
+DROP TRIGGER IF EXISTS bump_fuel_types_catalog_version ON fuel_types;
+DROP TRIGGER IF EXISTS bump_vehicle_types_catalog_version ON vehicle_types;
+DROP TRIGGER IF EXISTS bump_brands_catalog_version ON brands;
+DROP TRIGGER IF EXISTS bump_vehicle_features_catalog_version ON vehicle_features;
+DROP TRIGGER IF EXISTS bump_vehicles_catalog_version ON vehicles;
+DROP FUNCTION IF EXISTS bump_catalog_version();
+DROP TABLE IF EXISTS catalog_version;
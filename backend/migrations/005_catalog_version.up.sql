@@ -0,0 +1,44 @@
+-- Versión del catálogo para invalidar los índices en memoria (autocompletado y puntajes).
+-- MAX(updated_at) no sirve: NOW() es el inicio de la transacción y una escritura que
+-- confirma después de otra más reciente queda con una marca menor. El contador se
+-- incrementa en la misma transacción que el cambio y el bloqueo de la fila ordena a los
+-- escritores, así que cada confirmación deja una versión mayor que la anterior.
+CREATE TABLE IF NOT EXISTS catalog_version (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    version BIGINT NOT NULL DEFAULT 0
+);
+
+INSERT INTO catalog_version (id) VALUES (TRUE) ON CONFLICT (id) DO NOTHING;
+
+CREATE OR REPLACE FUNCTION bump_catalog_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE catalog_version SET version = version + 1;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Un incremento por sentencia: una importación masiva no multiplica las escrituras
+DROP TRIGGER IF EXISTS bump_vehicles_catalog_version ON vehicles;
+CREATE TRIGGER bump_vehicles_catalog_version AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON vehicles
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_catalog_version();
+
+DROP TRIGGER IF EXISTS bump_vehicle_features_catalog_version ON vehicle_features;
+CREATE TRIGGER bump_vehicle_features_catalog_version AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON vehicle_features
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_catalog_version();
+
+DROP TRIGGER IF EXISTS bump_brands_catalog_version ON brands;
+CREATE TRIGGER bump_brands_catalog_version AFTER UPDATE OR DELETE ON brands
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_catalog_version();
+
+DROP TRIGGER IF EXISTS bump_vehicle_types_catalog_version ON vehicle_types;
+CREATE TRIGGER bump_vehicle_types_catalog_version AFTER UPDATE OR DELETE ON vehicle_types
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_catalog_version();
+
+DROP TRIGGER IF EXISTS bump_fuel_types_catalog_version ON fuel_types;
+CREATE TRIGGER bump_fuel_types_catalog_version AFTER UPDATE OR DELETE ON fuel_types
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_catalog_version();
+
+DROP TRIGGER IF EXISTS bump_transmissions_catalog_version ON transmissions;
+CREATE TRIGGER bump_transmissions_catalog_version AFTER UPDATE OR DELETE ON transmissions
+    FOR EACH STATEMENT EXECUTE FUNCTION bump_catalog_version();
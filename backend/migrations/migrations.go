@@ -0,0 +1,8 @@
+// Package migrations embebe los scripts SQL del esquema en el binario.
+// Cada versión tiene un archivo NNN_nombre.up.sql y, opcionalmente, NNN_nombre.down.sql.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
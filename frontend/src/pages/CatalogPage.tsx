@@ -90,7 +90,11 @@ export default function CatalogPage() {
     { value: 'price_asc', label: 'Menor Precio' },
     { value: 'price_desc', label: 'Mayor Precio' },
     { value: 'year_desc', label: 'Año Más Reciente' },
-    { value: 'fuel_economy_desc', label: 'Mayor Eficiencia' }
+    { value: 'fuel_economy_desc', label: 'Mayor Eficiencia' },
+    { value: 'horsepower_desc', label: 'Mayor Potencia' },
+    { value: 'safety_rating_desc', label: 'Más Seguro' },
+    { value: 'price_per_hp_asc', label: 'Mejor Precio por HP' },
+    { value: 'brand_asc,model_asc', label: 'Marca (A-Z)' }
   ]
 
   return (
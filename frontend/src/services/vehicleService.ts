@@ -1,5 +1,5 @@
 import axios from 'axios'
-import { Vehicle, VehicleResponse, SearchFilter } from '../types/vehicle'
+import { Vehicle, VehicleResponse, SearchFilter, CompareResponse, FeatureCount, Suggestion, SimilarVehicle, SimilarConstraint, RecommendationProfile, RecommendationResponse } from '../types/vehicle'
 
 const API_URL = import.meta.env.VITE_API_URL || '/api'
 
@@ -34,6 +34,23 @@ export const getVehicleById = async (id: number): Promise<Vehicle> => {
   }
 }
 
+// Get vehicles similar to the given one, optionally constrained (cheaper, same type, ...)
+export const getSimilarVehicles = async (
+  id: number,
+  constraints: SimilarConstraint[] = [],
+  limit = 6
+): Promise<SimilarVehicle[]> => {
+  try {
+    const params = new URLSearchParams({ limit: limit.toString() })
+    if (constraints.length > 0) params.append('constraints', constraints.join(','))
+    const response = await api.get(`/vehicles/${id}/similar?${params.toString()}`)
+    return response.data.similar
+  } catch (error) {
+    console.error('Error fetching similar vehicles:', error)
+    throw error
+  }
+}
+
 // Search vehicles with filters
 export const searchVehicles = async (filters: SearchFilter): Promise<VehicleResponse> => {
   try {
@@ -43,20 +60,32 @@ export const searchVehicles = async (filters: SearchFilter): Promise<VehicleResp
     if (filters.page) params.append('page', filters.page.toString())
     if (filters.limit) params.append('limit', filters.limit.toString())
     if (filters.sortBy) params.append('sort_by', filters.sortBy)
+    if (filters.cursor) params.append('cursor', filters.cursor)
+    if (filters.count) params.append('count', filters.count)
     
     if (filters.priceMin && filters.priceMin > 0) params.append('price_min', filters.priceMin.toString())
     if (filters.priceMax && filters.priceMax > 0) params.append('price_max', filters.priceMax.toString())
     if (filters.yearMin && filters.yearMin > 0) params.append('year_min', filters.yearMin.toString())
     if (filters.yearMax && filters.yearMax > 0) params.append('year_max', filters.yearMax.toString())
     if (filters.doorsMin && filters.doorsMin > 0) params.append('doors_min', filters.doorsMin.toString())
+    if (filters.doorsMax && filters.doorsMax > 0) params.append('doors_max', filters.doorsMax.toString())
     if (filters.seatsMin && filters.seatsMin > 0) params.append('seats_min', filters.seatsMin.toString())
+    if (filters.seatsMax && filters.seatsMax > 0) params.append('seats_max', filters.seatsMax.toString())
     if (filters.fuelEconomyMin && filters.fuelEconomyMin > 0) params.append('fuel_economy_min', filters.fuelEconomyMin.toString())
+    if (filters.fuelEconomyMax && filters.fuelEconomyMax > 0) params.append('fuel_economy_max', filters.fuelEconomyMax.toString())
+
+    Object.entries(filters.ranges ?? {}).forEach(([attribute, range]) => {
+      if (range?.min && range.min > 0) params.append(`${attribute}_min`, range.min.toString())
+      if (range?.max && range.max > 0) params.append(`${attribute}_max`, range.max.toString())
+    })
     
     // Add array parameters
     filters.brandIds?.forEach(id => params.append('brand_id', id.toString()))
     filters.typeIds?.forEach(id => params.append('type_id', id.toString()))
     filters.fuelTypeIds?.forEach(id => params.append('fuel_type_id', id.toString()))
     filters.transmissionIds?.forEach(id => params.append('transmission_id', id.toString()))
+    filters.featuresAll?.forEach(feature => params.append('features_all', feature))
+    filters.featuresAny?.forEach(feature => params.append('features_any', feature))
     
     const response = await api.get(`/vehicles/search?${params.toString()}`)
     return response.data
@@ -77,6 +106,35 @@ export const getFilters = async () => {
   }
 }
 
+// Get features with vehicle counts for the current filters
+export const getFeatures = async (filters: SearchFilter = {}): Promise<FeatureCount[]> => {
+  try {
+    const params = new URLSearchParams()
+    filters.featuresAll?.forEach(feature => params.append('features_all', feature))
+    filters.featuresAny?.forEach(feature => params.append('features_any', feature))
+    filters.brandIds?.forEach(id => params.append('brand_id', id.toString()))
+    filters.typeIds?.forEach(id => params.append('type_id', id.toString()))
+    const response = await api.get(`/features?${params.toString()}`)
+    return response.data
+  } catch (error) {
+    console.error('Error fetching features:', error)
+    throw error
+  }
+}
+
+// Get autocomplete suggestions for the search box
+export const getSuggestions = async (q: string, limit = 8): Promise<Suggestion[]> => {
+  try {
+    const response = await api.get('/search/suggest', {
+      params: { q, limit }
+    })
+    return response.data.suggestions
+  } catch (error) {
+    console.error('Error fetching suggestions:', error)
+    throw error
+  }
+}
+
 // Get brands
 export const getBrands = async () => {
   try {
@@ -122,7 +180,7 @@ export const getTransmissions = async () => {
 }
 
 // Compare vehicles
-export const compareVehicles = async (vehicleIds: number[]): Promise<Vehicle[]> => {
+export const compareVehicles = async (vehicleIds: number[]): Promise<CompareResponse> => {
   try {
     const response = await api.post('/vehicles/compare', { vehicleIds })
     return response.data
@@ -132,6 +190,17 @@ export const compareVehicles = async (vehicleIds: number[]): Promise<Vehicle[]>
   }
 }
 
+// Get vehicles ranked for a needs profile, with a per-criterion score breakdown
+export const getRecommendations = async (profile: RecommendationProfile): Promise<RecommendationResponse> => {
+  try {
+    const response = await api.post('/recommendations', profile)
+    return response.data
+  } catch (error) {
+    console.error('Error fetching recommendations:', error)
+    throw error
+  }
+}
+
 // Save user search (for analytics and AI training)
 export const saveUserSearch = async (searchData: {
   query?: string
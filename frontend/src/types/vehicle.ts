@@ -45,11 +45,35 @@ export interface Vehicle {
   imageUrl?: string
   description?: string
   features?: string[]
+  images?: VehicleImage[]
   safetyRating?: number
+  relevance?: number // Puntaje de búsqueda, sólo con query
   createdAt?: string
   updatedAt?: string
 }
 
+export interface VehicleImage {
+  id: number
+  vehicle_id: number
+  image_url: string
+  image_type: 'exterior' | 'interior' | 'engine'
+  display_order: number
+}
+
+// Atributos con filtro de rango genérico (<atributo>_min / <atributo>_max)
+export type RangeAttribute =
+  | 'horsepower'
+  | 'torque'
+  | 'engine_size'
+  | 'tank_capacity'
+  | 'cargo_space'
+  | 'safety_rating'
+
+export interface NumericRange {
+  min?: number
+  max?: number
+}
+
 export interface SearchFilter {
   query?: string
   brandIds?: number[]
@@ -61,17 +85,146 @@ export interface SearchFilter {
   yearMin?: number
   yearMax?: number
   doorsMin?: number
+  doorsMax?: number
   seatsMin?: number
+  seatsMax?: number
   fuelEconomyMin?: number
+  fuelEconomyMax?: number
+  ranges?: Partial<Record<RangeAttribute, NumericRange>>
+  featuresAll?: string[]
+  featuresAny?: string[]
   sortBy?: string
   page?: number
   limit?: number
+  cursor?: string
+  count?: 'exact' | 'estimate' | 'none'
 }
 
 export interface VehicleResponse {
   vehicles: Vehicle[]
-  total: number
+  total: number | null
+  total_estimated?: boolean
+  next_cursor: string | null
+  prev_cursor: string | null
   page: number
   limit: number
   filters?: SearchFilter
-}
\ No newline at end of file
+  facets?: SearchFacets
+  rewrites?: QueryRewrite[]
+}
+
+// Regla del diccionario de sinónimos aplicada a la búsqueda
+export interface QueryRewrite {
+  term: string
+  brands?: string[]
+  vehicle_types?: string[]
+  fuel_types?: string[]
+  transmissions?: string[]
+  expand?: string[]
+}
+
+export interface FacetCount {
+  id: number
+  name: string
+  count: number
+}
+
+export interface FeatureCount {
+  feature: string
+  count: number
+}
+
+export interface Suggestion {
+  type: 'brand' | 'model' | 'feature'
+  text: string
+  brand_id?: number
+  brand?: string
+  model?: string
+  feature?: string
+  year_min?: number
+  year_max?: number
+  count: number
+}
+
+export interface HistogramBucket {
+  min: number
+  max: number
+  count: number
+}
+
+export interface SearchFacets {
+  brands: FacetCount[]
+  vehicle_types: FacetCount[]
+  fuel_types: FacetCount[]
+  transmissions: FacetCount[]
+  price: HistogramBucket[]
+  year: HistogramBucket[]
+}
+
+export type SimilarConstraint = 'cheaper' | 'same_type' | 'same_brand' | 'other_brand' | 'same_fuel_type'
+
+export interface SimilarityReason {
+  attribute: string
+  similarity: number
+  message: string
+}
+
+export interface SimilarVehicle {
+  vehicle: Vehicle
+  score: number
+  reasons: SimilarityReason[]
+}
+
+export type RecommendationCriterion = 'price' | 'fuel_economy' | 'safety' | 'space' | 'performance'
+
+export interface RecommendationProfile {
+  budget?: { min?: number; max?: number }
+  passengers?: number
+  usage?: Array<'city' | 'highway' | 'work' | 'family' | 'mixed'>
+  priorities?: Partial<Record<RecommendationCriterion, number>>
+  limit?: number
+}
+
+export interface CriterionScore {
+  criterion: RecommendationCriterion
+  weight: number
+  score: number
+  contribution: number
+  detail: string
+}
+
+export interface Recommendation {
+  vehicle: Vehicle
+  score: number
+  breakdown: CriterionScore[]
+}
+
+export interface RecommendationResponse {
+  weights: Record<RecommendationCriterion, number>
+  candidates: number
+  recommendations: Recommendation[]
+}
+
+export interface AttributeValue {
+  vehicle_id: number
+  value: number
+  best: boolean
+  worst: boolean
+}
+
+export interface AttributeComparison {
+  attribute: string
+  higher_is_better: boolean
+  values: AttributeValue[]
+}
+
+export interface CompareResponse {
+  vehicles: Vehicle[]
+  comparison: {
+    attributes: AttributeComparison[]
+    features: {
+      shared: string[]
+      unique: Record<number, string[]>
+    }
+  }
+}